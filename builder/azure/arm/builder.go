@@ -182,21 +182,8 @@ func (b *Builder) Run(ctx context.Context, ui packersdk.Ui, hook packersdk.Hook)
 		}
 		// SIG requires that replication regions include the region in which the Managed Image resides
 		managedImageLocation := normalizeAzureRegion(b.stateBag.Get(constants.ArmLocation).(string))
-		foundMandatoryReplicationRegion := false
-		var normalizedReplicationRegions []string
-		for _, region := range b.config.SharedGalleryDestination.SigDestinationReplicationRegions {
-			// change region to lower-case and strip spaces
-			normalizedRegion := normalizeAzureRegion(region)
-			normalizedReplicationRegions = append(normalizedReplicationRegions, normalizedRegion)
-			if strings.EqualFold(normalizedRegion, managedImageLocation) {
-				foundMandatoryReplicationRegion = true
-				continue
-			}
-		}
-		if foundMandatoryReplicationRegion == false {
-			b.config.SharedGalleryDestination.SigDestinationReplicationRegions = append(normalizedReplicationRegions, managedImageLocation)
-		}
-		b.stateBag.Put(constants.ArmManagedImageSharedGalleryReplicationRegions, b.config.SharedGalleryDestination.SigDestinationReplicationRegions)
+		b.stateBag.Put(constants.ArmManagedImageSharedGalleryTargetRegions,
+			sigTargetRegions(b.config.SharedGalleryDestination, managedImageLocation))
 	}
 
 	var steps []multistep.Step
@@ -435,6 +422,7 @@ func (b *Builder) configureStateBag(stateBag multistep.StateBag) {
 		stateBag.Put(constants.ArmManagedImageSharedGalleryImageVersionEndOfLifeDate, b.config.SharedGalleryImageVersionEndOfLifeDate)
 		stateBag.Put(constants.ArmManagedImageSharedGalleryImageVersionReplicaCount, b.config.SharedGalleryImageVersionReplicaCount)
 		stateBag.Put(constants.ArmManagedImageSharedGalleryImageVersionExcludeFromLatest, b.config.SharedGalleryImageVersionExcludeFromLatest)
+		stateBag.Put(constants.ArmManagedImageSharedGalleryStorageAccountType, b.config.SharedGalleryDestination.SigDestinationStorageAccountType)
 	}
 }
 
@@ -475,3 +463,32 @@ func getObjectIdFromToken(ui packersdk.Ui, token *adal.ServicePrincipalToken) st
 func normalizeAzureRegion(name string) string {
 	return strings.ToLower(strings.Replace(name, " ", "", -1))
 }
+
+// sigTargetRegions returns the regions to replicate the shared image gallery
+// image version to, from both replication_regions and target_regions. The
+// region of the managed image is added when missing, as SIG requires it.
+func sigTargetRegions(destination SharedImageGalleryDestination, managedImageLocation string) []TargetRegion {
+	var targetRegions []TargetRegion
+	found := make(map[string]bool)
+	for _, region := range destination.SigDestinationTargetRegions {
+		// change region to lower-case and strip spaces
+		region.Name = normalizeAzureRegion(region.Name)
+		if found[region.Name] {
+			continue
+		}
+		found[region.Name] = true
+		targetRegions = append(targetRegions, region)
+	}
+	for _, name := range destination.SigDestinationReplicationRegions {
+		name = normalizeAzureRegion(name)
+		if found[name] {
+			continue
+		}
+		found[name] = true
+		targetRegions = append(targetRegions, TargetRegion{Name: name})
+	}
+	if !found[managedImageLocation] {
+		targetRegions = append(targetRegions, TargetRegion{Name: managedImageLocation})
+	}
+	return targetRegions
+}
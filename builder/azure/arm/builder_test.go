@@ -65,3 +65,27 @@ func TestStateBagShouldPoluateExpectedTags(t *testing.T) {
 	}
 
 }
+
+func TestSigTargetRegionsShouldIncludeManagedImageLocation(t *testing.T) {
+	destination := SharedImageGalleryDestination{
+		SigDestinationReplicationRegions: []string{"West US", "East US"},
+		SigDestinationTargetRegions: []TargetRegion{
+			{Name: "East US", ReplicaCount: 3, StorageAccountType: "Standard_ZRS"},
+		},
+	}
+
+	targetRegions := sigTargetRegions(destination, "westeurope")
+	expected := []TargetRegion{
+		{Name: "eastus", ReplicaCount: 3, StorageAccountType: "Standard_ZRS"},
+		{Name: "westus"},
+		{Name: "westeurope"},
+	}
+	if len(targetRegions) != len(expected) {
+		t.Fatalf("Expected target regions %v, but got %v", expected, targetRegions)
+	}
+	for i := range expected {
+		if targetRegions[i] != expected[i] {
+			t.Fatalf("Expected target regions %v, but got %v", expected, targetRegions)
+		}
+	}
+}
@@ -1,5 +1,5 @@
 //go:generate packer-sdc struct-markdown
-//go:generate packer-sdc mapstructure-to-hcl2 -type Config,SharedImageGallery,SharedImageGalleryDestination,PlanInformation
+//go:generate packer-sdc mapstructure-to-hcl2 -type Config,SharedImageGallery,SharedImageGalleryDestination,TargetRegion,PlanInformation
 
 package arm
 
@@ -68,6 +68,10 @@ var (
 	reResourceNamePrefix   = regexp.MustCompile(validResourceNamePrefix)
 )
 
+// sigStorageAccountTypes are the storage account types of the replicas of a
+// shared image gallery image version.
+var sigStorageAccountTypes = []string{"Standard_LRS", "Standard_ZRS"}
+
 type PlanInformation struct {
 	PlanName          string `mapstructure:"plan_name"`
 	PlanProduct       string `mapstructure:"plan_product"`
@@ -95,6 +99,26 @@ type SharedImageGalleryDestination struct {
 	SigDestinationImageName          string   `mapstructure:"image_name"`
 	SigDestinationImageVersion       string   `mapstructure:"image_version"`
 	SigDestinationReplicationRegions []string `mapstructure:"replication_regions"`
+	// The regions to replicate the image version to, with their own replica
+	// count and storage account type. This can be used instead of, or along
+	// with, `replication_regions`.
+	SigDestinationTargetRegions []TargetRegion `mapstructure:"target_regions"`
+	// The storage account type of the replicas of the image version, in the
+	// regions that don't set their own: `Standard_LRS` or `Standard_ZRS`.
+	// This defaults to the storage account type chosen by Azure.
+	SigDestinationStorageAccountType string `mapstructure:"storage_account_type"`
+}
+
+// TargetRegion describes a region where the shared image should be replicated
+type TargetRegion struct {
+	// Name of the Azure region
+	Name string `mapstructure:"name" required:"true"`
+	// Number of replicas in this region. This defaults to
+	// `shared_image_gallery_replica_count`.
+	ReplicaCount int32 `mapstructure:"replicas"`
+	// Storage account type: Standard_LRS or Standard_ZRS. This defaults to
+	// the `storage_account_type` of the destination.
+	StorageAccountType string `mapstructure:"storage_account_type"`
 }
 
 type Config struct {
@@ -1007,8 +1031,22 @@ func assertRequiredParametersSet(c *Config, errs *packersdk.MultiError) {
 		if c.SharedGalleryDestination.SigDestinationImageVersion == "" {
 			errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("An image_version must be specified for shared_image_gallery_destination"))
 		}
-		if len(c.SharedGalleryDestination.SigDestinationReplicationRegions) == 0 {
-			errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("A list of replication_regions must be specified for shared_image_gallery_destination"))
+		if len(c.SharedGalleryDestination.SigDestinationReplicationRegions) == 0 && len(c.SharedGalleryDestination.SigDestinationTargetRegions) == 0 {
+			errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("A list of replication_regions or target_regions must be specified for shared_image_gallery_destination"))
+		}
+		if !isValidSigStorageAccountType(c.SharedGalleryDestination.SigDestinationStorageAccountType) {
+			errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("The storage_account_type of shared_image_gallery_destination must be one of %s", strings.Join(sigStorageAccountTypes, ", ")))
+		}
+		for i, region := range c.SharedGalleryDestination.SigDestinationTargetRegions {
+			if region.Name == "" {
+				errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("A name must be specified for target_regions[%d] of shared_image_gallery_destination", i))
+			}
+			if region.ReplicaCount < 0 || region.ReplicaCount > constants.SharedImageGalleryImageVersionDefaultMaxReplicaCount {
+				errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("The replicas of target_regions[%d] of shared_image_gallery_destination must be between 1 and %d", i, constants.SharedImageGalleryImageVersionDefaultMaxReplicaCount))
+			}
+			if !isValidSigStorageAccountType(region.StorageAccountType) {
+				errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("The storage_account_type of target_regions[%d] of shared_image_gallery_destination must be one of %s", i, strings.Join(sigStorageAccountTypes, ", ")))
+			}
 		}
 		if c.SharedGalleryDestination.SigDestinationSubscription == "" {
 			c.SharedGalleryDestination.SigDestinationSubscription = c.ClientConfig.SubscriptionID
@@ -1157,6 +1195,18 @@ func assertResourceGroupName(rgn, setting string) (bool, error) {
 	return true, nil
 }
 
+func isValidSigStorageAccountType(storageAccountType string) bool {
+	if storageAccountType == "" {
+		return true
+	}
+	for _, t := range sigStorageAccountTypes {
+		if t == storageAccountType {
+			return true
+		}
+	}
+	return false
+}
+
 func isValidAzureName(re *regexp.Regexp, rgn string) bool {
 	return re.Match([]byte(rgn)) &&
 		!strings.HasSuffix(rgn, ".") &&
@@ -1980,6 +1980,83 @@ func TestConfigShouldRejectSharedImageGalleryWithVhdTarget(t *testing.T) {
 	}
 }
 
+func TestConfigShouldValidateSharedImageGalleryDestinationTargetRegions(t *testing.T) {
+	config := map[string]interface{}{
+		"image_publisher":                   "ignore",
+		"image_offer":                       "ignore",
+		"image_sku":                         "ignore",
+		"location":                          "ignore",
+		"subscription_id":                   "ignore",
+		"communicator":                      "none",
+		"managed_image_resource_group_name": "ignore",
+		"managed_image_name":                "ignore",
+		"os_type":                           constants.Target_Linux,
+		"shared_image_gallery_destination": map[string]interface{}{
+			"resource_group":       "ignore",
+			"gallery_name":         "ignore",
+			"image_name":           "ignore",
+			"image_version":        "1.0.0",
+			"storage_account_type": "Standard_ZRS",
+			"target_regions": []map[string]interface{}{
+				{"name": "westus", "replicas": 2, "storage_account_type": "Standard_LRS"},
+				{"name": "eastus"},
+			},
+		},
+	}
+
+	var c Config
+	_, err := c.Prepare(config, getPackerConfiguration())
+	if err != nil {
+		t.Fatalf("expected config to accept target_regions without replication_regions: %s", err)
+	}
+	if len(c.SharedGalleryDestination.SigDestinationTargetRegions) != 2 ||
+		c.SharedGalleryDestination.SigDestinationTargetRegions[0].ReplicaCount != 2 {
+		t.Fatalf("bad target_regions: %v", c.SharedGalleryDestination.SigDestinationTargetRegions)
+	}
+
+	badTargetRegions := [][]map[string]interface{}{
+		{{"replicas": 1}},
+		{{"name": "westus", "replicas": 11}},
+		{{"name": "westus", "storage_account_type": "Premium_LRS"}},
+	}
+	for _, targetRegions := range badTargetRegions {
+		config["shared_image_gallery_destination"].(map[string]interface{})["target_regions"] = targetRegions
+		var c Config
+		_, err := c.Prepare(config, getPackerConfiguration())
+		if err == nil {
+			t.Fatalf("expected config to reject target_regions %v", targetRegions)
+		}
+	}
+}
+
+func TestConfigShouldRejectSharedImageGalleryDestinationStorageAccountType(t *testing.T) {
+	config := map[string]interface{}{
+		"image_publisher":                   "ignore",
+		"image_offer":                       "ignore",
+		"image_sku":                         "ignore",
+		"location":                          "ignore",
+		"subscription_id":                   "ignore",
+		"communicator":                      "none",
+		"managed_image_resource_group_name": "ignore",
+		"managed_image_name":                "ignore",
+		"os_type":                           constants.Target_Linux,
+		"shared_image_gallery_destination": map[string]interface{}{
+			"resource_group":       "ignore",
+			"gallery_name":         "ignore",
+			"image_name":           "ignore",
+			"image_version":        "1.0.0",
+			"replication_regions":  []string{"westus"},
+			"storage_account_type": "Premium_LRS",
+		},
+	}
+
+	var c Config
+	_, err := c.Prepare(config, getPackerConfiguration())
+	if err == nil {
+		t.Fatal("expected config to reject a storage_account_type of Premium_LRS")
+	}
+}
+
 func Test_GivenZoneNotSupportingResiliency_ConfigValidate_ShouldWarn(t *testing.T) {
 	builderValues := getArmBuilderConfiguration()
 	builderValues["managed_image_zone_resilient"] = "true"
@@ -3,6 +3,8 @@ package arm
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-03-01/compute"
 	"github.com/Azure/go-autorest/autorest/date"
@@ -13,7 +15,7 @@ import (
 
 type StepPublishToSharedImageGallery struct {
 	client  *AzureClient
-	publish func(ctx context.Context, mdiID, miSigPubRg, miSIGalleryName, miSGImageName, miSGImageVersion string, miSigReplicationRegions []string, miSGImageVersionEndOfLifeDate string, miSGImageVersionExcludeFromLatest bool, miSigReplicaCount int32, location string, tags map[string]*string) (string, error)
+	publish func(ctx context.Context, mdiID, miSigPubRg, miSIGalleryName, miSGImageName, miSGImageVersion string, miSigTargetRegions []TargetRegion, miSigStorageAccountType string, miSGImageVersionEndOfLifeDate string, miSGImageVersionExcludeFromLatest bool, miSigReplicaCount int32, location string, tags map[string]*string) (string, error)
 	say     func(message string)
 	error   func(e error)
 	toSIG   func() bool
@@ -37,12 +39,19 @@ func NewStepPublishToSharedImageGallery(client *AzureClient, ui packersdk.Ui, co
 	return step
 }
 
-func (s *StepPublishToSharedImageGallery) publishToSig(ctx context.Context, mdiID string, miSigPubRg string, miSIGalleryName string, miSGImageName string, miSGImageVersion string, miSigReplicationRegions []string, miSGImageVersionEndOfLifeDate string, miSGImageVersionExcludeFromLatest bool, miSigReplicaCount int32, location string, tags map[string]*string) (string, error) {
+func (s *StepPublishToSharedImageGallery) publishToSig(ctx context.Context, mdiID string, miSigPubRg string, miSIGalleryName string, miSGImageName string, miSGImageVersion string, miSigTargetRegions []TargetRegion, miSigStorageAccountType string, miSGImageVersionEndOfLifeDate string, miSGImageVersionExcludeFromLatest bool, miSigReplicaCount int32, location string, tags map[string]*string) (string, error) {
 
-	replicationRegions := make([]compute.TargetRegion, len(miSigReplicationRegions))
-	for i, v := range miSigReplicationRegions {
-		regionName := v
+	replicationRegions := make([]compute.TargetRegion, len(miSigTargetRegions))
+	for i, v := range miSigTargetRegions {
+		regionName := v.Name
 		replicationRegions[i] = compute.TargetRegion{Name: &regionName}
+		if v.ReplicaCount > 0 {
+			replicaCount := v.ReplicaCount
+			replicationRegions[i].RegionalReplicaCount = &replicaCount
+		}
+		if v.StorageAccountType != "" {
+			replicationRegions[i].StorageAccountType = compute.StorageAccountType(v.StorageAccountType)
+		}
 	}
 
 	var endOfLifeDate *date.Time
@@ -66,10 +75,11 @@ func (s *StepPublishToSharedImageGallery) publishToSig(ctx context.Context, mdiI
 						ID: &mdiID,
 					},
 				},
-				TargetRegions:     &replicationRegions,
-				EndOfLifeDate:     endOfLifeDate,
-				ExcludeFromLatest: &miSGImageVersionExcludeFromLatest,
-				ReplicaCount:      &miSigReplicaCount,
+				TargetRegions:      &replicationRegions,
+				EndOfLifeDate:      endOfLifeDate,
+				ExcludeFromLatest:  &miSGImageVersionExcludeFromLatest,
+				ReplicaCount:       &miSigReplicaCount,
+				StorageAccountType: compute.StorageAccountType(miSigStorageAccountType),
 			},
 		},
 	}
@@ -81,7 +91,7 @@ func (s *StepPublishToSharedImageGallery) publishToSig(ctx context.Context, mdiI
 		return "", err
 	}
 
-	err = f.WaitForCompletionRef(ctx, s.client.GalleryImageVersionsClient.Client)
+	err = s.waitForReplication(ctx, f, miSigPubRg, miSIGalleryName, miSGImageName, miSGImageVersion)
 
 	if err != nil {
 		s.say(s.client.LastError.Error())
@@ -99,6 +109,64 @@ func (s *StepPublishToSharedImageGallery) publishToSig(ctx context.Context, mdiI
 	return *(createdSGImageVersion.ID), nil
 }
 
+// waitForReplication waits for the image version to be published, showing the
+// progress of its replication to each target region while it runs.
+func (s *StepPublishToSharedImageGallery) waitForReplication(ctx context.Context, f compute.GalleryImageVersionsCreateOrUpdateFuture, miSigPubRg, miSIGalleryName, miSGImageName, miSGImageVersion string) error {
+	client := s.client.GalleryImageVersionsClient
+	ctx, cancel := context.WithTimeout(ctx, client.PollingDuration)
+	defer cancel()
+
+	lastProgress := ""
+	for {
+		done, err := f.DoneWithContext(ctx, client)
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		imageVersion, err := client.Get(ctx, miSigPubRg, miSIGalleryName, miSGImageName, miSGImageVersion, compute.ReplicationStatusTypesReplicationStatus)
+		if err == nil && imageVersion.GalleryImageVersionProperties != nil {
+			progress := replicationProgress(imageVersion.GalleryImageVersionProperties.ReplicationStatus)
+			if progress != "" && progress != lastProgress {
+				s.say(fmt.Sprintf(" -> SIG replication progress : %s", progress))
+				lastProgress = progress
+			}
+		}
+
+		delay, ok := f.GetPollingDelay()
+		if !ok {
+			delay = client.PollingDelay
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// replicationProgress describes the progress of the replication of an image
+// version to each of its target regions.
+func replicationProgress(status *compute.ReplicationStatus) string {
+	if status == nil || status.Summary == nil {
+		return ""
+	}
+	var regions []string
+	for _, region := range *status.Summary {
+		if region.Region == nil {
+			continue
+		}
+		progress := fmt.Sprintf("%s %s", *region.Region, region.State)
+		if region.Progress != nil {
+			progress = fmt.Sprintf("%s %d%%", progress, *region.Progress)
+		}
+		regions = append(regions, progress)
+	}
+	return strings.Join(regions, ", ")
+}
+
 func (s *StepPublishToSharedImageGallery) Run(ctx context.Context, stateBag multistep.StateBag) multistep.StepAction {
 	if !s.toSIG() {
 		return multistep.ActionContinue
@@ -112,7 +180,8 @@ func (s *StepPublishToSharedImageGallery) Run(ctx context.Context, stateBag mult
 	miSIGalleryName := stateBag.Get(constants.ArmManagedImageSharedGalleryName).(string)
 	miSGImageName := stateBag.Get(constants.ArmManagedImageSharedGalleryImageName).(string)
 	miSGImageVersion := stateBag.Get(constants.ArmManagedImageSharedGalleryImageVersion).(string)
-	miSigReplicationRegions := stateBag.Get(constants.ArmManagedImageSharedGalleryReplicationRegions).([]string)
+	miSigTargetRegions := stateBag.Get(constants.ArmManagedImageSharedGalleryTargetRegions).([]TargetRegion)
+	miSigStorageAccountType, _ := stateBag.Get(constants.ArmManagedImageSharedGalleryStorageAccountType).(string)
 
 	tags := stateBag.Get(constants.ArmTags).(map[string]*string)
 	targetManagedImageResourceGroupName := stateBag.Get(constants.ArmManagedImageResourceGroupName).(string)
@@ -136,12 +205,13 @@ func (s *StepPublishToSharedImageGallery) Run(ctx context.Context, stateBag mult
 	s.say(fmt.Sprintf(" -> SIG gallery name                      : '%s'", miSIGalleryName))
 	s.say(fmt.Sprintf(" -> SIG image name                        : '%s'", miSGImageName))
 	s.say(fmt.Sprintf(" -> SIG image version                     : '%s'", miSGImageVersion))
-	s.say(fmt.Sprintf(" -> SIG replication regions               : '%v'", miSigReplicationRegions))
+	s.say(fmt.Sprintf(" -> SIG replication regions               : '%v'", sigTargetRegionNames(miSigTargetRegions)))
+	s.say(fmt.Sprintf(" -> SIG storage account type              : '%s'", miSigStorageAccountType))
 	s.say(fmt.Sprintf(" -> SIG image version endoflife date      : '%s'", miSGImageVersionEndOfLifeDate))
 	s.say(fmt.Sprintf(" -> SIG image version exclude from latest : '%t'", miSGImageVersionExcludeFromLatest))
 	s.say(fmt.Sprintf(" -> SIG replica count [1, 10]             : '%d'", miSigReplicaCount))
 
-	createdGalleryImageVersionID, err := s.publish(ctx, mdiID, miSigPubRg, miSIGalleryName, miSGImageName, miSGImageVersion, miSigReplicationRegions, miSGImageVersionEndOfLifeDate, miSGImageVersionExcludeFromLatest, miSigReplicaCount, location, tags)
+	createdGalleryImageVersionID, err := s.publish(ctx, mdiID, miSigPubRg, miSIGalleryName, miSGImageName, miSGImageVersion, miSigTargetRegions, miSigStorageAccountType, miSGImageVersionEndOfLifeDate, miSGImageVersionExcludeFromLatest, miSigReplicaCount, location, tags)
 
 	if err != nil {
 		stateBag.Put(constants.Error, err)
@@ -156,3 +226,11 @@ func (s *StepPublishToSharedImageGallery) Run(ctx context.Context, stateBag mult
 
 func (*StepPublishToSharedImageGallery) Cleanup(multistep.StateBag) {
 }
+
+func sigTargetRegionNames(targetRegions []TargetRegion) []string {
+	names := make([]string, len(targetRegions))
+	for i, region := range targetRegions {
+		names[i] = region.Name
+	}
+	return names
+}
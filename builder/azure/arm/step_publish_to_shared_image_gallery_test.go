@@ -4,13 +4,16 @@ import (
 	"context"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-03-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+
 	"github.com/hashicorp/packer-plugin-sdk/multistep"
 	"github.com/hashicorp/packer/builder/azure/common/constants"
 )
 
 func TestStepPublishToSharedImageGalleryShouldNotPublishForVhd(t *testing.T) {
 	var testSubject = &StepPublishToSharedImageGallery{
-		publish: func(context.Context, string, string, string, string, string, []string, string, bool, int32, string, map[string]*string) (string, error) {
+		publish: func(context.Context, string, string, string, string, string, []TargetRegion, string, string, bool, int32, string, map[string]*string) (string, error) {
 			return "test", nil
 		},
 		say:   func(message string) {},
@@ -31,7 +34,7 @@ func TestStepPublishToSharedImageGalleryShouldNotPublishForVhd(t *testing.T) {
 
 func TestStepPublishToSharedImageGalleryShouldPublishForManagedImageWithSig(t *testing.T) {
 	var testSubject = &StepPublishToSharedImageGallery{
-		publish: func(context.Context, string, string, string, string, string, []string, string, bool, int32, string, map[string]*string) (string, error) {
+		publish: func(context.Context, string, string, string, string, string, []TargetRegion, string, string, bool, int32, string, map[string]*string) (string, error) {
 			return "", nil
 		},
 		say:   func(message string) {},
@@ -50,6 +53,52 @@ func TestStepPublishToSharedImageGalleryShouldPublishForManagedImageWithSig(t *t
 	}
 }
 
+func TestStepPublishToSharedImageGalleryShouldPublishTargetRegions(t *testing.T) {
+	var actualTargetRegions []TargetRegion
+	var actualStorageAccountType string
+	var testSubject = &StepPublishToSharedImageGallery{
+		publish: func(ctx context.Context, mdiID, miSigPubRg, miSIGalleryName, miSGImageName, miSGImageVersion string, miSigTargetRegions []TargetRegion, miSigStorageAccountType string, miSGImageVersionEndOfLifeDate string, miSGImageVersionExcludeFromLatest bool, miSigReplicaCount int32, location string, tags map[string]*string) (string, error) {
+			actualTargetRegions = miSigTargetRegions
+			actualStorageAccountType = miSigStorageAccountType
+			return "", nil
+		},
+		say:   func(message string) {},
+		error: func(e error) {},
+		toSIG: func() bool { return true },
+	}
+
+	stateBag := createTestStateBagStepPublishToSharedImageGallery()
+	stateBag.Put(constants.ArmManagedImageSharedGalleryStorageAccountType, "Standard_LRS")
+	var result = testSubject.Run(context.Background(), stateBag)
+	if result != multistep.ActionContinue {
+		t.Fatalf("Expected the step to return 'ActionContinue', but got '%d'.", result)
+	}
+
+	if len(actualTargetRegions) != 2 || actualTargetRegions[1].ReplicaCount != 2 || actualTargetRegions[1].StorageAccountType != "Standard_ZRS" {
+		t.Fatalf("Expected the step to publish the target regions, but got '%v'.", actualTargetRegions)
+	}
+	if actualStorageAccountType != "Standard_LRS" {
+		t.Fatalf("Expected the step to publish with the storage account type 'Standard_LRS', but got '%s'.", actualStorageAccountType)
+	}
+}
+
+func TestReplicationProgress(t *testing.T) {
+	if progress := replicationProgress(nil); progress != "" {
+		t.Fatalf("Expected no progress without a replication status, but got '%s'.", progress)
+	}
+
+	status := &compute.ReplicationStatus{
+		Summary: &[]compute.RegionalReplicationStatus{
+			{Region: to.StringPtr("West US"), State: compute.ReplicationStateCompleted, Progress: to.Int32Ptr(100)},
+			{Region: to.StringPtr("East US"), State: compute.ReplicationStateReplicating, Progress: to.Int32Ptr(40)},
+		},
+	}
+	expected := "West US Completed 100%, East US Replicating 40%"
+	if progress := replicationProgress(status); progress != expected {
+		t.Fatalf("Expected the progress '%s', but got '%s'.", expected, progress)
+	}
+}
+
 func createTestStateBagStepPublishToSharedImageGallery() multistep.StateBag {
 	stateBag := new(multistep.BasicStateBag)
 
@@ -63,7 +112,7 @@ func createTestStateBagStepPublishToSharedImageGallery() multistep.StateBag {
 		"tag01": &value,
 	}
 	stateBag.Put(constants.ArmTags, tags)
-	stateBag.Put(constants.ArmManagedImageSharedGalleryReplicationRegions, []string{"ManagedImageSharedGalleryReplicationRegionA", "ManagedImageSharedGalleryReplicationRegionB"})
+	stateBag.Put(constants.ArmManagedImageSharedGalleryTargetRegions, []TargetRegion{{Name: "ManagedImageSharedGalleryReplicationRegionA"}, {Name: "ManagedImageSharedGalleryReplicationRegionB", ReplicaCount: 2, StorageAccountType: "Standard_ZRS"}})
 	stateBag.Put(constants.ArmManagedImageResourceGroupName, "Unit Test: ManagedImageResourceGroupName")
 	stateBag.Put(constants.ArmManagedImageName, "Unit Test: ManagedImageName")
 	stateBag.Put(constants.ArmManagedImageSubscription, "Unit Test: ManagedImageSubscription")
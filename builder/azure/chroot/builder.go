@@ -122,6 +122,14 @@ type Config struct {
 	// The shared image to create using this build.
 	SharedImageGalleryDestination SharedImageGalleryDestination `mapstructure:"shared_image_destination"`
 
+	// The purchase plan of the marketplace image the source is based on. Images
+	// created from the Marketplace with a plan **must** specify the plan
+	// whenever the image is deployed. The builder adds the `PlanInfo`,
+	// `PlanProduct`, `PlanPublisher` and `PlanPromotionCode` tags to the
+	// managed image to ensure this information is not lost, and verifies that
+	// the shared image of `shared_image_destination` has the same purchase plan.
+	PlanInfo PlanInformation `mapstructure:"plan_info"`
+
 	ctx interpolate.Context
 }
 
@@ -346,6 +354,10 @@ func (b *Builder) Prepare(raws ...interface{}) ([]string, []string, error) {
 		}
 	}
 
+	if e := b.config.PlanInfo.Validate("plan_info"); len(e) > 0 {
+		errs = packersdk.MultiErrorAppend(errs, e...)
+	}
+
 	if !azcommon.StringsContains(md.Keys, "shared_image_destination") && b.config.ImageResourceID == "" {
 		errs = packersdk.MultiErrorAppend(errs, errors.New("image_resource_id or shared_image_destination is required"))
 	}
@@ -492,6 +504,7 @@ func buildsteps(config Config, info *client.ComputeInfo) []multistep.Step {
 			&StepVerifySharedImageDestination{
 				Image:    config.SharedImageGalleryDestination,
 				Location: info.Location,
+				PlanInfo: config.PlanInfo,
 			},
 		)
 	}
@@ -600,6 +613,7 @@ func buildsteps(config Config, info *client.ComputeInfo) []multistep.Step {
 			OSDiskCacheType:          config.OSDiskCacheType,
 			OSDiskStorageAccountType: config.OSDiskStorageAccountType,
 			Location:                 info.Location,
+			Tags:                     config.PlanInfo.Tags(),
 		})
 	}
 	if hasValidSharedImage {
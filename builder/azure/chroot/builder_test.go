@@ -79,6 +79,36 @@ func TestBuilder_Prepare(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "marketplace image with plan info",
+			config: config{
+				"source":            "bitnami:rabbitmq:rabbitmq:latest",
+				"image_resource_id": "/subscriptions/789/resourceGroups/otherrgname/providers/Microsoft.Compute/images/MyRabbitMQImage",
+				"plan_info": config{
+					"plan_name":      "rabbitmq",
+					"plan_product":   "rabbitmq",
+					"plan_publisher": "bitnami",
+				},
+			},
+			validate: func(c Config) {
+				tags := c.PlanInfo.Tags()
+				if tags["PlanInfo"] == nil || *tags["PlanInfo"] != "rabbitmq" {
+					t.Errorf("Expected the PlanInfo tag to be %q, but got %v", "rabbitmq", tags)
+				}
+			},
+		},
+		{
+			name: "plan info with missing property",
+			config: config{
+				"source":            "bitnami:rabbitmq:rabbitmq:latest",
+				"image_resource_id": "/subscriptions/789/resourceGroups/otherrgname/providers/Microsoft.Compute/images/MyRabbitMQImage",
+				"plan_info": config{
+					"plan_name":    "rabbitmq",
+					"plan_product": "rabbitmq",
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "disk to both managed image and shared image with missing property",
 			config: config{
@@ -0,0 +1,69 @@
+//go:generate packer-sdc struct-markdown
+//go:generate packer-sdc mapstructure-to-hcl2 -type PlanInformation
+
+package chroot
+
+import (
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-12-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+)
+
+// PlanInformation describes the purchase plan of a marketplace image that
+// the source of the build is based on.
+type PlanInformation struct {
+	// The plan name
+	PlanName string `mapstructure:"plan_name" required:"true"`
+	// The plan product
+	PlanProduct string `mapstructure:"plan_product" required:"true"`
+	// The plan publisher
+	PlanPublisher string `mapstructure:"plan_publisher" required:"true"`
+	// Some images accept a promotion code
+	PlanPromotionCode string `mapstructure:"plan_promotion_code"`
+}
+
+// IsSet returns true when any of the plan information is set
+func (p PlanInformation) IsSet() bool {
+	return p.PlanName != "" || p.PlanProduct != "" || p.PlanPublisher != "" || p.PlanPromotionCode != ""
+}
+
+// Validate validates that the plan information is complete
+func (p PlanInformation) Validate(prefix string) (errs []error) {
+	if !p.IsSet() {
+		return nil
+	}
+	if p.PlanName == "" {
+		errs = append(errs, fmt.Errorf("%s.plan_name is required", prefix))
+	}
+	if p.PlanProduct == "" {
+		errs = append(errs, fmt.Errorf("%s.plan_product is required", prefix))
+	}
+	if p.PlanPublisher == "" {
+		errs = append(errs, fmt.Errorf("%s.plan_publisher is required", prefix))
+	}
+	return errs
+}
+
+// Tags returns the tags recording the plan information on the managed image,
+// the same as the ones the azure-arm builder adds.
+func (p PlanInformation) Tags() map[string]*string {
+	if !p.IsSet() {
+		return nil
+	}
+	return map[string]*string{
+		"PlanInfo":          to.StringPtr(p.PlanName),
+		"PlanProduct":       to.StringPtr(p.PlanProduct),
+		"PlanPublisher":     to.StringPtr(p.PlanPublisher),
+		"PlanPromotionCode": to.StringPtr(p.PlanPromotionCode),
+	}
+}
+
+// Matches returns true when the purchase plan of a shared image is the same
+// as the plan information
+func (p PlanInformation) Matches(plan *compute.ImagePurchasePlan) bool {
+	return plan != nil &&
+		to.String(plan.Name) == p.PlanName &&
+		to.String(plan.Product) == p.PlanProduct &&
+		to.String(plan.Publisher) == p.PlanPublisher
+}
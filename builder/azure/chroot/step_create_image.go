@@ -24,6 +24,7 @@ type StepCreateImage struct {
 	DataDiskStorageAccountType string
 	DataDiskCacheType          string
 	Location                   string
+	Tags                       map[string]*string
 }
 
 func (s *StepCreateImage) Run(ctx context.Context, state multistep.StateBag) multistep.StepAction {
@@ -64,7 +65,7 @@ func (s *StepCreateImage) Run(ctx context.Context, state multistep.StateBag) mul
 				//	ZoneResilient: nil,
 			},
 		},
-		//		Tags:            nil,
+		Tags: s.Tags,
 	}
 
 	var datadisks []compute.ImageDataDisk
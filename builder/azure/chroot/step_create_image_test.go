@@ -25,6 +25,7 @@ func TestStepCreateImage_Run(t *testing.T) {
 		DataDiskStorageAccountType string
 		DataDiskCacheType          string
 		Location                   string
+		Tags                       map[string]*string
 	}
 	tests := []struct {
 		name        string
@@ -91,6 +92,44 @@ func TestStepCreateImage_Run(t *testing.T) {
 				}
 			}`,
 		},
+		{
+			name: "with plan info tags",
+			fields: fields{
+				ImageResourceID:          "/subscriptions/12345/resourceGroups/group1/providers/Microsoft.Compute/images/myImage",
+				Location:                 "location1",
+				OSDiskStorageAccountType: "Standard_LRS",
+				OSDiskCacheType:          "ReadWrite",
+				Tags: PlanInformation{
+					PlanName:      "rabbitmq",
+					PlanProduct:   "rabbitmq",
+					PlanPublisher: "bitnami",
+				}.Tags(),
+			},
+			diskset: diskset(
+				"/subscriptions/12345/resourceGroups/group1/providers/Microsoft.Compute/disks/osdisk"),
+			want: multistep.ActionContinue,
+			wantPutBody: `{
+				"location": "location1",
+				"properties": {
+					"storageProfile": {
+						"osDisk": {
+							"osType": "Linux",
+							"managedDisk": {
+								"id": "/subscriptions/12345/resourceGroups/group1/providers/Microsoft.Compute/disks/osdisk"
+							},
+							"caching": "ReadWrite",
+							"storageAccountType": "Standard_LRS"
+						}
+					}
+				},
+				"tags": {
+					"PlanInfo": "rabbitmq",
+					"PlanProduct": "rabbitmq",
+					"PlanPromotionCode": "",
+					"PlanPublisher": "bitnami"
+				}
+			}`,
+		},
 	}
 	for _, tt := range tests {
 
@@ -128,6 +167,7 @@ func TestStepCreateImage_Run(t *testing.T) {
 				DataDiskStorageAccountType: tt.fields.DataDiskStorageAccountType,
 				DataDiskCacheType:          tt.fields.DataDiskCacheType,
 				Location:                   tt.fields.Location,
+				Tags:                       tt.fields.Tags,
 			}
 			if got := s.Run(context.TODO(), state); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("StepCreateImage.Run() = %v, want %v", got, tt.want)
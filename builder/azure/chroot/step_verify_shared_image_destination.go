@@ -20,6 +20,7 @@ var _ multistep.Step = &StepVerifySharedImageDestination{}
 type StepVerifySharedImageDestination struct {
 	Image    SharedImageGalleryDestination
 	Location string
+	PlanInfo PlanInformation
 }
 
 // Run retrieves the image metadata from Azure and compares the location to Location. Verifies the OS Type.
@@ -83,6 +84,14 @@ func (s *StepVerifySharedImageDestination) Run(ctx context.Context, state multis
 			image.GalleryImageProperties.OsType)
 	}
 
+	if s.PlanInfo.IsSet() && !s.PlanInfo.Matches(image.GalleryImageProperties.PurchasePlan) {
+		return errorMessage("The shared image (%q) does not have the purchase plan of plan_info (%s:%s:%s).",
+			to.String(image.ID),
+			s.PlanInfo.PlanPublisher,
+			s.PlanInfo.PlanProduct,
+			s.PlanInfo.PlanName)
+	}
+
 	ui.Say(fmt.Sprintf("Found image %s in location %s",
 		to.String(image.ID),
 		to.String(image.Location)))
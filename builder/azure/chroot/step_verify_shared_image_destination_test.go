@@ -21,6 +21,7 @@ func TestStepVerifySharedImageDestination_Run(t *testing.T) {
 	type fields struct {
 		Image    SharedImageGalleryDestination
 		Location string
+		PlanInfo PlanInformation
 	}
 	tests := []struct {
 		name    string
@@ -83,6 +84,43 @@ func TestStepVerifySharedImageDestination_Run(t *testing.T) {
 				Location: "region1",
 			},
 		},
+		{
+			name: "same plan",
+			want: multistep.ActionContinue,
+			fields: fields{
+				Image: SharedImageGalleryDestination{
+					ResourceGroup: "rg",
+					GalleryName:   "gallery",
+					ImageName:     "planimage",
+					ImageVersion:  "1.2.3",
+				},
+				Location: "region1",
+				PlanInfo: PlanInformation{
+					PlanName:      "plan",
+					PlanProduct:   "product",
+					PlanPublisher: "publisher",
+				},
+			},
+		},
+		{
+			name:    "no plan",
+			want:    multistep.ActionHalt,
+			wantErr: "The shared image (\"image-resourceid-goes-here\") does not have the purchase plan of plan_info (publisher:product:plan).",
+			fields: fields{
+				Image: SharedImageGalleryDestination{
+					ResourceGroup: "rg",
+					GalleryName:   "gallery",
+					ImageName:     "image",
+					ImageVersion:  "1.2.3",
+				},
+				Location: "region1",
+				PlanInfo: PlanInformation{
+					PlanName:      "plan",
+					PlanProduct:   "product",
+					PlanPublisher: "publisher",
+				},
+			},
+		},
 		{
 			name:    "not Linux",
 			want:    multistep.ActionHalt,
@@ -115,6 +153,24 @@ func TestStepVerifySharedImageDestination_Run(t *testing.T) {
 					}`)),
 					StatusCode: 200,
 				}, nil
+			case r.Method == "GET" && strings.HasPrefix(r.URL.RequestURI(),
+				"/subscriptions/subscriptionID/resourceGroups/rg/providers/Microsoft.Compute/galleries/gallery/images/planimage"):
+				return &http.Response{
+					Request: r,
+					Body: ioutil.NopCloser(strings.NewReader(`{
+						"id": "plan-image-resourceid-goes-here",
+						"location": "region1",
+						"properties": {
+							"osType": "Linux",
+							"purchasePlan": {
+								"name": "plan",
+								"product": "product",
+								"publisher": "publisher"
+							}
+						}
+					}`)),
+					StatusCode: 200,
+				}, nil
 			case r.Method == "GET" && strings.HasPrefix(r.URL.RequestURI(),
 				"/subscriptions/subscriptionID/resourceGroups/rg/providers/Microsoft.Compute/galleries/gallery/images/windowsimage"):
 				return &http.Response{
@@ -141,8 +197,10 @@ func TestStepVerifySharedImageDestination_Run(t *testing.T) {
 
 		giv := compute.NewGalleryImageVersionsClient("subscriptionID")
 		giv.Sender = autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
-			if !(r.Method == "GET" && strings.HasPrefix(r.URL.RequestURI(),
-				"/subscriptions/subscriptionID/resourceGroups/rg/providers/Microsoft.Compute/galleries/gallery/images/image/versions")) {
+			if !(r.Method == "GET" && (strings.HasPrefix(r.URL.RequestURI(),
+				"/subscriptions/subscriptionID/resourceGroups/rg/providers/Microsoft.Compute/galleries/gallery/images/image/versions") ||
+				strings.HasPrefix(r.URL.RequestURI(),
+					"/subscriptions/subscriptionID/resourceGroups/rg/providers/Microsoft.Compute/galleries/gallery/images/planimage/versions"))) {
 				t.Errorf("Unexpected HTTP call: %s %s", r.Method, r.URL.RequestURI())
 			}
 			return &http.Response{
@@ -170,6 +228,7 @@ func TestStepVerifySharedImageDestination_Run(t *testing.T) {
 			s := &StepVerifySharedImageDestination{
 				Image:    tt.fields.Image,
 				Location: tt.fields.Location,
+				PlanInfo: tt.fields.PlanInfo,
 			}
 			if got := s.Run(context.TODO(), state); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("StepVerifySharedImageDestination.Run() = %v, want %v", got, tt.want)
@@ -46,6 +46,8 @@ const (
 	ArmManagedImageSharedGalleryImageName                     string = "arm.ManagedImageSharedGalleryImageName"
 	ArmManagedImageSharedGalleryImageVersion                  string = "arm.ManagedImageSharedGalleryImageVersion"
 	ArmManagedImageSharedGalleryReplicationRegions            string = "arm.ManagedImageSharedGalleryReplicationRegions"
+	ArmManagedImageSharedGalleryTargetRegions                 string = "arm.ManagedImageSharedGalleryTargetRegions"
+	ArmManagedImageSharedGalleryStorageAccountType            string = "arm.ManagedImageSharedGalleryStorageAccountType"
 	ArmManagedImageSharedGalleryId                            string = "arm.ArmManagedImageSharedGalleryId"
 	ArmManagedImageSharedGalleryImageVersionEndOfLifeDate     string = "arm.ArmManagedImageSharedGalleryImageVersionEndOfLifeDate"
 	ArmManagedImageSharedGalleryImageVersionReplicaCount      string = "arm.ArmManagedImageSharedGalleryImageVersionReplicaCount"
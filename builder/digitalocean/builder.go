@@ -106,6 +106,7 @@ func (b *Builder) Run(ctx context.Context, ui packersdk.Ui, hook packersdk.Hook)
 		new(stepPowerOff),
 		&stepSnapshot{
 			snapshotTimeout: b.config.SnapshotTimeout,
+			transferTimeout: b.config.TransferTimeout,
 		},
 	}
 
@@ -414,3 +414,36 @@ func TestBuilderPrepare_ConnectWithPrivateIP(t *testing.T) {
 		t.Fatal("should not have error")
 	}
 }
+
+func TestBuilderPrepare_TransferTimeout(t *testing.T) {
+	var b Builder
+	config := testConfig()
+
+	// Test default
+	_, warnings, err := b.Prepare(config)
+	if len(warnings) > 0 {
+		t.Fatalf("bad: %#v", warnings)
+	}
+	if err != nil {
+		t.Fatalf("should not have error: %s", err)
+	}
+
+	if b.config.TransferTimeout != 20*time.Minute {
+		t.Errorf("invalid: %s", b.config.TransferTimeout)
+	}
+
+	// Test set
+	config["transfer_timeout"] = "45m"
+	b = Builder{}
+	_, warnings, err = b.Prepare(config)
+	if len(warnings) > 0 {
+		t.Fatalf("bad: %#v", warnings)
+	}
+	if err != nil {
+		t.Fatalf("should not have error: %s", err)
+	}
+
+	if b.config.TransferTimeout != 45*time.Minute {
+		t.Errorf("invalid: %s", b.config.TransferTimeout)
+	}
+}
@@ -73,6 +73,10 @@ type Config struct {
 	// its default of "60m" (valid time units include `s` for seconds, `m` for
 	// minutes, and `h` for hours.)
 	SnapshotTimeout time.Duration `mapstructure:"snapshot_timeout" required:"false"`
+	// How long to wait for the snapshot to be transferred to each of the
+	// `snapshot_regions` before timing out. The transfers run at the same
+	// time. The default transfer timeout is "20m".
+	TransferTimeout time.Duration `mapstructure:"transfer_timeout" required:"false"`
 	// The name assigned to the droplet. DigitalOcean
 	// sets the hostname of the machine to this value.
 	DropletName string `mapstructure:"droplet_name" required:"false"`
@@ -148,6 +152,11 @@ func (c *Config) Prepare(raws ...interface{}) ([]string, error) {
 		c.SnapshotTimeout = 60 * time.Minute
 	}
 
+	if c.TransferTimeout == 0 {
+		// Default to 20 minutes timeout, waiting for the snapshot transfers
+		c.TransferTimeout = 20 * time.Minute
+	}
+
 	var errs *packersdk.MultiError
 
 	if es := c.Comm.Prepare(&c.ctx); len(es) > 0 {
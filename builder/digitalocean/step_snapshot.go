@@ -14,6 +14,7 @@ import (
 
 type stepSnapshot struct {
 	snapshotTimeout time.Duration
+	transferTimeout time.Duration
 }
 
 func (s *stepSnapshot) Run(ctx context.Context, state multistep.StateBag) multistep.StepAction {
@@ -82,10 +83,12 @@ func (s *stepSnapshot) Run(ctx context.Context, state multistep.StateBag) multis
 		}
 		snapshotRegions = regions
 
-		for transfer := range snapshotRegions {
+		// Start all the transfers, then wait for each of them to complete
+		transferIds := make([]int, 0, len(snapshotRegions))
+		for _, region := range snapshotRegions {
 			transferRequest := &godo.ActionRequest{
 				"type":   "transfer",
-				"region": snapshotRegions[transfer],
+				"region": region,
 			}
 			imageTransfer, _, err := client.ImageActions.Transfer(context.TODO(), images[0].ID, transferRequest)
 			if err != nil {
@@ -94,11 +97,16 @@ func (s *stepSnapshot) Run(ctx context.Context, state multistep.StateBag) multis
 				ui.Error(err.Error())
 				return multistep.ActionHalt
 			}
-			ui.Say(fmt.Sprintf("transferring Snapshot ID: %d", imageTransfer.ID))
-			if err := WaitForImageState(godo.ActionCompleted, imageTransfer.ID, action.ID,
-				client, 20*time.Minute); err != nil {
+			ui.Say(fmt.Sprintf("Transferring snapshot to %s (action ID: %d)", region, imageTransfer.ID))
+			transferIds = append(transferIds, imageTransfer.ID)
+		}
+
+		ui.Say("Waiting for snapshot transfers to complete...")
+		for i, transferId := range transferIds {
+			if err := WaitForImageState(godo.ActionCompleted, images[0].ID, transferId,
+				client, s.transferTimeout); err != nil {
 				// If we get an error the first time, actually report it
-				err := fmt.Errorf("Error waiting for snapshot transfer: %s", err)
+				err := fmt.Errorf("Error waiting for snapshot transfer to %s: %s", snapshotRegions[i], err)
 				state.Put("error", err)
 				ui.Error(err.Error())
 				return multistep.ActionHalt
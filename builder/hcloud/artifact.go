@@ -11,13 +11,13 @@ import (
 
 type Artifact struct {
 	// The name of the snapshot
-	snapshotName string
+	SnapshotName string
 
 	// The ID of the image
-	snapshotId int
+	SnapshotId int
 
 	// The hcloudClient for making API calls
-	hcloudClient *hcloud.Client
+	HcloudClient *hcloud.Client
 
 	// StateData should store data such as GeneratedData
 	// to be shared with post-processors
@@ -33,11 +33,11 @@ func (*Artifact) Files() []string {
 }
 
 func (a *Artifact) Id() string {
-	return strconv.Itoa(a.snapshotId)
+	return strconv.Itoa(a.SnapshotId)
 }
 
 func (a *Artifact) String() string {
-	return fmt.Sprintf("A snapshot was created: '%v' (ID: %v)", a.snapshotName, a.snapshotId)
+	return fmt.Sprintf("A snapshot was created: '%v' (ID: %v)", a.SnapshotName, a.SnapshotId)
 }
 
 func (a *Artifact) State(name string) interface{} {
@@ -45,7 +45,7 @@ func (a *Artifact) State(name string) interface{} {
 }
 
 func (a *Artifact) Destroy() error {
-	log.Printf("Destroying image: %d (%s)", a.snapshotId, a.snapshotName)
-	_, err := a.hcloudClient.Image.Delete(context.TODO(), &hcloud.Image{ID: a.snapshotId})
+	log.Printf("Destroying image: %d (%s)", a.SnapshotId, a.SnapshotName)
+	_, err := a.HcloudClient.Image.Delete(context.TODO(), &hcloud.Image{ID: a.SnapshotId})
 	return err
 }
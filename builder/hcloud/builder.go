@@ -81,9 +81,9 @@ func (b *Builder) Run(ctx context.Context, ui packersdk.Ui, hook packersdk.Hook)
 	}
 
 	artifact := &Artifact{
-		snapshotName: state.Get("snapshot_name").(string),
-		snapshotId:   state.Get("snapshot_id").(int),
-		hcloudClient: b.hcloudClient,
+		SnapshotName: state.Get("snapshot_name").(string),
+		SnapshotId:   state.Get("snapshot_id").(int),
+		HcloudClient: b.hcloudClient,
 		StateData:    map[string]interface{}{"generated_data": state.Get("generated_data")},
 	}
 
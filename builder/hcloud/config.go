@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/packer-plugin-sdk/common"
@@ -129,6 +130,11 @@ func (c *Config) Prepare(raws ...interface{}) ([]string, error) {
 		}
 	}
 
+	if c.Comm.SSHKeyPairName != "" && c.Comm.SSHPrivateKeyFile == "" && !c.Comm.SSHAgentAuth {
+		errs = packersdk.MultiErrorAppend(
+			errs, errors.New("ssh_private_key_file or ssh_agent_auth must be specified with ssh_keypair_name"))
+	}
+
 	if c.UserData != "" && c.UserDataFile != "" {
 		errs = packersdk.MultiErrorAppend(
 			errs, errors.New("only one of user_data or user_data_file can be specified"))
@@ -147,6 +153,15 @@ func (c *Config) Prepare(raws ...interface{}) ([]string, error) {
 	return nil, nil
 }
 
+// serverTypeArchitecture returns the CPU architecture of a server type: the
+// Ampere CAX server types are ARM servers.
+func serverTypeArchitecture(serverType string) string {
+	if strings.HasPrefix(strings.ToLower(serverType), "cax") {
+		return "arm"
+	}
+	return "x86"
+}
+
 func getServerIP(state multistep.StateBag) (string, error) {
 	return state.Get("server_ip").(string), nil
 }
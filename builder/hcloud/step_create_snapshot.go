@@ -17,11 +17,21 @@ func (s *stepCreateSnapshot) Run(ctx context.Context, state multistep.StateBag)
 	c := state.Get("config").(*Config)
 	serverID := state.Get("server_id").(int)
 
+	// Label the snapshot with the architecture of the server, so that
+	// the ARM snapshots can be told apart from the x86 ones.
+	labels := make(map[string]string, len(c.SnapshotLabels)+1)
+	for k, v := range c.SnapshotLabels {
+		labels[k] = v
+	}
+	if _, ok := labels["architecture"]; !ok {
+		labels["architecture"] = serverTypeArchitecture(c.ServerType)
+	}
+
 	ui.Say("Creating snapshot ...")
 	ui.Say("This can take some time")
 	result, _, err := client.Server.CreateImage(ctx, &hcloud.Server{ID: serverID}, &hcloud.ServerCreateImageOpts{
 		Type:        hcloud.ImageTypeSnapshot,
-		Labels:      c.SnapshotLabels,
+		Labels:      labels,
 		Description: hcloud.String(c.SnapshotName),
 	})
 	if err != nil {
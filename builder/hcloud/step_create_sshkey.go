@@ -7,6 +7,7 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"os"
 	"runtime"
@@ -29,6 +30,11 @@ func (s *stepCreateSSHKey) Run(ctx context.Context, state multistep.StateBag) mu
 	client := state.Get("hcloudClient").(*hcloud.Client)
 	ui := state.Get("ui").(packersdk.Ui)
 	c := state.Get("config").(*Config)
+
+	if c.Comm.SSHKeyPairName != "" {
+		return s.useExistingKey(ctx, state)
+	}
+
 	ui.Say("Creating temporary ssh key for server...")
 
 	priv, err := rsa.GenerateKey(rand.Reader, 2014)
@@ -108,6 +114,43 @@ func (s *stepCreateSSHKey) Run(ctx context.Context, state multistep.StateBag) mu
 	return multistep.ActionContinue
 }
 
+// useExistingKey launches the server with the ssh key named ssh_keypair_name,
+// and connects with the private key in ssh_private_key_file.
+func (s *stepCreateSSHKey) useExistingKey(ctx context.Context, state multistep.StateBag) multistep.StepAction {
+	client := state.Get("hcloudClient").(*hcloud.Client)
+	ui := state.Get("ui").(packersdk.Ui)
+	c := state.Get("config").(*Config)
+	ui.Say(fmt.Sprintf("Using existing ssh key %s...", c.Comm.SSHKeyPairName))
+
+	key, _, err := client.SSHKey.Get(ctx, c.Comm.SSHKeyPairName)
+	if err != nil {
+		err := fmt.Errorf("Error fetching SSH key: %s", err)
+		state.Put("error", err)
+		ui.Error(err.Error())
+		return multistep.ActionHalt
+	}
+	if key == nil {
+		err := fmt.Errorf("Could not find key: %s", c.Comm.SSHKeyPairName)
+		state.Put("error", err)
+		ui.Error(err.Error())
+		return multistep.ActionHalt
+	}
+
+	if c.Comm.SSHPrivateKeyFile != "" {
+		privateKey, err := ioutil.ReadFile(c.Comm.SSHPrivateKeyFile)
+		if err != nil {
+			err := fmt.Errorf("Error loading configured private key file: %s", err)
+			state.Put("error", err)
+			ui.Error(err.Error())
+			return multistep.ActionHalt
+		}
+		c.Comm.SSHPrivateKey = privateKey
+	}
+
+	state.Put("ssh_key_id", key.ID)
+	return multistep.ActionContinue
+}
+
 func (s *stepCreateSSHKey) Cleanup(state multistep.StateBag) {
 	// If no key id is set, then we never created it, so just return
 	if s.keyId == 0 {
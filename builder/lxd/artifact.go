@@ -6,6 +6,8 @@ import (
 
 type Artifact struct {
 	id string
+	// remote is the LXD remote the image was published to
+	remote string
 
 	// StateData should store data such as GeneratedData
 	// to be shared with post-processors
@@ -33,6 +35,6 @@ func (a *Artifact) State(name string) interface{} {
 }
 
 func (a *Artifact) Destroy() error {
-	_, err := LXDCommand("image", "delete", a.id)
+	_, err := LXDCommand("image", "delete", remoteName(a.remote, a.id))
 	return err
 }
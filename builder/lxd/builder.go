@@ -63,6 +63,7 @@ func (b *Builder) Run(ctx context.Context, ui packersdk.Ui, hook packersdk.Hook)
 
 	artifact := &Artifact{
 		id:        state.Get("imageFingerprint").(string),
+		remote:    b.config.PublishRemote,
 		StateData: map[string]interface{}{"generated_data": state.Get("generated_data")},
 	}
 
@@ -3,6 +3,7 @@ package lxd
 import (
 	"os"
 	"testing"
+	"time"
 
 	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
 )
@@ -75,3 +76,54 @@ func TestBuilder_ImplementsBuilder(t *testing.T) {
 		t.Fatalf("Builder should be a builder")
 	}
 }
+
+func TestBuilderPrepare_Remote(t *testing.T) {
+	var b Builder
+	config := testConfig()
+	config["remote"] = "build-server"
+	config["container_name"] = "packer-foo"
+	_, _, err := b.Prepare(config)
+	if err != nil {
+		t.Fatalf("should not have error: %s", err)
+	}
+	if b.config.InstanceName() != "build-server:packer-foo" {
+		t.Fatalf("bad instance name: %s", b.config.InstanceName())
+	}
+	if b.config.PublishRemote != "build-server" {
+		t.Fatalf("publish_remote should default to remote, got: %s", b.config.PublishRemote)
+	}
+
+	b = Builder{}
+	config["publish_remote"] = "images"
+	config["publish_expiry"] = "720h"
+	_, _, err = b.Prepare(config)
+	if err != nil {
+		t.Fatalf("should not have error: %s", err)
+	}
+	if b.config.PublishRemote != "images" {
+		t.Fatalf("bad publish_remote: %s", b.config.PublishRemote)
+	}
+	if b.config.PublishExpiry != 720*time.Hour {
+		t.Fatalf("bad publish_expiry: %s", b.config.PublishExpiry)
+	}
+}
+
+func TestBuilderPrepare_Profiles(t *testing.T) {
+	var b Builder
+	config := testConfig()
+	config["profiles"] = []string{"default", "build"}
+	_, _, err := b.Prepare(config)
+	if err != nil {
+		t.Fatalf("should not have error: %s", err)
+	}
+	if b.config.Profile != "" {
+		t.Fatalf("profile should not default when profiles are set, got: %s", b.config.Profile)
+	}
+
+	b = Builder{}
+	config["profile"] = "default"
+	_, _, err = b.Prepare(config)
+	if err == nil {
+		t.Fatal("should have error with both profile and profiles")
+	}
+}
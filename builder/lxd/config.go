@@ -5,6 +5,7 @@ package lxd
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/packer-plugin-sdk/common"
 	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
@@ -26,8 +27,18 @@ type Config struct {
 	// The source image to use when creating the build
 	// container. This can be a (local or remote) image (name or fingerprint).
 	// E.G. my-base-image, ubuntu-daily:x, 08fababf6f27, ...
-	Image   string `mapstructure:"image" required:"true"`
+	Image string `mapstructure:"image" required:"true"`
+	// The LXD remote to build the instance on, as configured with `lxc remote
+	// add`. Defaults to the default remote of the `lxc` client.
+	Remote string `mapstructure:"remote" required:"false"`
+	// Launch a virtual machine instead of a container. The image must be a
+	// virtual machine image. Defaults to `false`.
+	VirtualMachine bool `mapstructure:"virtual_machine" required:"false"`
+	// The profile to launch the instance with. Defaults to `default`.
 	Profile string `mapstructure:"profile"`
+	// A list of profiles to launch the instance with, applied in order. This
+	// can't be used along with `profile`.
+	Profiles []string `mapstructure:"profiles" required:"false"`
 	// The number of seconds to sleep between launching
 	// the LXD instance and provisioning it; defaults to 3 seconds.
 	InitSleep string `mapstructure:"init_sleep" required:"false"`
@@ -37,6 +48,13 @@ type Config struct {
 	// https://stgraber.org/2016/03/30/lxd-2-0-image-management-512/
 	// for more properties.
 	PublishProperties map[string]string `mapstructure:"publish_properties" required:"false"`
+	// The LXD remote to publish the output image to. Defaults to `remote`.
+	PublishRemote string `mapstructure:"publish_remote" required:"false"`
+	// Aliases to add to the output image, in addition to `output_image`.
+	PublishAliases []string `mapstructure:"publish_aliases" required:"false"`
+	// The time after which the output image expires, e.g. `720h`. Defaults to
+	// the expiry set by the LXD server.
+	PublishExpiry time.Duration `mapstructure:"publish_expiry" required:"false"`
 	// List of key/value pairs you wish to
 	// pass to lxc launch via --config. Defaults to empty.
 	LaunchConfig map[string]string `mapstructure:"launch_config" required:"false"`
@@ -74,10 +92,22 @@ func (c *Config) Prepare(raws ...interface{}) error {
 		errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("`image` is a required parameter for LXD. Please specify an image by alias or fingerprint. e.g. `ubuntu-daily:x`"))
 	}
 
-	if c.Profile == "" {
+	if c.Profile != "" && len(c.Profiles) > 0 {
+		errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("Only one of `profile` or `profiles` can be specified"))
+	}
+
+	if c.Profile == "" && len(c.Profiles) == 0 {
 		c.Profile = "default"
 	}
 
+	if c.PublishRemote == "" {
+		c.PublishRemote = c.Remote
+	}
+
+	if c.PublishExpiry < 0 {
+		errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("`publish_expiry` must be positive"))
+	}
+
 	// Sadly we have to wait a few seconds for /tmp to be intialized and networking
 	// to finish starting. There isn't a great cross platform to check when things are ready.
 	if c.InitSleep == "" {
@@ -90,3 +120,17 @@ func (c *Config) Prepare(raws ...interface{}) error {
 
 	return nil
 }
+
+// InstanceName returns the name of the build instance, prefixed by its remote
+// when one is set.
+func (c *Config) InstanceName() string {
+	return remoteName(c.Remote, c.ContainerName)
+}
+
+// remoteName returns the name of a resource on an LXD remote, as used by lxc.
+func remoteName(remote, name string) string {
+	if remote == "" {
+		return name
+	}
+	return fmt.Sprintf("%s:%s", remote, name)
+}
@@ -22,9 +22,15 @@ type FlatConfig struct {
 	ContainerName       *string           `mapstructure:"container_name" cty:"container_name" hcl:"container_name"`
 	CommandWrapper      *string           `mapstructure:"command_wrapper" required:"false" cty:"command_wrapper" hcl:"command_wrapper"`
 	Image               *string           `mapstructure:"image" required:"true" cty:"image" hcl:"image"`
+	Remote              *string           `mapstructure:"remote" required:"false" cty:"remote" hcl:"remote"`
+	VirtualMachine      *bool             `mapstructure:"virtual_machine" required:"false" cty:"virtual_machine" hcl:"virtual_machine"`
 	Profile             *string           `mapstructure:"profile" cty:"profile" hcl:"profile"`
+	Profiles            []string          `mapstructure:"profiles" required:"false" cty:"profiles" hcl:"profiles"`
 	InitSleep           *string           `mapstructure:"init_sleep" required:"false" cty:"init_sleep" hcl:"init_sleep"`
 	PublishProperties   map[string]string `mapstructure:"publish_properties" required:"false" cty:"publish_properties" hcl:"publish_properties"`
+	PublishRemote       *string           `mapstructure:"publish_remote" required:"false" cty:"publish_remote" hcl:"publish_remote"`
+	PublishAliases      []string          `mapstructure:"publish_aliases" required:"false" cty:"publish_aliases" hcl:"publish_aliases"`
+	PublishExpiry       *string           `mapstructure:"publish_expiry" required:"false" cty:"publish_expiry" hcl:"publish_expiry"`
 	LaunchConfig        map[string]string `mapstructure:"launch_config" required:"false" cty:"launch_config" hcl:"launch_config"`
 }
 
@@ -52,9 +58,15 @@ func (*FlatConfig) HCL2Spec() map[string]hcldec.Spec {
 		"container_name":             &hcldec.AttrSpec{Name: "container_name", Type: cty.String, Required: false},
 		"command_wrapper":            &hcldec.AttrSpec{Name: "command_wrapper", Type: cty.String, Required: false},
 		"image":                      &hcldec.AttrSpec{Name: "image", Type: cty.String, Required: false},
+		"remote":                     &hcldec.AttrSpec{Name: "remote", Type: cty.String, Required: false},
+		"virtual_machine":            &hcldec.AttrSpec{Name: "virtual_machine", Type: cty.Bool, Required: false},
 		"profile":                    &hcldec.AttrSpec{Name: "profile", Type: cty.String, Required: false},
+		"profiles":                   &hcldec.AttrSpec{Name: "profiles", Type: cty.List(cty.String), Required: false},
 		"init_sleep":                 &hcldec.AttrSpec{Name: "init_sleep", Type: cty.String, Required: false},
 		"publish_properties":         &hcldec.AttrSpec{Name: "publish_properties", Type: cty.Map(cty.String), Required: false},
+		"publish_remote":             &hcldec.AttrSpec{Name: "publish_remote", Type: cty.String, Required: false},
+		"publish_aliases":            &hcldec.AttrSpec{Name: "publish_aliases", Type: cty.List(cty.String), Required: false},
+		"publish_expiry":             &hcldec.AttrSpec{Name: "publish_expiry", Type: cty.String, Required: false},
 		"launch_config":              &hcldec.AttrSpec{Name: "launch_config", Type: cty.Map(cty.String), Required: false},
 	}
 	return s
@@ -17,22 +17,35 @@ func (s *stepLxdLaunch) Run(ctx context.Context, state multistep.StateBag) multi
 	config := state.Get("config").(*Config)
 	ui := state.Get("ui").(packersdk.Ui)
 
-	name := config.ContainerName
+	name := config.InstanceName()
 	image := config.Image
-	profile := fmt.Sprintf("--profile=%s", config.Profile)
 
 	launch_args := []string{
-		"launch", "--ephemeral=false", profile, image, name,
+		"launch", "--ephemeral=false",
 	}
 
+	profiles := config.Profiles
+	if config.Profile != "" {
+		profiles = []string{config.Profile}
+	}
+	for _, profile := range profiles {
+		launch_args = append(launch_args, fmt.Sprintf("--profile=%s", profile))
+	}
+
+	if config.VirtualMachine {
+		launch_args = append(launch_args, "--vm")
+	}
+
+	launch_args = append(launch_args, image, name)
+
 	for k, v := range config.LaunchConfig {
 		launch_args = append(launch_args, "--config", fmt.Sprintf("%s=%s", k, v))
 	}
 
-	ui.Say("Creating container...")
+	ui.Say("Creating instance...")
 	_, err := LXDCommand(launch_args...)
 	if err != nil {
-		err := fmt.Errorf("Error creating container: %s", err)
+		err := fmt.Errorf("Error creating instance: %s", err)
 		state.Put("error", err)
 		ui.Error(err.Error())
 		return multistep.ActionHalt
@@ -58,11 +71,11 @@ func (s *stepLxdLaunch) Cleanup(state multistep.StateBag) {
 	ui := state.Get("ui").(packersdk.Ui)
 
 	cleanup_args := []string{
-		"delete", "--force", config.ContainerName,
+		"delete", "--force", config.InstanceName(),
 	}
 
-	ui.Say("Unregistering and deleting deleting container...")
+	ui.Say("Unregistering and deleting deleting instance...")
 	if _, err := LXDCommand(cleanup_args...); err != nil {
-		ui.Error(fmt.Sprintf("Error deleting container: %s", err))
+		ui.Error(fmt.Sprintf("Error deleting instance: %s", err))
 	}
 }
@@ -20,7 +20,7 @@ func (s *StepProvision) Run(ctx context.Context, state multistep.StateBag) multi
 
 	// Create our communicator
 	comm := &Communicator{
-		ContainerName: config.ContainerName,
+		ContainerName: config.InstanceName(),
 		CmdWrapper:    wrappedCommand,
 	}
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"time"
 
 	"github.com/hashicorp/packer-plugin-sdk/multistep"
 	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
@@ -15,33 +16,46 @@ func (s *stepPublish) Run(ctx context.Context, state multistep.StateBag) multist
 	config := state.Get("config").(*Config)
 	ui := state.Get("ui").(packersdk.Ui)
 
-	name := config.ContainerName
+	name := config.InstanceName()
 	stop_args := []string{
-		// We created the container with "--ephemeral=false" so we know it is safe to stop.
+		// We created the instance with "--ephemeral=false" so we know it is safe to stop.
 		"stop", name,
 	}
 
-	ui.Say("Stopping container...")
+	ui.Say("Stopping instance...")
 	_, err := LXDCommand(stop_args...)
 	if err != nil {
-		err := fmt.Errorf("Error stopping container: %s", err)
+		err := fmt.Errorf("Error stopping instance: %s", err)
 		state.Put("error", err)
 		ui.Error(err.Error())
 		return multistep.ActionHalt
 	}
 
 	publish_args := []string{
-		"publish", name, "--alias", config.OutputImage,
+		"publish", name,
+	}
+
+	if config.PublishRemote != "" {
+		publish_args = append(publish_args, config.PublishRemote+":")
+	}
+
+	for _, alias := range append([]string{config.OutputImage}, config.PublishAliases...) {
+		publish_args = append(publish_args, "--alias", alias)
+	}
+
+	if config.PublishExpiry > 0 {
+		expiry := time.Now().Add(config.PublishExpiry).UTC().Format(time.RFC3339)
+		publish_args = append(publish_args, "--expire", expiry)
 	}
 
 	for k, v := range config.PublishProperties {
 		publish_args = append(publish_args, fmt.Sprintf("%s=%s", k, v))
 	}
 
-	ui.Say("Publishing container...")
+	ui.Say("Publishing instance...")
 	stdoutString, err := LXDCommand(publish_args...)
 	if err != nil {
-		err := fmt.Errorf("Error publishing container: %s", err)
+		err := fmt.Errorf("Error publishing instance: %s", err)
 		state.Put("error", err)
 		ui.Error(err.Error())
 		return multistep.ActionHalt
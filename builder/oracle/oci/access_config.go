@@ -0,0 +1,196 @@
+package oci
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/user"
+	"path/filepath"
+
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+	"github.com/hashicorp/packer-plugin-sdk/pathing"
+	ocicommon "github.com/oracle/oci-go-sdk/v36/common"
+	ociauth "github.com/oracle/oci-go-sdk/v36/common/auth"
+)
+
+// AccessConfig holds the settings used to authenticate against OCI.
+type AccessConfig struct {
+	configProvider ocicommon.ConfigurationProvider
+
+	// Instance Principals (OPTIONAL)
+	// If set to true the following can't have non empty values
+	// - AccessCfgFile
+	// - AccessCfgFileAccount
+	// - UserID
+	// - TenancyID
+	// - Region
+	// - Fingerprint
+	// - KeyFile
+	// - PassPhrase
+	InstancePrincipals bool `mapstructure:"use_instance_principals"`
+
+	AccessCfgFile        string `mapstructure:"access_cfg_file"`
+	AccessCfgFileAccount string `mapstructure:"access_cfg_file_account"`
+
+	// Access config overrides
+	UserID      string `mapstructure:"user_ocid"`
+	TenancyID   string `mapstructure:"tenancy_ocid"`
+	Region      string `mapstructure:"region"`
+	Fingerprint string `mapstructure:"fingerprint"`
+	KeyFile     string `mapstructure:"key_file"`
+	PassPhrase  string `mapstructure:"pass_phrase"`
+}
+
+func (c *AccessConfig) ConfigProvider() ocicommon.ConfigurationProvider {
+	return c.configProvider
+}
+
+// Prepare validates the access settings and sets up the configuration
+// provider. Validation errors are appended to errs; err is only set when the
+// configuration provider could not be created at all.
+func (c *AccessConfig) Prepare(errs *packersdk.MultiError) (*packersdk.MultiError, error) {
+	var err error
+	if c.InstancePrincipals {
+		// We could go through all keys in one go and report that the below set
+		// of keys cannot coexist with use_instance_principals but decided to
+		// split them and report them seperately so that the user sees the specific
+		// key involved.
+		var message string = " cannot be present when use_instance_principals is set to true."
+		if c.AccessCfgFile != "" {
+			errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("access_cfg_file"+message))
+		}
+		if c.AccessCfgFileAccount != "" {
+			errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("access_cfg_file_account"+message))
+		}
+		if c.UserID != "" {
+			errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("user_ocid"+message))
+		}
+		if c.TenancyID != "" {
+			errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("tenancy_ocid"+message))
+		}
+		if c.Region != "" {
+			errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("region"+message))
+		}
+		if c.Fingerprint != "" {
+			errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("fingerprint"+message))
+		}
+		if c.KeyFile != "" {
+			errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("key_file"+message))
+		}
+		if c.PassPhrase != "" {
+			errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("pass_phrase"+message))
+		}
+		// This check is used to facilitate testing. During testing a Mock struct
+		// is assigned to c.configProvider otherwise testing fails because Instance
+		// Principals cannot be obtained.
+		if c.configProvider == nil {
+			// Even though the previous configuraion checks might fail we don't want
+			// to skip this step. It seems that the logic behind the checks in this
+			// file is to check everything even getting the configProvider.
+			c.configProvider, err = ociauth.InstancePrincipalConfigurationProvider()
+			if err != nil {
+				return errs, err
+			}
+		}
+		if _, err := c.configProvider.TenancyOCID(); err != nil {
+			return errs, err
+		}
+	} else {
+		// Determine where the SDK config is located
+		if c.AccessCfgFile == "" {
+			c.AccessCfgFile, err = getDefaultOCISettingsPath()
+			if err != nil {
+				log.Println("Default OCI settings file not found")
+			}
+		}
+
+		if c.AccessCfgFileAccount == "" {
+			c.AccessCfgFileAccount = "DEFAULT"
+		}
+
+		var keyContent []byte
+		if c.KeyFile != "" {
+			path, err := pathing.ExpandUser(c.KeyFile)
+			if err != nil {
+				return errs, err
+			}
+
+			// Read API signing key
+			keyContent, err = ioutil.ReadFile(path)
+			if err != nil {
+				return errs, err
+			}
+		}
+
+		fileProvider, _ := ocicommon.ConfigurationProviderFromFileWithProfile(c.AccessCfgFile, c.AccessCfgFileAccount, c.PassPhrase)
+		if c.Region == "" {
+			var region string
+			if fileProvider != nil {
+				region, _ = fileProvider.Region()
+			}
+			if region == "" {
+				c.Region = "us-phoenix-1"
+			}
+		}
+
+		providers := []ocicommon.ConfigurationProvider{
+			ocicommon.NewRawConfigurationProvider(c.TenancyID, c.UserID, c.Region, c.Fingerprint, string(keyContent), &c.PassPhrase),
+		}
+
+		if fileProvider != nil {
+			providers = append(providers, fileProvider)
+		}
+
+		// Load API access configuration from SDK
+		configProvider, err := ocicommon.ComposingConfigurationProvider(providers)
+		if err != nil {
+			return errs, err
+		}
+
+		if userOCID, _ := configProvider.UserOCID(); userOCID == "" {
+			errs = packersdk.MultiErrorAppend(
+				errs, errors.New("'user_ocid' must be specified"))
+		}
+
+		if tenancyOCID, _ := configProvider.TenancyOCID(); tenancyOCID == "" {
+			errs = packersdk.MultiErrorAppend(
+				errs, errors.New("'tenancy_ocid' must be specified"))
+		}
+
+		if fingerprint, _ := configProvider.KeyFingerprint(); fingerprint == "" {
+			errs = packersdk.MultiErrorAppend(
+				errs, errors.New("'fingerprint' must be specified"))
+		}
+
+		if _, err := configProvider.PrivateRSAKey(); err != nil {
+			errs = packersdk.MultiErrorAppend(
+				errs, fmt.Errorf("'key_file' must be correctly specified. %w", err))
+		}
+
+		c.configProvider = configProvider
+	}
+
+	return errs, nil
+}
+
+// getDefaultOCISettingsPath uses os/user to compute the default
+// config file location ($HOME/.oci/config).
+func getDefaultOCISettingsPath() (string, error) {
+	u, err := user.Current()
+	if err != nil {
+		return "", err
+	}
+
+	if u.HomeDir == "" {
+		return "", fmt.Errorf("Unable to determine the home directory for the current user.")
+	}
+
+	path := filepath.Join(u.HomeDir, ".oci", "config")
+	if _, err := os.Stat(path); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
@@ -9,18 +9,13 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
-	"os/user"
-	"path/filepath"
 	"strings"
 
 	"github.com/hashicorp/packer-plugin-sdk/common"
 	"github.com/hashicorp/packer-plugin-sdk/communicator"
 	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
-	"github.com/hashicorp/packer-plugin-sdk/pathing"
 	"github.com/hashicorp/packer-plugin-sdk/template/config"
 	"github.com/hashicorp/packer-plugin-sdk/template/interpolate"
-	ocicommon "github.com/oracle/oci-go-sdk/v36/common"
-	ociauth "github.com/oracle/oci-go-sdk/v36/common/auth"
 )
 
 type CreateVNICDetails struct {
@@ -55,31 +50,9 @@ type Config struct {
 	common.PackerConfig `mapstructure:",squash"`
 	Comm                communicator.Config `mapstructure:",squash"`
 
-	configProvider ocicommon.ConfigurationProvider
-
-	// Instance Principals (OPTIONAL)
-	// If set to true the following can't have non empty values
-	// - AccessCfgFile
-	// - AccessCfgFileAccount
-	// - UserID
-	// - TenancyID
-	// - Region
-	// - Fingerprint
-	// - KeyFile
-	// - PassPhrase
-	InstancePrincipals bool `mapstructure:"use_instance_principals"`
-
-	AccessCfgFile        string `mapstructure:"access_cfg_file"`
-	AccessCfgFileAccount string `mapstructure:"access_cfg_file_account"`
-
-	// Access config overrides
-	UserID       string `mapstructure:"user_ocid"`
-	TenancyID    string `mapstructure:"tenancy_ocid"`
-	Region       string `mapstructure:"region"`
-	Fingerprint  string `mapstructure:"fingerprint"`
-	KeyFile      string `mapstructure:"key_file"`
-	PassPhrase   string `mapstructure:"pass_phrase"`
-	UsePrivateIP bool   `mapstructure:"use_private_ip"`
+	AccessConfig `mapstructure:",squash"`
+
+	UsePrivateIP bool `mapstructure:"use_private_ip"`
 
 	AvailabilityDomain string `mapstructure:"availability_domain"`
 	CompartmentID      string `mapstructure:"compartment_ocid"`
@@ -121,10 +94,6 @@ type Config struct {
 	ctx interpolate.Context
 }
 
-func (c *Config) ConfigProvider() ocicommon.ConfigurationProvider {
-	return c.configProvider
-}
-
 func (c *Config) Prepare(raws ...interface{}) error {
 
 	// Decode from template
@@ -141,128 +110,14 @@ func (c *Config) Prepare(raws ...interface{}) error {
 		errs = packersdk.MultiErrorAppend(errs, es...)
 	}
 
-	var tenancyOCID string
-
-	if c.InstancePrincipals {
-		// We could go through all keys in one go and report that the below set
-		// of keys cannot coexist with use_instance_principals but decided to
-		// split them and report them seperately so that the user sees the specific
-		// key involved.
-		var message string = " cannot be present when use_instance_principals is set to true."
-		if c.AccessCfgFile != "" {
-			errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("access_cfg_file"+message))
-		}
-		if c.AccessCfgFileAccount != "" {
-			errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("access_cfg_file_account"+message))
-		}
-		if c.UserID != "" {
-			errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("user_ocid"+message))
-		}
-		if c.TenancyID != "" {
-			errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("tenancy_ocid"+message))
-		}
-		if c.Region != "" {
-			errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("region"+message))
-		}
-		if c.Fingerprint != "" {
-			errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("fingerprint"+message))
-		}
-		if c.KeyFile != "" {
-			errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("key_file"+message))
-		}
-		if c.PassPhrase != "" {
-			errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("pass_phrase"+message))
-		}
-		// This check is used to facilitate testing. During testing a Mock struct
-		// is assigned to c.configProvider otherwise testing fails because Instance
-		// Principals cannot be obtained.
-		if c.configProvider == nil {
-			// Even though the previous configuraion checks might fail we don't want
-			// to skip this step. It seems that the logic behind the checks in this
-			// file is to check everything even getting the configProvider.
-			c.configProvider, err = ociauth.InstancePrincipalConfigurationProvider()
-			if err != nil {
-				return err
-			}
-		}
-		tenancyOCID, err = c.configProvider.TenancyOCID()
-		if err != nil {
-			return err
-		}
-	} else {
-		// Determine where the SDK config is located
-		if c.AccessCfgFile == "" {
-			c.AccessCfgFile, err = getDefaultOCISettingsPath()
-			if err != nil {
-				log.Println("Default OCI settings file not found")
-			}
-		}
-
-		if c.AccessCfgFileAccount == "" {
-			c.AccessCfgFileAccount = "DEFAULT"
-		}
-
-		var keyContent []byte
-		if c.KeyFile != "" {
-			path, err := pathing.ExpandUser(c.KeyFile)
-			if err != nil {
-				return err
-			}
-
-			// Read API signing key
-			keyContent, err = ioutil.ReadFile(path)
-			if err != nil {
-				return err
-			}
-		}
-
-		fileProvider, _ := ocicommon.ConfigurationProviderFromFileWithProfile(c.AccessCfgFile, c.AccessCfgFileAccount, c.PassPhrase)
-		if c.Region == "" {
-			var region string
-			if fileProvider != nil {
-				region, _ = fileProvider.Region()
-			}
-			if region == "" {
-				c.Region = "us-phoenix-1"
-			}
-		}
-
-		providers := []ocicommon.ConfigurationProvider{
-			ocicommon.NewRawConfigurationProvider(c.TenancyID, c.UserID, c.Region, c.Fingerprint, string(keyContent), &c.PassPhrase),
-		}
-
-		if fileProvider != nil {
-			providers = append(providers, fileProvider)
-		}
-
-		// Load API access configuration from SDK
-		configProvider, err := ocicommon.ComposingConfigurationProvider(providers)
-		if err != nil {
-			return err
-		}
-
-		if userOCID, _ := configProvider.UserOCID(); userOCID == "" {
-			errs = packersdk.MultiErrorAppend(
-				errs, errors.New("'user_ocid' must be specified"))
-		}
-
-		tenancyOCID, _ = configProvider.TenancyOCID()
-		if tenancyOCID == "" {
-			errs = packersdk.MultiErrorAppend(
-				errs, errors.New("'tenancy_ocid' must be specified"))
-		}
-
-		if fingerprint, _ := configProvider.KeyFingerprint(); fingerprint == "" {
-			errs = packersdk.MultiErrorAppend(
-				errs, errors.New("'fingerprint' must be specified"))
-		}
-
-		if _, err := configProvider.PrivateRSAKey(); err != nil {
-			errs = packersdk.MultiErrorAppend(
-				errs, fmt.Errorf("'key_file' must be correctly specified. %w", err))
-		}
+	errs, err = c.AccessConfig.Prepare(errs)
+	if err != nil {
+		return err
+	}
 
-		c.configProvider = configProvider
+	var tenancyOCID string
+	if c.configProvider != nil {
+		tenancyOCID, _ = c.configProvider.TenancyOCID()
 	}
 
 	if c.AvailabilityDomain == "" {
@@ -393,23 +248,3 @@ func (c *Config) Prepare(raws ...interface{}) error {
 
 	return nil
 }
-
-// getDefaultOCISettingsPath uses os/user to compute the default
-// config file location ($HOME/.oci/config).
-func getDefaultOCISettingsPath() (string, error) {
-	u, err := user.Current()
-	if err != nil {
-		return "", err
-	}
-
-	if u.HomeDir == "" {
-		return "", fmt.Errorf("Unable to determine the home directory for the current user.")
-	}
-
-	path := filepath.Join(u.HomeDir, ".oci", "config")
-	if _, err := os.Stat(path); err != nil {
-		return "", err
-	}
-
-	return path, nil
-}
@@ -115,6 +115,7 @@ func (b *Builder) Run(ctx context.Context, ui packersdk.Ui, hook packersdk.Hook)
 			DiskSize:                 b.config.DiskSize,
 			DataDisks:                b.config.DataDisks,
 			HostName:                 b.config.HostName,
+			CamRoleName:              b.config.CamRoleName,
 			InternetMaxBandwidthOut:  b.config.InternetMaxBandwidthOut,
 			AssociatePublicIpAddress: b.config.AssociatePublicIpAddress,
 			Tags:                     b.config.RunTags,
@@ -52,6 +52,32 @@ func WaitForInstance(ctx context.Context, client *cvm.Client, instanceId string,
 	return nil
 }
 
+// WaitForInstanceTerminated wait for instance to be terminated
+func WaitForInstanceTerminated(ctx context.Context, client *cvm.Client, instanceId string, timeout int) error {
+	req := cvm.NewDescribeInstancesRequest()
+	req.InstanceIds = []*string{&instanceId}
+
+	for {
+		var resp *cvm.DescribeInstancesResponse
+		err := Retry(ctx, func(ctx context.Context) error {
+			var e error
+			resp, e = client.DescribeInstances(req)
+			return e
+		})
+		if err != nil {
+			return err
+		}
+		if *resp.Response.TotalCount == 0 {
+			return nil
+		}
+		time.Sleep(DefaultWaitForInterval * time.Second)
+		timeout = timeout - DefaultWaitForInterval
+		if timeout <= 0 {
+			return fmt.Errorf("wait instance(%s) terminated timeout", instanceId)
+		}
+	}
+}
+
 // WaitForImageReady wait for image reaches statue
 func WaitForImageReady(ctx context.Context, client *cvm.Client, imageName string, status string, timeout int) error {
 	for {
@@ -84,6 +84,9 @@ type TencentCloudRunConfig struct {
 	UserDataFile string `mapstructure:"user_data_file" required:"false"`
 	// host name.
 	HostName string `mapstructure:"host_name" required:"false"`
+	// The name of the CAM role to bind to the instance, so that the
+	// provisioners can use the role's temporary credentials.
+	CamRoleName string `mapstructure:"cam_role_name" required:"false"`
 	// Key/value pair tags to apply to the instance that is *launched* to
 	// create the image. These tags are *not* applied to the resulting image.
 	RunTags map[string]string `mapstructure:"run_tags" required:"false"`
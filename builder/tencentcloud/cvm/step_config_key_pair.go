@@ -93,7 +93,8 @@ func (s *stepConfigKeyPair) Run(ctx context.Context, state multistep.StateBag) m
 }
 
 func (s *stepConfigKeyPair) Cleanup(state multistep.StateBag) {
-	if s.Comm.SSHPrivateKeyFile != "" || (s.Comm.SSHKeyPairName == "" && s.keyID == "") {
+	// Only the temporary key pair is deleted, never a key pair of the user
+	if s.keyID == "" {
 		return
 	}
 
@@ -21,6 +21,7 @@ type stepRunInstance struct {
 	DiskType                 string
 	DiskSize                 int64
 	HostName                 string
+	CamRoleName              string
 	InternetMaxBandwidthOut  int64
 	AssociatePublicIpAddress bool
 	Tags                     map[string]string
@@ -123,6 +124,9 @@ func (s *stepRunInstance) Run(ctx context.Context, state multistep.StateBag) mul
 	req.ClientToken = &s.InstanceName
 	req.HostName = &s.HostName
 	req.UserData = &userData
+	if s.CamRoleName != "" {
+		req.CamRoleName = &s.CamRoleName
+	}
 	var tags []*cvm.Tag
 	for k, v := range s.Tags {
 		tags = append(tags, &cvm.Tag{
@@ -218,5 +222,13 @@ func (s *stepRunInstance) Cleanup(state multistep.StateBag) {
 	})
 	if err != nil {
 		Error(state, err, fmt.Sprintf("Failed to terminate instance(%s), please delete it manually", s.instanceId))
+		return
+	}
+
+	// The key pair and the security group of the instance can only be
+	// deleted once it is gone.
+	err = WaitForInstanceTerminated(ctx, client, s.instanceId, 600)
+	if err != nil {
+		Error(state, err, fmt.Sprintf("Failed to wait for instance(%s) terminated", s.instanceId))
 	}
 }
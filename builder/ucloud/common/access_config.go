@@ -15,6 +15,7 @@ import (
 	"github.com/ucloud/ucloud-sdk-go/external"
 	"github.com/ucloud/ucloud-sdk-go/private/protocol/http"
 	"github.com/ucloud/ucloud-sdk-go/services/uaccount"
+	"github.com/ucloud/ucloud-sdk-go/services/udisk"
 	"github.com/ucloud/ucloud-sdk-go/services/ufile"
 	"github.com/ucloud/ucloud-sdk-go/services/uhost"
 	"github.com/ucloud/ucloud-sdk-go/services/unet"
@@ -122,6 +123,7 @@ func (c *AccessConfig) Client() (*UCloudClient, error) {
 	c.client.VPCConn = vpc.NewClient(&cfg, &cred)
 	c.client.UAccountConn = uaccount.NewClient(&cfg, &cred)
 	c.client.UFileConn = ufile.NewClient(&cfg, &cred)
+	c.client.UDiskConn = udisk.NewClient(&cfg, &cred)
 
 	if cloudShellCredHandler != nil {
 		if err := c.client.UHostConn.AddHttpRequestHandler(cloudShellCredHandler); err != nil {
@@ -139,6 +141,9 @@ func (c *AccessConfig) Client() (*UCloudClient, error) {
 		if err := c.client.UFileConn.AddHttpRequestHandler(cloudShellCredHandler); err != nil {
 			return nil, err
 		}
+		if err := c.client.UDiskConn.AddHttpRequestHandler(cloudShellCredHandler); err != nil {
+			return nil, err
+		}
 	}
 
 	return c.client, nil
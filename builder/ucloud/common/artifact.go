@@ -13,6 +13,9 @@ import (
 type Artifact struct {
 	UCloudImages *ImageInfoSet
 
+	// DataDiskSnapshots are the snapshots of the data disks of the instance
+	DataDiskSnapshots []SnapshotInfo
+
 	BuilderIdValue string
 
 	Client *UCloudClient
@@ -48,7 +51,17 @@ func (a *Artifact) String() string {
 	}
 
 	sort.Strings(m)
-	return fmt.Sprintf("UCloud images were created:\n\n%s", strings.Join(m, "\n"))
+	msg := fmt.Sprintf("UCloud images were created:\n\n%s", strings.Join(m, "\n"))
+
+	if len(a.DataDiskSnapshots) > 0 {
+		s := make([]string, 0, len(a.DataDiskSnapshots))
+		for _, v := range a.DataDiskSnapshots {
+			s = append(s, fmt.Sprintf("%s: %s: %s", v.Zone, v.DiskId, v.SnapshotId))
+		}
+		msg += fmt.Sprintf("\n\nUCloud data disk snapshots were created:\n\n%s", strings.Join(s, "\n"))
+	}
+
+	return msg
 }
 
 func (a *Artifact) State(name string) interface{} {
@@ -80,6 +93,17 @@ func (a *Artifact) Destroy() error {
 		}
 	}
 
+	for _, v := range a.DataDiskSnapshots {
+		log.Printf("Delete ucloud data disk snapshot %s from %s", v.SnapshotId, v.Zone)
+		req := a.Client.UDiskConn.NewDeleteUDiskSnapshotRequest()
+		req.Zone = ucloud.String(v.Zone)
+		req.SnapshotId = ucloud.String(v.SnapshotId)
+
+		if _, err := a.Client.UDiskConn.DeleteUDiskSnapshot(req); err != nil {
+			errors = append(errors, err)
+		}
+	}
+
 	if len(errors) > 0 {
 		if len(errors) == 1 {
 			return errors[0]
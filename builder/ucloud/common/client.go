@@ -2,6 +2,7 @@ package common
 
 import (
 	"github.com/ucloud/ucloud-sdk-go/services/uaccount"
+	"github.com/ucloud/ucloud-sdk-go/services/udisk"
 	"github.com/ucloud/ucloud-sdk-go/services/ufile"
 	"github.com/ucloud/ucloud-sdk-go/services/uhost"
 	"github.com/ucloud/ucloud-sdk-go/services/unet"
@@ -16,6 +17,7 @@ type UCloudClient struct {
 	VPCConn      *vpc.VPCClient
 	UAccountConn *uaccount.UAccountClient
 	UFileConn    *ufile.UFileClient
+	UDiskConn    *udisk.UDiskClient
 }
 
 func (c *UCloudClient) DescribeFirewallById(sgId string) (*unet.FirewallDataSet, error) {
@@ -122,6 +124,26 @@ func (c *UCloudClient) DescribeUHostById(uhostId string) (*uhost.UHostInstanceSe
 	return &resp.UHostSet[0], nil
 }
 
+func (c *UCloudClient) DescribeUDiskSnapshotById(zone, snapshotId string) (*udisk.UDiskSnapshotSet, error) {
+	if snapshotId == "" {
+		return nil, NewNotFoundError("snapshot", snapshotId)
+	}
+	req := c.UDiskConn.NewDescribeUDiskSnapshotRequest()
+	req.Zone = ucloud.String(zone)
+	req.SnapshotId = ucloud.String(snapshotId)
+
+	resp, err := c.UDiskConn.DescribeUDiskSnapshot(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.DataSet) < 1 {
+		return nil, NewNotFoundError("snapshot", snapshotId)
+	}
+
+	return &resp.DataSet[0], nil
+}
+
 func (c *UCloudClient) DescribeImageByInfo(projectId, regionId, imageId string) (*uhost.UHostImageSet, error) {
 	req := c.UHostConn.NewDescribeImageRequest()
 	req.ProjectId = ucloud.String(projectId)
@@ -15,6 +15,8 @@ const (
 	ImageStateUnavailable = "Unavailable"
 
 	BootDiskStateNormal = "Normal"
+	SnapshotStateNormal = "Normal"
+	SnapshotStateFailed = "Failed"
 	OsTypeWindows       = "Windows"
 	SecurityGroupNonWeb = "recommend non web"
 	IpTypePrivate       = "Private"
@@ -31,6 +33,12 @@ var BootDiskTypeMap = NewStringConverter(map[string]string{
 	"cloud_rssd":   "CLOUD_RSSD",
 })
 
+var DataDiskTypeMap = NewStringConverter(map[string]string{
+	"cloud_normal": "CLOUD_NORMAL",
+	"cloud_ssd":    "CLOUD_SSD",
+	"cloud_rssd":   "CLOUD_RSSD",
+})
+
 var ChargeModeMap = NewStringConverter(map[string]string{
 	"post_accurate_bandwidth": "PostAccurateBandwidth",
 	"traffic":                 "Traffic",
@@ -1,4 +1,6 @@
+//go:generate packer-sdc mapstructure-to-hcl2 -type DataDisk
 //go:generate packer-sdc struct-markdown
+
 package common
 
 import (
@@ -11,6 +13,14 @@ import (
 	"github.com/hashicorp/packer-plugin-sdk/uuid"
 )
 
+type DataDisk struct {
+	// The type of the data disk. Possible values are: `cloud_normal`, `cloud_ssd`
+	// and `cloud_rssd`. (Default: `cloud_ssd`).
+	Type string `mapstructure:"type" required:"false"`
+	// The size of the data disk, in GB. The size must be at least 20 GB.
+	Size int `mapstructure:"size" required:"true"`
+}
+
 type RunConfig struct {
 	// This is the UCloud availability zone where UHost instance is located. such as: `cn-bj2-02`.
 	// You may refer to [list of availability_zone](https://docs.ucloud.cn/api/summary/regionlist)
@@ -30,6 +40,26 @@ type RunConfig struct {
 	//
 	//~> **Note:** It takes around 10 mins for boot disk initialization when `boot_disk_type` is `local_normal` or `local_ssd`.
 	BootDiskType string `mapstructure:"boot_disk_type" required:"false"`
+	// The cloud data disks attached to the UHost instance. A snapshot of each
+	// data disk is taken along with the image, and is part of the artifact.
+	// The snapshots are not copied with `image_copy_to_mappings`.
+	//
+	//  - `type` (string) - The type of the data disk. Possible values are: `cloud_normal`,
+	//    `cloud_ssd` and `cloud_rssd`. (Default: `cloud_ssd`).
+	//
+	//  - `size` (int) - The size of the data disk, in GB. The size must be at least 20 GB.
+	//
+	// ```json
+	// {
+	//   "data_disks": [
+	//     {
+	//       "type": "cloud_ssd",
+	//       "size": 50
+	//     }
+	//   ]
+	// }
+	// ```
+	DataDisks []DataDisk `mapstructure:"data_disks" required:"false"`
 	// The ID of VPC linked to the UHost instance. If not defined `vpc_id`, the instance will use the default VPC in the current region.
 	VPCId string `mapstructure:"vpc_id" required:"false"`
 	// The ID of subnet under the VPC. If `vpc_id` is defined, the `subnet_id` is mandatory required.
@@ -107,6 +137,20 @@ func (c *RunConfig) Prepare(ctx *interpolate.Context) []error {
 		errs = append(errs, err)
 	}
 
+	for i := range c.DataDisks {
+		disk := &c.DataDisks[i]
+		if disk.Type == "" {
+			disk.Type = "cloud_ssd"
+		} else if err := CheckStringIn(disk.Type,
+			[]string{"cloud_normal", "cloud_ssd", "cloud_rssd"}); err != nil {
+			errs = append(errs, fmt.Errorf("data_disks[%d].type %s", i, err))
+		}
+
+		if disk.Size < 20 {
+			errs = append(errs, fmt.Errorf("expected data_disks[%d].size to be at least 20, got %d", i, disk.Size))
+		}
+	}
+
 	if c.InstanceName == "" {
 		c.InstanceName = fmt.Sprintf("packer-%s", uuid.TimeOrderedUUID()[:8])
 	} else if !instanceNamePattern.MatchString(c.InstanceName) {
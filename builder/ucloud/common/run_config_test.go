@@ -63,3 +63,25 @@ func TestRunConfigPrepare_SSHPort(t *testing.T) {
 		t.Fatalf("invalid value: %d", c.Comm.SSHPort)
 	}
 }
+
+func TestRunConfigPrepare_DataDisks(t *testing.T) {
+	c := testConfig()
+	c.DataDisks = []DataDisk{{Size: 50}}
+	if err := c.Prepare(nil); len(err) != 0 {
+		t.Fatalf("err: %s", err)
+	}
+
+	if c.DataDisks[0].Type != "cloud_ssd" {
+		t.Fatalf("invalid value: %s", c.DataDisks[0].Type)
+	}
+
+	c.DataDisks = []DataDisk{{Type: "local_ssd", Size: 50}}
+	if err := c.Prepare(nil); len(err) != 1 {
+		t.Fatalf("err: %s", err)
+	}
+
+	c.DataDisks = []DataDisk{{Type: "cloud_rssd", Size: 10}}
+	if err := c.Prepare(nil); len(err) != 1 {
+		t.Fatalf("err: %s", err)
+	}
+}
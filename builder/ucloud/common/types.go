@@ -170,6 +170,15 @@ func (i *ImageInfo) Id() string {
 	return fmt.Sprintf("%s:%s", i.ProjectId, i.Region)
 }
 
+// SnapshotInfo is a snapshot of a data disk of the UHost instance, taken
+// along with the image.
+type SnapshotInfo struct {
+	SnapshotId string
+	DiskId     string
+	Zone       string
+	Size       int
+}
+
 type ImageInfoSet struct {
 	m    map[string]ImageInfo
 	once sync.Once
@@ -113,6 +113,7 @@ func (b *Builder) Run(ctx context.Context, ui packersdk.Ui, hook packersdk.Hook)
 			SourceImageId:  b.config.SourceImageId,
 			InstanceName:   b.config.InstanceName,
 			BootDiskType:   b.config.BootDiskType,
+			DataDisks:      b.config.DataDisks,
 			UsePrivateIp:   b.config.UseSSHPrivateIp,
 			EipBandwidth:   b.config.EipBandwidth,
 			EipChargeMode:  b.config.EipChargeMode,
@@ -129,6 +130,9 @@ func (b *Builder) Run(ctx context.Context, ui packersdk.Ui, hook packersdk.Hook)
 		&commonsteps.StepProvision{},
 		&stepStopInstance{},
 		&stepCreateImage{},
+		&stepCreateDataDiskSnapshots{
+			Zone: b.config.Zone,
+		},
 		&stepCopyUCloudImage{
 			ImageDestinations:     b.config.ImageDestinations,
 			RegionId:              b.config.Region,
@@ -159,5 +163,9 @@ func (b *Builder) Run(ctx context.Context, ui packersdk.Ui, hook packersdk.Hook)
 		StateData:      map[string]interface{}{"generated_data": state.Get("generated_data")},
 	}
 
+	if v, ok := state.GetOk("data_disk_snapshots"); ok {
+		artifact.DataDiskSnapshots = v.([]ucloudcommon.SnapshotInfo)
+	}
+
 	return artifact, nil
 }
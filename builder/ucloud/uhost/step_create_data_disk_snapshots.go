@@ -0,0 +1,121 @@
+package uhost
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/packer-plugin-sdk/multistep"
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+	"github.com/hashicorp/packer-plugin-sdk/retry"
+	ucloudcommon "github.com/hashicorp/packer/builder/ucloud/common"
+	"github.com/ucloud/ucloud-sdk-go/services/uhost"
+	"github.com/ucloud/ucloud-sdk-go/ucloud"
+)
+
+type stepCreateDataDiskSnapshots struct {
+	Zone string
+
+	snapshots []ucloudcommon.SnapshotInfo
+}
+
+func (s *stepCreateDataDiskSnapshots) Run(ctx context.Context, state multistep.StateBag) multistep.StepAction {
+	client := state.Get("client").(*ucloudcommon.UCloudClient)
+	conn := client.UDiskConn
+	instance := state.Get("instance").(*uhost.UHostInstanceSet)
+	ui := state.Get("ui").(packersdk.Ui)
+	config := state.Get("config").(*Config)
+
+	var disks []uhost.UHostDiskSet
+	for _, v := range instance.DiskSet {
+		if !strings.EqualFold(v.IsBoot, "true") {
+			disks = append(disks, v)
+		}
+	}
+	if len(disks) == 0 {
+		return multistep.ActionContinue
+	}
+
+	ui.Say("Creating data disk snapshots...")
+	for _, disk := range disks {
+		req := conn.NewCreateUDiskSnapshotRequest()
+		req.Zone = ucloud.String(s.Zone)
+		req.UDiskId = ucloud.String(disk.DiskId)
+		req.Name = ucloud.String(fmt.Sprintf("%s-%s", config.ImageName, disk.DiskId))
+		req.Comment = ucloud.String(fmt.Sprintf("Data disk snapshot of image %s", config.ImageName))
+
+		resp, err := conn.CreateUDiskSnapshot(req)
+		if err != nil {
+			return ucloudcommon.Halt(state, err, fmt.Sprintf("Error on creating snapshot of data disk %q", disk.DiskId))
+		}
+		if len(resp.SnapshotId) < 1 {
+			return ucloudcommon.Halt(state, fmt.Errorf("no snapshot returned"), fmt.Sprintf("Error on creating snapshot of data disk %q", disk.DiskId))
+		}
+
+		snapshotId := resp.SnapshotId[0]
+		s.snapshots = append(s.snapshots, ucloudcommon.SnapshotInfo{
+			SnapshotId: snapshotId,
+			DiskId:     disk.DiskId,
+			Zone:       s.Zone,
+			Size:       disk.Size,
+		})
+		ui.Message(fmt.Sprintf("Waiting for the snapshot %q of data disk %q to become available...", snapshotId, disk.DiskId))
+
+		err = retry.Config{
+			StartTimeout: time.Duration(config.WaitImageReadyTimeout) * time.Second,
+			ShouldRetry: func(err error) bool {
+				return ucloudcommon.IsExpectedStateError(err)
+			},
+			RetryDelay: (&retry.Backoff{InitialBackoff: 2 * time.Second, MaxBackoff: 12 * time.Second, Multiplier: 2}).Linear,
+		}.Run(ctx, func(ctx context.Context) error {
+			snapshot, err := client.DescribeUDiskSnapshotById(s.Zone, snapshotId)
+			if err != nil {
+				return err
+			}
+			if snapshot.Status == ucloudcommon.SnapshotStateFailed {
+				return fmt.Errorf("snapshot failed")
+			}
+			if snapshot.Status != ucloudcommon.SnapshotStateNormal {
+				return ucloudcommon.NewExpectedStateError("snapshot", snapshotId)
+			}
+
+			return nil
+		})
+
+		if err != nil {
+			return ucloudcommon.Halt(state, err, fmt.Sprintf("Error on waiting for snapshot %q to become available", snapshotId))
+		}
+	}
+
+	state.Put("data_disk_snapshots", s.snapshots)
+	ui.Message(fmt.Sprintf("Creating %d data disk snapshots complete", len(s.snapshots)))
+	return multistep.ActionContinue
+}
+
+func (s *stepCreateDataDiskSnapshots) Cleanup(state multistep.StateBag) {
+	if len(s.snapshots) == 0 {
+		return
+	}
+	_, cancelled := state.GetOk(multistep.StateCancelled)
+	_, halted := state.GetOk(multistep.StateHalted)
+	if !cancelled && !halted {
+		return
+	}
+
+	client := state.Get("client").(*ucloudcommon.UCloudClient)
+	conn := client.UDiskConn
+	ui := state.Get("ui").(packersdk.Ui)
+
+	ui.Say("Deleting data disk snapshots because of cancellation or error...")
+	for _, v := range s.snapshots {
+		req := conn.NewDeleteUDiskSnapshotRequest()
+		req.Zone = ucloud.String(v.Zone)
+		req.SnapshotId = ucloud.String(v.SnapshotId)
+		if _, err := conn.DeleteUDiskSnapshot(req); err != nil {
+			ui.Error(fmt.Sprintf("Error on deleting snapshot %q", v.SnapshotId))
+			continue
+		}
+		ui.Message(fmt.Sprintf("Deleting snapshot %q complete", v.SnapshotId))
+	}
+}
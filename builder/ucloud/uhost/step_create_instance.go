@@ -23,6 +23,7 @@ type stepCreateInstance struct {
 	InstanceType  string
 	InstanceName  string
 	BootDiskType  string
+	DataDisks     []ucloudcommon.DataDisk
 	SourceImageId string
 	UsePrivateIp  bool
 
@@ -285,6 +286,15 @@ func (s *stepCreateInstance) buildCreateInstanceRequest(state multistep.StateBag
 
 	req.Disks = append(req.Disks, bootDisk)
 
+	for _, v := range s.DataDisks {
+		dataDisk := uhost.UHostDisk{}
+		dataDisk.IsBoot = ucloud.String("false")
+		dataDisk.Size = ucloud.Int(v.Size)
+		dataDisk.Type = ucloud.String(ucloudcommon.DataDiskTypeMap.Convert(v.Type))
+
+		req.Disks = append(req.Disks, dataDisk)
+	}
+
 	if v, ok := state.GetOk("user_data"); ok {
 		req.UserData = ucloud.String(base64.StdEncoding.EncodeToString([]byte(v.(string))))
 	}
@@ -3,9 +3,12 @@ package command
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"math"
+	"os"
 	"strconv"
 	"strings"
 	"sync"
@@ -74,6 +77,9 @@ func (m *Meta) GetConfigFromHCL(cla *MetaArgs) (*hcl2template.PackerConfig, int)
 }
 
 func writeDiags(ui packersdk.Ui, files map[string]*hcl.File, diags hcl.Diagnostics) int {
+	if w, ok := ui.(diagnosticsWriter); ok {
+		return w.writeDiagnostics(diags)
+	}
 	// write HCL errors/diagnostics if any.
 	b := bytes.NewBuffer(nil)
 	err := hcl.NewDiagnosticTextWriter(b, files, 80, false).WriteDiagnostics(diags)
@@ -145,6 +151,21 @@ func (m *Meta) GetConfigFromJSON(cla *MetaArgs) (packer.Handler, int) {
 }
 
 func (c *BuildCommand) RunContext(buildCtx context.Context, cla *BuildArgs) int {
+	// With -output=json, all the output of the build is written as JSON
+	// events, and events are added for the builds and their artifacts.
+	defer func(ui packersdk.Ui) { c.Ui = ui }(c.Ui)
+	var events *packer.JSONUi
+	if cla.Output == "json" {
+		events = packer.NewJSONUi(uiWriter(c.Ui))
+		c.Ui = events
+	}
+	// With -json-diagnostics, the diagnostics of the configuration are
+	// written as JSON, the output of the builds is left as is.
+	buildUi := c.Ui
+	if cla.JSONDiagnostics {
+		c.Ui = newJSONDiagnosticsUi(buildUi)
+	}
+
 	packerStarter, ret := c.GetConfig(&cla.MetaArgs)
 	if ret != 0 {
 		return ret
@@ -161,16 +182,39 @@ func (c *BuildCommand) RunContext(buildCtx context.Context, cla *BuildArgs) int
 		Debug:   cla.Debug,
 		Force:   cla.Force,
 		OnError: cla.OnError,
+		Timeout: cla.Timeout,
+		Retries: cla.Retries,
 	})
 
 	// here, something could have gone wrong but we still want to run valid
 	// builds.
 	ret = writeDiags(c.Ui, nil, diags)
+	c.Ui = buildUi
+
+	// The hooks of the Packer config file are notified of the same events as
+	// the json output, and of the end of each provisioner, in the background.
+	hooks := packer.NewEventNotifier(c.CoreConfig.EventHooks)
+	defer hooks.Close(packer.EventHookDrainTimeout)
+	for _, b := range builds {
+		if coreBuild, ok := b.(*packer.CoreBuild); ok {
+			coreBuild.EventNotifier = hooks
+			coreBuild.Events = events
+		}
+	}
+	notify := func(event packer.JSONEvent) {
+		if events != nil {
+			events.Event(event)
+		}
+		hooks.Notify(event)
+	}
 
 	if cla.Debug {
 		c.Ui.Say("Debug mode enabled. Builds will not be parallelized.")
 	}
 
+	// Builds start after the builds they depend on
+	builds = sortBuildsByDependencies(builds)
+
 	// Compile all the UIs for the builds
 	colors := [5]packer.UiColor{
 		packer.UiColorGreen,
@@ -183,8 +227,11 @@ func (c *BuildCommand) RunContext(buildCtx context.Context, cla *BuildArgs) int
 	for i := range builds {
 		ui := c.Ui
 		if cla.Color {
-			// Only set up UI colors if -machine-readable isn't set.
-			if _, ok := c.Ui.(*packer.MachineReadableUi); !ok {
+			// Only set up UI colors if -machine-readable or -output=json
+			// isn't set.
+			switch c.Ui.(type) {
+			case *packer.MachineReadableUi, *packer.JSONUi:
+			default:
 				ui = &packer.ColoredUi{
 					Color: colors[i%len(colors)],
 					Ui:    ui,
@@ -196,8 +243,8 @@ func (c *BuildCommand) RunContext(buildCtx context.Context, cla *BuildArgs) int
 				}
 			}
 		}
-		// Now add timestamps if requested
-		if cla.TimestampUi {
+		// Now add timestamps if requested, JSON events are already timestamped
+		if cla.TimestampUi && events == nil {
 			ui = &packer.TimestampedUi{
 				Ui: ui,
 			}
@@ -209,6 +256,8 @@ func (c *BuildCommand) RunContext(buildCtx context.Context, cla *BuildArgs) int
 	log.Printf("Build debug mode: %v", cla.Debug)
 	log.Printf("Force build: %v", cla.Force)
 	log.Printf("On error: %v", cla.OnError)
+	log.Printf("Build timeout: %v", cla.Timeout)
+	log.Printf("Build retries: %v", cla.Retries)
 
 	// Get the start of the build command
 	buildCommandStart := time.Now()
@@ -225,6 +274,8 @@ func (c *BuildCommand) RunContext(buildCtx context.Context, cla *BuildArgs) int
 		m map[string]error
 	}{m: make(map[string]error)}
 	limitParallel := semaphore.NewWeighted(cla.ParallelBuilds)
+	dependencies := newBuildDependencies(builds)
+	started := 0
 	for i := range builds {
 		if err := buildCtx.Err(); err != nil {
 			log.Println("Interrupted, not going to start any more builds.")
@@ -243,24 +294,41 @@ func (c *BuildCommand) RunContext(buildCtx context.Context, cla *BuildArgs) int
 		}
 		// Increment the waitgroup so we wait for this item to finish properly
 		wg.Add(1)
+		started++
 
 		// Run the build in a goroutine
 		go func() {
-			// Get the start of the build
-			buildStart := time.Now()
-
 			defer wg.Done()
 
 			defer limitParallel.Release(1)
 
+			if err := dependencies.wait(buildCtx, b); err != nil {
+				ui.Error(fmt.Sprintf("Build '%s' was not started: %s", name, err))
+				errors.Lock()
+				errors.m[name] = err
+				errors.Unlock()
+				dependencies.finish(b, false)
+				return
+			}
+
+			// Get the start of the build
+			buildStart := time.Now()
+
 			log.Printf("Starting build run: %s", name)
+			notify(packer.JSONEvent{Type: "build-start", Build: name})
 			runArtifacts, err := b.Run(buildCtx, ui)
 
 			// Get the duration of the build and parse it
 			buildEnd := time.Now()
 			buildDuration := buildEnd.Sub(buildStart)
 			fmtBuildDuration := durafmt.Parse(buildDuration).LimitFirstN(2)
+			event := packer.JSONEvent{Type: "build-end", Build: name, Duration: buildDuration.Seconds()}
+			if err != nil {
+				event.Code = buildErrorCode(err)
+			}
+			notify(event)
 
+			dependencies.finish(b, err == nil)
 			if err != nil {
 				ui.Error(fmt.Sprintf("Build '%s' errored after %s: %s", name, fmtBuildDuration, err))
 				errors.Lock()
@@ -287,6 +355,10 @@ func (c *BuildCommand) RunContext(buildCtx context.Context, cla *BuildArgs) int
 		}
 
 	}
+	// The builds that were not started will never finish
+	for _, b := range builds[started:] {
+		dependencies.finish(b, false)
+	}
 
 	// Wait for both the builds to complete and the interrupt handler,
 	// if it is interrupted.
@@ -300,6 +372,7 @@ func (c *BuildCommand) RunContext(buildCtx context.Context, cla *BuildArgs) int
 	c.Ui.Say(fmt.Sprintf("\n==> Wait completed after %s", fmtBuildCommandDuration))
 
 	if err := buildCtx.Err(); err != nil {
+		notify(packer.JSONEvent{Type: "error", Code: "interrupted", Message: err.Error()})
 		c.Ui.Say("Cleanly cancelled builds after being interrupted.")
 		return 1
 	}
@@ -315,7 +388,10 @@ func (c *BuildCommand) RunContext(buildCtx context.Context, cla *BuildArgs) int
 				Ui:     c.Ui,
 			}
 
-			ui.Machine("error", err.Error())
+			notify(packer.JSONEvent{Type: "error", Build: name, Code: buildErrorCode(err), Message: err.Error()})
+			if events == nil {
+				ui.Machine("error", err.Error())
+			}
 
 			c.Ui.Error(fmt.Sprintf("--> %s: %s", name, err))
 		}
@@ -343,6 +419,19 @@ func (c *BuildCommand) RunContext(buildCtx context.Context, cla *BuildArgs) int
 					fmt.Fprint(&message, "<nothing>")
 				}
 
+				if artifact != nil {
+					notify(packer.JSONEvent{Type: "artifact", Build: name, Artifact: &packer.JSONArtifact{
+						BuilderId: artifact.BuilderId(),
+						Id:        artifact.Id(),
+						String:    artifact.String(),
+						Files:     artifact.Files(),
+					}})
+				}
+				if events != nil {
+					c.Ui.Say(message.String())
+					continue
+				}
+
 				iStr := strconv.FormatInt(int64(i), 10)
 				if artifact != nil {
 					ui.Machine("artifact", iStr, "builder-id", artifact.BuilderId())
@@ -377,6 +466,31 @@ func (c *BuildCommand) RunContext(buildCtx context.Context, cla *BuildArgs) int
 	return ret
 }
 
+// buildErrorCode returns the code of the error of a build, for the JSON output.
+func buildErrorCode(err error) string {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "cancelled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	default:
+		return "build_failed"
+	}
+}
+
+// uiWriter returns the writer the output of ui is written to.
+func uiWriter(ui packersdk.Ui) io.Writer {
+	switch ui := ui.(type) {
+	case *packersdk.BasicUi:
+		if ui.Writer != nil {
+			return ui.Writer
+		}
+	case *packer.JSONUi:
+		return ui.Writer
+	}
+	return os.Stdout
+}
+
 func (*BuildCommand) Help() string {
 	helpText := `
 Usage: packer build [options] TEMPLATE
@@ -391,9 +505,13 @@ Options:
   -except=foo,bar,baz           Run all builds and post-processors other than these.
   -only=foo,bar,baz             Build only the specified builds.
   -force                        Force a build to continue if artifacts exist, deletes existing artifacts.
+  -json-diagnostics             Write the errors and warnings of the template as JSON, one per line.
   -machine-readable             Produce machine-readable output.
   -on-error=[cleanup|abort|ask|run-cleanup-provisioner] If the build fails do: clean up (default), abort, ask, or run-cleanup-provisioner.
+  -output=[text|json]           Format of the output. json writes one JSON event per line. (Default: text)
   -parallel-builds=1            Number of builds to run in parallel. 1 disables parallelization. 0 means no limit (Default: 0)
+  -retries=0                    Number of times a failed build is retried from scratch. (Default: 0)
+  -timeout=0                    Maximum duration of each build, e.g. 1h30m. The build is cancelled when it is exceeded. 0 means no timeout. (Default: 0)
   -timestamp-ui                 Enable prefixing of each ui output with an RFC3339 timestamp.
   -var 'key=value'              Variable for templates, can be used multiple times.
   -var-file=path                JSON or HCL2 file containing user variables.
@@ -417,9 +535,13 @@ func (*BuildCommand) AutocompleteFlags() complete.Flags {
 		"-except":           complete.PredictNothing,
 		"-only":             complete.PredictNothing,
 		"-force":            complete.PredictNothing,
+		"-json-diagnostics": complete.PredictNothing,
 		"-machine-readable": complete.PredictNothing,
 		"-on-error":         complete.PredictNothing,
+		"-output":           complete.PredictSet("text", "json"),
 		"-parallel":         complete.PredictNothing,
+		"-retries":          complete.PredictNothing,
+		"-timeout":          complete.PredictNothing,
 		"-timestamp-ui":     complete.PredictNothing,
 		"-var":              complete.PredictNothing,
 		"-var-file":         complete.PredictNothing,
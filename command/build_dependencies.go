@@ -0,0 +1,114 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+	"github.com/hashicorp/packer/packer"
+)
+
+// buildBlockName returns the name of the build block of a build, and the
+// names of the build blocks it depends on.
+func buildBlockName(b packersdk.Build) (string, []string) {
+	if cb, ok := b.(*packer.CoreBuild); ok {
+		return cb.BuildName, cb.DependsOn
+	}
+	return "", nil
+}
+
+// sortBuildsByDependencies orders builds so that every build comes after the
+// builds it depends on, and otherwise keeps their order.
+func sortBuildsByDependencies(builds []packersdk.Build) []packersdk.Build {
+	sorted := make([]packersdk.Build, 0, len(builds))
+	added := map[packersdk.Build]bool{}
+	var add func(b packersdk.Build)
+	add = func(b packersdk.Build) {
+		if added[b] {
+			return
+		}
+		added[b] = true
+		_, dependsOn := buildBlockName(b)
+		for _, dep := range dependsOn {
+			for _, other := range builds {
+				if name, _ := buildBlockName(other); name == dep {
+					add(other)
+				}
+			}
+		}
+		sorted = append(sorted, b)
+	}
+	for _, b := range builds {
+		add(b)
+	}
+	return sorted
+}
+
+// buildDependencies lets builds wait for the builds they depend on.
+type buildDependencies struct {
+	l      sync.Mutex
+	blocks map[string]*buildBlockState
+}
+
+// buildBlockState is the state of the builds of a build block.
+type buildBlockState struct {
+	remaining int
+	failed    bool
+	// done is closed when all the builds of the block finished.
+	done chan struct{}
+}
+
+func newBuildDependencies(builds []packersdk.Build) *buildDependencies {
+	d := &buildDependencies{blocks: map[string]*buildBlockState{}}
+	for _, b := range builds {
+		name, _ := buildBlockName(b)
+		state, ok := d.blocks[name]
+		if !ok {
+			state = &buildBlockState{done: make(chan struct{})}
+			d.blocks[name] = state
+		}
+		state.remaining++
+	}
+	return d
+}
+
+// finish records that the build b finished, or will never run.
+func (d *buildDependencies) finish(b packersdk.Build, succeeded bool) {
+	name, _ := buildBlockName(b)
+	d.l.Lock()
+	defer d.l.Unlock()
+	state := d.blocks[name]
+	if !succeeded {
+		state.failed = true
+	}
+	state.remaining--
+	if state.remaining == 0 {
+		close(state.done)
+	}
+}
+
+// wait waits for the builds b depends on to finish. It fails when one of
+// them failed. The builds that are not run, for example because of -only,
+// are not waited for.
+func (d *buildDependencies) wait(ctx context.Context, b packersdk.Build) error {
+	_, dependsOn := buildBlockName(b)
+	for _, dep := range dependsOn {
+		state, ok := d.blocks[dep]
+		if !ok {
+			continue
+		}
+		select {
+		case <-state.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		d.l.Lock()
+		failed := state.failed
+		d.l.Unlock()
+		if failed {
+			return fmt.Errorf("the build %q it depends on failed", dep)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package command
+
+import (
+	"context"
+	"testing"
+
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+	"github.com/hashicorp/packer/packer"
+)
+
+func TestSortBuildsByDependencies(t *testing.T) {
+	app := &packer.CoreBuild{BuildName: "app", DependsOn: []string{"base"}}
+	base1 := &packer.CoreBuild{BuildName: "base"}
+	base2 := &packer.CoreBuild{BuildName: "base", DependsOn: []string{"os"}}
+	os := &packer.CoreBuild{BuildName: "os"}
+	other := &packer.CoreBuild{BuildName: "other"}
+
+	got := sortBuildsByDependencies([]packersdk.Build{app, other, base1, base2, os})
+	want := []packersdk.Build{base1, os, base2, app, other}
+	if len(got) != len(want) {
+		t.Fatalf("got %d builds, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			name, _ := buildBlockName(got[i])
+			t.Fatalf("unexpected build %q at %d", name, i)
+		}
+	}
+}
+
+func TestBuildDependencies(t *testing.T) {
+	app := &packer.CoreBuild{BuildName: "app", DependsOn: []string{"base", "excluded"}}
+	base1 := &packer.CoreBuild{BuildName: "base"}
+	base2 := &packer.CoreBuild{BuildName: "base"}
+	dependencies := newBuildDependencies([]packersdk.Build{base1, base2, app})
+
+	waited := make(chan error)
+	go func() { waited <- dependencies.wait(context.Background(), app) }()
+
+	dependencies.finish(base1, true)
+	select {
+	case err := <-waited:
+		t.Fatalf("wait returned before all the builds it depends on finished: %v", err)
+	default:
+	}
+	dependencies.finish(base2, false)
+	if err := <-waited; err == nil {
+		t.Fatal("wait should fail when a build it depends on failed")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	dependencies = newBuildDependencies([]packersdk.Build{base1, app})
+	if err := dependencies.wait(ctx, app); err != context.Canceled {
+		t.Fatalf("wait should stop when the context is cancelled, got: %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package command
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"math"
@@ -10,6 +11,7 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/go-uuid"
@@ -523,6 +525,53 @@ func TestBuildStdin(t *testing.T) {
 	}
 }
 
+func TestBuildJSONOutput(t *testing.T) {
+	c := &BuildCommand{
+		Meta: testMetaFile(t),
+	}
+
+	args := []string{
+		"-output=json",
+		"-only=chocolate",
+		filepath.Join(testFixture("build-only"), "template.json"),
+	}
+
+	defer cleanup()
+
+	if code := c.Run(args); code != 0 {
+		fatalCommand(t, c.Meta)
+	}
+
+	out, _ := outputCommand(t, c.Meta)
+	types := map[string]packer.JSONEvent{}
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		var event packer.JSONEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("output line %q is not a JSON event: %s", line, err)
+		}
+		if event.Timestamp == "" {
+			t.Fatalf("event %q has no timestamp", line)
+		}
+		types[event.Type] = event
+	}
+
+	for _, typ := range []string{"ui", "build-start", "build-end", "artifact"} {
+		event, ok := types[typ]
+		if !ok {
+			t.Fatalf("expected a %q event in output:\n%s", typ, out)
+		}
+		if typ != "ui" && event.Build != "chocolate" {
+			t.Errorf("expected %q event of build chocolate, got %#v", typ, event)
+		}
+	}
+	if artifact := types["artifact"].Artifact; artifact == nil || len(artifact.Files) != 1 || artifact.Files[0] != "chocolate.txt" {
+		t.Errorf("unexpected artifact %#v", artifact)
+	}
+	if code := types["build-end"].Code; code != "" {
+		t.Errorf("unexpected error code %q", code)
+	}
+}
+
 func TestBuildOnlyFileMultipleFlags(t *testing.T) {
 	c := &BuildCommand{
 		Meta: testMetaFile(t),
@@ -584,6 +633,49 @@ func TestBuildProvisionAndPosProcessWithBuildVariablesSharing(t *testing.T) {
 	}
 }
 
+func TestBuildDependsOn(t *testing.T) {
+	c := &BuildCommand{
+		Meta: testMetaFile(t),
+	}
+
+	args := []string{
+		"-parallel-builds=1",
+		filepath.Join(testFixture("hcl", "depends-on"), "build.pkr.hcl"),
+	}
+
+	defer cleanup("base.txt", "app.txt")
+
+	if code := c.Run(args); code != 0 {
+		fatalCommand(t, c.Meta)
+	}
+
+	for _, f := range []string{"base.txt", "app.txt"} {
+		if !fileExists(f) {
+			t.Errorf("Expected to find %s", f)
+		}
+	}
+}
+
+func TestBuildDependsOn_failed(t *testing.T) {
+	c := &BuildCommand{
+		Meta: testMetaFile(t),
+	}
+
+	args := []string{
+		filepath.Join(testFixture("hcl", "depends-on"), "failed.pkr.hcl"),
+	}
+
+	defer cleanup("base.txt", "app.txt")
+
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("Expected the build to fail, got exit code %d", code)
+	}
+
+	if fileExists("app.txt") {
+		t.Error("Expected NOT to find app.txt")
+	}
+}
+
 func TestBuildEverything(t *testing.T) {
 	c := &BuildCommand{
 		Meta: testMetaFile(t),
@@ -1042,6 +1134,23 @@ func TestBuildCommand_ParseArgs(t *testing.T) {
 			},
 			0,
 		},
+		{fields{defaultMeta},
+			args{[]string{"-timeout=1h30m", "file.json"}},
+			&BuildArgs{
+				MetaArgs:       MetaArgs{Path: "file.json"},
+				ParallelBuilds: math.MaxInt64,
+				Color:          true,
+				Timeout:        90 * time.Minute,
+			},
+			0,
+		},
+		{fields{defaultMeta},
+			args{[]string{"-timeout=-1h", "file.json"}},
+			&BuildArgs{
+				Color: true,
+			},
+			1,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(fmt.Sprintf("%s", tt.args.args), func(t *testing.T) {
@@ -1,8 +1,10 @@
 package command
 
 import (
+	"errors"
 	"flag"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/packer/command/enumflag"
 	kvflag "github.com/hashicorp/packer/command/flag-kv"
@@ -70,18 +72,43 @@ type MetaArgs struct {
 	ConfigType configType
 }
 
+// timeoutFlag is a duration flag that can't be negative.
+type timeoutFlag time.Duration
+
+func (t *timeoutFlag) String() string {
+	return time.Duration(*t).String()
+}
+
+func (t *timeoutFlag) Set(value string) error {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	if d < 0 {
+		return errors.New("the timeout must not be negative")
+	}
+	*t = timeoutFlag(d)
+	return nil
+}
+
 func (ba *BuildArgs) AddFlagSets(flags *flag.FlagSet) {
 	flags.BoolVar(&ba.Color, "color", true, "")
 	flags.BoolVar(&ba.Debug, "debug", false, "")
 	flags.BoolVar(&ba.Force, "force", false, "")
 	flags.BoolVar(&ba.TimestampUi, "timestamp-ui", false, "")
 	flags.BoolVar(&ba.MachineReadable, "machine-readable", false, "")
+	flags.BoolVar(&ba.JSONDiagnostics, "json-diagnostics", false, "")
 
 	flags.Int64Var(&ba.ParallelBuilds, "parallel-builds", 0, "")
+	flags.Var((*timeoutFlag)(&ba.Timeout), "timeout", "")
+	flags.IntVar(&ba.Retries, "retries", 0, "")
 
 	flagOnError := enumflag.New(&ba.OnError, "cleanup", "abort", "ask", "run-cleanup-provisioner")
 	flags.Var(flagOnError, "on-error", "")
 
+	flagOutput := enumflag.New(&ba.Output, "text", "json")
+	flags.Var(flagOutput, "output", "")
+
 	ba.MetaArgs.AddFlagSets(flags)
 }
 
@@ -91,6 +118,14 @@ type BuildArgs struct {
 	Color, Debug, Force, TimestampUi, MachineReadable bool
 	ParallelBuilds                                    int64
 	OnError                                           string
+	// The format of the output, "text" or "json"
+	Output string
+	// The maximum duration of each build
+	Timeout time.Duration
+	// The number of times a failed build is retried
+	Retries int
+	// Write the diagnostics of the template as JSON
+	JSONDiagnostics bool
 }
 
 func (ia *InitArgs) AddFlagSets(flags *flag.FlagSet) {
@@ -105,6 +140,34 @@ type InitArgs struct {
 	Upgrade bool
 }
 
+func (pa *PluginsInstallArgs) AddFlagSets(flags *flag.FlagSet) {
+	flags.StringVar(&pa.Path, "path", "", "install the plugin from a local binary")
+}
+
+// PluginsInstallArgs represents a parsed cli line for a `packer plugins install`
+type PluginsInstallArgs struct {
+	// Plugin is the source of the plugin, like github.com/hashicorp/amazon
+	Plugin string
+	// Version is an optional version constraint
+	Version string
+	Path    string
+}
+
+func (pa *PluginsMirrorArgs) AddFlagSets(flags *flag.FlagSet) {
+	flags.Var((*sliceflag.StringFlag)(&pa.Platforms), "platform", "")
+
+	pa.MetaArgs.AddFlagSets(flags)
+}
+
+// PluginsMirrorArgs represents a parsed cli line for a `packer plugins mirror`
+type PluginsMirrorArgs struct {
+	MetaArgs
+	// Folder of the mirror
+	Folder string
+	// Platforms to mirror, like linux_amd64
+	Platforms []string
+}
+
 // ConsoleArgs represents a parsed cli line for a `packer console`
 type ConsoleArgs struct {
 	MetaArgs
@@ -124,6 +187,7 @@ type FixArgs struct {
 
 func (va *ValidateArgs) AddFlagSets(flags *flag.FlagSet) {
 	flags.BoolVar(&va.SyntaxOnly, "syntax-only", false, "check syntax only")
+	flags.BoolVar(&va.JSONDiagnostics, "json-diagnostics", false, "write diagnostics as JSON")
 
 	va.MetaArgs.AddFlagSets(flags)
 }
@@ -131,16 +195,37 @@ func (va *ValidateArgs) AddFlagSets(flags *flag.FlagSet) {
 // ValidateArgs represents a parsed cli line for a `packer validate`
 type ValidateArgs struct {
 	MetaArgs
-	SyntaxOnly bool
+	SyntaxOnly      bool
+	JSONDiagnostics bool
+}
+
+func (pa *PlanArgs) AddFlagSets(flags *flag.FlagSet) {
+	pa.MetaArgs.AddFlagSets(flags)
+}
+
+// PlanArgs represents a parsed cli line for a `packer plan`
+type PlanArgs struct {
+	MetaArgs
+}
+
+func (ta *TestArgs) AddFlagSets(flags *flag.FlagSet) {
+	ta.MetaArgs.AddFlagSets(flags)
+}
+
+// TestArgs represents a parsed cli line for a `packer test`
+type TestArgs struct {
+	MetaArgs
 }
 
 func (va *InspectArgs) AddFlagSets(flags *flag.FlagSet) {
+	flags.BoolVar(&va.JSON, "json", false, "write the configuration as JSON")
 	va.MetaArgs.AddFlagSets(flags)
 }
 
 // InspectArgs represents a parsed cli line for a `packer inspect`
 type InspectArgs struct {
 	MetaArgs
+	JSON bool
 }
 
 func (va *HCL2UpgradeArgs) AddFlagSets(flags *flag.FlagSet) {
@@ -162,11 +247,12 @@ func (va *FormatArgs) AddFlagSets(flags *flag.FlagSet) {
 	flags.BoolVar(&va.Diff, "diff", false, "display the diff of formatting changes")
 	flags.BoolVar(&va.Write, "write", true, "overwrite source files instead of writing to stdout")
 	flags.BoolVar(&va.Recursive, "recursive", false, "Also process files in subdirectories")
+	flags.BoolVar(&va.SortAttributes, "sort-attributes", false, "Sort the attributes of blocks by name")
 	va.MetaArgs.AddFlagSets(flags)
 }
 
 // FormatArgs represents a parsed cli line for `packer fmt`
 type FormatArgs struct {
 	MetaArgs
-	Check, Diff, Write, Recursive bool
+	Check, Diff, Write, Recursive, SortAttributes bool
 }
@@ -5,9 +5,16 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/chzyer/readline"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/packer-plugin-sdk/pathing"
+	"github.com/hashicorp/packer/hcl2template"
 	"github.com/hashicorp/packer/helper/wrappedreadline"
 	"github.com/hashicorp/packer/helper/wrappedstreams"
 	"github.com/hashicorp/packer/packer"
@@ -106,14 +113,30 @@ func (c *ConsoleCommand) modePiped(cfg packer.Evaluator) int {
 	var lastResult string
 	scanner := bufio.NewScanner(wrappedstreams.Stdin())
 	ret := 0
-	for scanner.Scan() {
-		result, _, diags := cfg.EvaluateExpression(strings.TrimSpace(scanner.Text()))
+	evaluate := func(input string) {
+		result, _, diags := cfg.EvaluateExpression(input)
 		if len(diags) > 0 {
 			ret = writeDiags(c.Ui, nil, diags)
 		}
 		// Store the last result
 		lastResult = result
 	}
+	input := ""
+	for scanner.Scan() {
+		if input == "" {
+			input = strings.TrimSpace(scanner.Text())
+		} else {
+			input += "\n" + scanner.Text()
+		}
+		if !consoleInputComplete(cfg, input) {
+			continue
+		}
+		evaluate(input)
+		input = ""
+	}
+	if input != "" {
+		evaluate(input)
+	}
 
 	// Output the final result
 	c.Ui.Message(lastResult)
@@ -126,6 +149,7 @@ func (c *ConsoleCommand) modeInteractive(cfg packer.Evaluator) int {
 		Prompt:            "> ",
 		InterruptPrompt:   "^C",
 		EOFPrompt:         "exit",
+		HistoryFile:       consoleHistoryFile(),
 		HistorySearchFold: true,
 	}))
 	if err != nil {
@@ -134,18 +158,36 @@ func (c *ConsoleCommand) modeInteractive(cfg packer.Evaluator) int {
 			err))
 		return 1
 	}
+	defer l.Close()
+
+	// Expressions with unclosed brackets or heredocs continue on the next
+	// lines.
+	input := ""
 	for {
 		// Read a line
 		line, err := l.Readline()
 		if err == readline.ErrInterrupt {
-			if len(line) == 0 {
+			if len(line) == 0 && input == "" {
 				break
 			} else {
+				input = ""
+				l.SetPrompt("> ")
 				continue
 			}
 		} else if err == io.EOF {
 			break
 		}
+		if input != "" {
+			line = input + "\n" + line
+		}
+		if !consoleInputComplete(cfg, line) {
+			input = line
+			l.SetPrompt("... ")
+			continue
+		}
+		input = ""
+		l.SetPrompt("> ")
+
 		out, exit, diags := cfg.EvaluateExpression(line)
 		ret := writeDiags(c.Ui, nil, diags)
 		if exit {
@@ -159,3 +201,38 @@ func (c *ConsoleCommand) modeInteractive(cfg packer.Evaluator) int {
 
 	return 0
 }
+
+// consoleInputComplete tells whether input is a complete HCL2 expression, or
+// if it has brackets or a heredoc that are not closed yet. Legacy JSON
+// templates are evaluated one line at a time.
+func consoleInputComplete(cfg packer.Evaluator, input string) bool {
+	if _, ok := cfg.(*hcl2template.PackerConfig); !ok {
+		return true
+	}
+	tokens, _ := hclsyntax.LexExpression([]byte(input), "<console-input>", hcl.InitialPos)
+	depth := 0
+	for _, token := range tokens {
+		switch token.Type {
+		case hclsyntax.TokenOBrace, hclsyntax.TokenOBrack, hclsyntax.TokenOParen, hclsyntax.TokenOHeredoc:
+			depth++
+		case hclsyntax.TokenCBrace, hclsyntax.TokenCBrack, hclsyntax.TokenCParen, hclsyntax.TokenCHeredoc:
+			depth--
+		}
+	}
+	return depth <= 0
+}
+
+// consoleHistoryFile returns the file the history of the console is kept in,
+// or an empty string to only keep it in memory.
+func consoleHistoryFile() string {
+	dir, err := pathing.ConfigDir()
+	if err != nil {
+		log.Printf("[WARN] Could not find the config dir, the console history won't be saved: %s", err)
+		return ""
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		log.Printf("[WARN] Could not create %s, the console history won't be saved: %s", dir, err)
+		return ""
+	}
+	return filepath.Join(dir, "console_history")
+}
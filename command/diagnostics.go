@@ -0,0 +1,108 @@
+package command
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/hcl/v2"
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+)
+
+// diagnosticsWriter is implemented by the UIs that write diagnostics in their
+// own format, see writeDiags.
+type diagnosticsWriter interface {
+	writeDiagnostics(diags hcl.Diagnostics) int
+}
+
+// jsonDiagnostic is a diagnostic, as written with -json-diagnostics.
+type jsonDiagnostic struct {
+	Severity string     `json:"severity"`
+	Summary  string     `json:"summary"`
+	Detail   string     `json:"detail,omitempty"`
+	Range    *jsonRange `json:"range,omitempty"`
+}
+
+type jsonRange struct {
+	Filename string  `json:"filename"`
+	Start    jsonPos `json:"start"`
+	End      jsonPos `json:"end"`
+}
+
+type jsonPos struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	Byte   int `json:"byte"`
+}
+
+// jsonDiagnosticsUi writes the diagnostics of a configuration as JSON, one
+// diagnostic per line, so that editors and CI systems can show them next to
+// the code they are about. Errors that are not HCL diagnostics, like the
+// errors of legacy JSON templates, are written as diagnostics without a
+// range.
+type jsonDiagnosticsUi struct {
+	packersdk.Ui
+
+	w io.Writer
+	l sync.Mutex
+}
+
+func newJSONDiagnosticsUi(ui packersdk.Ui) *jsonDiagnosticsUi {
+	return &jsonDiagnosticsUi{
+		Ui: ui,
+		w:  uiWriter(ui),
+	}
+}
+
+func (u *jsonDiagnosticsUi) Error(message string) {
+	summary, detail := message, ""
+	if i := strings.Index(message, "\n"); i >= 0 {
+		summary, detail = message[:i], strings.TrimSpace(message[i+1:])
+	}
+	u.write(jsonDiagnostic{
+		Severity: "error",
+		Summary:  summary,
+		Detail:   detail,
+	})
+}
+
+func (u *jsonDiagnosticsUi) writeDiagnostics(diags hcl.Diagnostics) int {
+	for _, diag := range diags {
+		d := jsonDiagnostic{
+			Severity: "error",
+			Summary:  diag.Summary,
+			Detail:   diag.Detail,
+		}
+		if diag.Severity == hcl.DiagWarning {
+			d.Severity = "warning"
+		}
+		rng := diag.Subject
+		if rng == nil {
+			rng = diag.Context
+		}
+		if rng != nil {
+			d.Range = &jsonRange{
+				Filename: rng.Filename,
+				Start:    jsonPos{rng.Start.Line, rng.Start.Column, rng.Start.Byte},
+				End:      jsonPos{rng.End.Line, rng.End.Column, rng.End.Byte},
+			}
+		}
+		u.write(d)
+	}
+	if diags.HasErrors() {
+		return 1
+	}
+	return 0
+}
+
+func (u *jsonDiagnosticsUi) write(d jsonDiagnostic) {
+	line, err := json.Marshal(d)
+	if err != nil {
+		u.Ui.Error("could not write diagnostic: " + err.Error())
+		return
+	}
+	u.l.Lock()
+	defer u.l.Unlock()
+	u.w.Write(append(line, '\n'))
+}
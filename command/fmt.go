@@ -48,10 +48,11 @@ func (c *FormatCommand) RunContext(ctx context.Context, cla *FormatArgs) int {
 	}
 
 	formatter := hclutils.HCL2Formatter{
-		ShowDiff:  cla.Diff,
-		Write:     cla.Write,
-		Output:    os.Stdout,
-		Recursive: cla.Recursive,
+		ShowDiff:       cla.Diff,
+		Write:          cla.Write,
+		Output:         os.Stdout,
+		Recursive:      cla.Recursive,
+		SortAttributes: cla.SortAttributes,
 	}
 
 	bytesModified, diags := formatter.Format(cla.Path)
@@ -93,6 +94,10 @@ Options:
 
   -recursive     Also process files in subdirectories. By default, only the
                  given directory (or current directory) is processed.
+
+  -sort-attributes  Sort the attributes of each block by name. Attributes
+                 separated by an empty line or a nested block are sorted
+                 separately.
 `
 
 	return strings.TrimSpace(helpText)
@@ -108,9 +113,10 @@ func (*FormatCommand) AutocompleteArgs() complete.Predictor {
 
 func (*FormatCommand) AutocompleteFlags() complete.Flags {
 	return complete.Flags{
-		"-check":     complete.PredictNothing,
-		"-diff":      complete.PredictNothing,
-		"-write":     complete.PredictNothing,
-		"-recursive": complete.PredictNothing,
+		"-check":           complete.PredictNothing,
+		"-diff":            complete.PredictNothing,
+		"-write":           complete.PredictNothing,
+		"-recursive":       complete.PredictNothing,
+		"-sort-attributes": complete.PredictNothing,
 	}
 }
@@ -376,31 +376,19 @@ func transposeTemplatingCalls(s []byte) []byte {
 		"uuid": func() string {
 			return fmt.Sprintf("${uuidv4()}")
 		},
-		"lower": func(a string) (string, error) {
-			funcErrors = multierror.Append(funcErrors, UnhandleableArgumentError{
-				"lower",
-				"`lower(var.example)`",
-				"https://www.packer.io/docs/templates/hcl_templates/functions/string/lower",
-			})
-			return fmt.Sprintf("{{ lower `%s` }}", a), nil
+		"lower": func(a string) string {
+			return fmt.Sprintf("${lower(%s)}", hcl2Expression(a))
 		},
-		"upper": func(a string) (string, error) {
-			funcErrors = multierror.Append(funcErrors, UnhandleableArgumentError{
-				"upper",
-				"`upper(var.example)`",
-				"https://www.packer.io/docs/templates/hcl_templates/functions/string/upper",
-			})
-			return fmt.Sprintf("{{ upper `%s` }}", a), nil
+		"upper": func(a string) string {
+			return fmt.Sprintf("${upper(%s)}", hcl2Expression(a))
 		},
-		"split": func(a, b string, n int) (string, error) {
-			funcErrors = multierror.Append(funcErrors, UnhandleableArgumentError{
-				"split",
-				"`split(separator, string)`",
-				"https://www.packer.io/docs/templates/hcl_templates/functions/string/split",
-			})
-			return fmt.Sprintf("{{ split `%s` `%s` %d }}", a, b, n), nil
+		"split": func(a, b string, n int) string {
+			return fmt.Sprintf("${split(%s, %s)[%d]}", hcl2Expression(b), hcl2Expression(a), n)
 		},
 		"replace": func(a, b string, n int, c string) (string, error) {
+			if n < 0 && !isHCL2Regex(a) {
+				return fmt.Sprintf("${replace(%s, %s, %s)}", hcl2Expression(c), hcl2Expression(a), hcl2Expression(b)), nil
+			}
 			funcErrors = multierror.Append(funcErrors, UnhandleableArgumentError{
 				"replace",
 				"`replace(string, substring, replacement)` or `regex_replace(string, substring, replacement)`",
@@ -409,6 +397,9 @@ func transposeTemplatingCalls(s []byte) []byte {
 			return fmt.Sprintf("{{ replace `%s` `%s` `%s` %d }}", a, b, c, n), nil
 		},
 		"replace_all": func(a, b, c string) (string, error) {
+			if !isHCL2Regex(a) {
+				return fmt.Sprintf("${replace(%s, %s, %s)}", hcl2Expression(c), hcl2Expression(a), hcl2Expression(b)), nil
+			}
 			funcErrors = multierror.Append(funcErrors, UnhandleableArgumentError{
 				"replace_all",
 				"`replace(string, substring, replacement)` or `regex_replace(string, substring, replacement)`",
@@ -472,6 +463,70 @@ func transposeTemplatingCalls(s []byte) []byte {
 	return out
 }
 
+// hcl2Expression returns s, the result of an upgraded templating call, as an
+// HCL2 expression that can be passed to a function. For example, the result
+// of {{ user `name` }}, "${var.name}", becomes var.name so that
+// {{ lower (user `name`) }} becomes "${lower(var.name)}"; anything else is
+// quoted, keeping its interpolations.
+func hcl2Expression(s string) string {
+	if strings.HasPrefix(s, "${") && interpolationEnd(s, 0) == len(s) {
+		return s[2 : len(s)-1]
+	}
+
+	b := &strings.Builder{}
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		if strings.HasPrefix(s[i:], "${") {
+			end := interpolationEnd(s, i)
+			b.WriteString(s[i:end])
+			i = end - 1
+			continue
+		}
+		switch s[i] {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(s[i])
+		case '\n':
+			b.WriteString(`\n`)
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// interpolationEnd returns the index following the end of the interpolation
+// starting at start in s, or len(s) when it is not closed.
+func interpolationEnd(s string, start int) int {
+	depth := 0
+	inString := false
+	for i := start + 1; i < len(s); i++ {
+		switch {
+		case inString && s[i] == '\\':
+			i++
+		case s[i] == '"':
+			inString = !inString
+		case inString:
+		case s[i] == '{':
+			depth++
+		case s[i] == '}':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(s)
+}
+
+// isHCL2Regex tells whether substring would be used as a regular expression
+// by the HCL2 replace function, which is the case when it is wrapped in
+// forward slashes.
+func isHCL2Regex(substring string) bool {
+	return len(substring) > 1 && strings.HasPrefix(substring, "/") && strings.HasSuffix(substring, "/")
+}
+
 // variableTransposeTemplatingCalls executes parts of blocks as go template files and replaces
 // their result with their hcl2 variant for variables block only. If something goes wrong the template
 // containing the go template string is returned.
@@ -1076,18 +1131,18 @@ func (p *ProvisionerParser) Parse(tpl *template.Template) error {
 		p.out = []byte{}
 	}
 	for _, provisioner := range tpl.Provisioners {
-		contentBytes := writeProvisioner("provisioner", provisioner)
+		contentBytes := writeProvisioner(tpl, "provisioner", provisioner)
 		p.out = append(p.out, transposeTemplatingCalls(contentBytes)...)
 	}
 
 	if tpl.CleanupProvisioner != nil {
-		contentBytes := writeProvisioner("error-cleanup-provisioner", tpl.CleanupProvisioner)
+		contentBytes := writeProvisioner(tpl, "error-cleanup-provisioner", tpl.CleanupProvisioner)
 		p.out = append(p.out, transposeTemplatingCalls(contentBytes)...)
 	}
 	return nil
 }
 
-func writeProvisioner(typeName string, provisioner *template.Provisioner) []byte {
+func writeProvisioner(tpl *template.Template, typeName string, provisioner *template.Provisioner) []byte {
 	provisionerContent := hclwrite.NewEmptyFile()
 	body := provisionerContent.Body()
 	block := body.AppendNewBlock(typeName, []string{provisioner.Type})
@@ -1098,10 +1153,10 @@ func writeProvisioner(typeName string, provisioner *template.Provisioner) []byte
 	}
 
 	if len(provisioner.Except) > 0 {
-		cfg["except"] = provisioner.Except
+		cfg["except"] = upgradeOnlyExcept(tpl, provisioner.Except)
 	}
 	if len(provisioner.Only) > 0 {
-		cfg["only"] = provisioner.Only
+		cfg["only"] = upgradeOnlyExcept(tpl, provisioner.Only)
 	}
 	if provisioner.MaxRetries != "" {
 		cfg["max_retries"] = provisioner.MaxRetries
@@ -1114,9 +1169,36 @@ func writeProvisioner(typeName string, provisioner *template.Provisioner) []byte
 	}
 	body.AppendNewline()
 	jsonBodyToHCL2Body(block.Body(), cfg)
+
+	if len(provisioner.Override) > 0 {
+		// Overrides are keyed by builder name in JSON, and by source name in
+		// HCL2.
+		override := make(map[string]interface{}, len(provisioner.Override))
+		for name, cfg := range provisioner.Override {
+			if builder, ok := tpl.Builders[name]; ok {
+				name = builder.Name
+			}
+			override[name] = cfg
+		}
+		block.Body().SetAttributeValue("override", hcl2shim.HCL2ValueFromConfigValue(override))
+	}
 	return provisionerContent.Bytes()
 }
 
+// upgradeOnlyExcept returns the names of the sources generated for the
+// builders named in the only or except option of a provisioner or a
+// post-processor, since these options take the type.name of sources in HCL2.
+func upgradeOnlyExcept(tpl *template.Template, names []string) []string {
+	res := make([]string, 0, len(names))
+	for _, name := range names {
+		if builder, ok := tpl.Builders[name]; ok {
+			name = fmt.Sprintf("%s.%s", builder.Type, builder.Name)
+		}
+		res = append(res, name)
+	}
+	return res
+}
+
 func (p *ProvisionerParser) Write(out *bytes.Buffer) {
 	if len(p.out) > 0 {
 		out.Write(p.out)
@@ -1154,10 +1236,10 @@ func (p *PostProcessorParser) Parse(tpl *template.Template) error {
 			}
 
 			if len(pp.Except) > 0 {
-				cfg["except"] = pp.Except
+				cfg["except"] = upgradeOnlyExcept(tpl, pp.Except)
 			}
 			if len(pp.Only) > 0 {
-				cfg["only"] = pp.Only
+				cfg["only"] = upgradeOnlyExcept(tpl, pp.Only)
 			}
 			if pp.Name != "" && pp.Name != pp.Type {
 				cfg["name"] = pp.Name
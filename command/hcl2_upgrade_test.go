@@ -30,6 +30,7 @@ func Test_hcl2_upgrade(t *testing.T) {
 		{folder: "variables-with-variables", flags: []string{}},
 		{folder: "complete-variables-with-template-engine", flags: []string{}},
 		{folder: "escaping", flags: []string{}},
+		{folder: "overrides", flags: []string{}},
 	}
 
 	for _, tc := range tc {
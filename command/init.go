@@ -5,13 +5,17 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
 	pluginsdk "github.com/hashicorp/packer-plugin-sdk/plugin"
 	"github.com/hashicorp/packer/packer"
 	plugingetter "github.com/hashicorp/packer/packer/plugin-getter"
 	"github.com/hashicorp/packer/packer/plugin-getter/github"
+	pluginmirror "github.com/hashicorp/packer/packer/plugin-getter/mirror"
 	"github.com/hashicorp/packer/version"
 	"github.com/posener/complete"
 )
@@ -63,47 +67,34 @@ func (c *InitCommand) RunContext(buildCtx context.Context, cla *InitArgs) int {
 		return ret
 	}
 
-	opts := plugingetter.ListInstallationsOptions{
-		FromFolders: c.Meta.CoreConfig.Components.PluginConfig.KnownPluginFolders,
-		BinaryInstallationOptions: plugingetter.BinaryInstallationOptions{
-			OS:              runtime.GOOS,
-			ARCH:            runtime.GOARCH,
-			APIVersionMajor: pluginsdk.APIVersionMajor,
-			APIVersionMinor: pluginsdk.APIVersionMinor,
-			Checksummers: []plugingetter.Checksummer{
-				{Type: "sha256", Hash: sha256.New()},
-			},
-		},
-	}
-
-	if runtime.GOOS == "windows" && opts.Ext == "" {
-		opts.BinaryInstallationOptions.Ext = ".exe"
-	}
+	opts := pluginInstallationOptions(c.Meta.CoreConfig.Components.PluginConfig.KnownPluginFolders)
 
 	log.Printf("[TRACE] init: %#v", opts)
 
-	getters := []plugingetter.Getter{
-		&github.Getter{
-			// In the past some terraform plugins downloads were blocked from a
-			// specific aws region by s3. Changing the user agent unblocked the
-			// downloads so having one user agent per version will help mitigate
-			// that a little more. Especially in the case someone forks this
-			// code to make it more aggressive or something.
-			// TODO: allow to set this from the config file or an environment
-			// variable.
-			UserAgent: "packer-getter-github-" + version.String(),
-		},
-	}
+	getters := pluginGetters(c.Meta.CoreConfig.Components.PluginConfig.PluginMirror)
 
 	ui := &packer.ColoredUi{
 		Color: packer.UiColorCyan,
 		Ui:    c.Ui,
 	}
 
+	// The versions selected by previous runs are kept, unless upgrading.
+	lockPath := lockFilePath(cla.Path)
+	lock, err := plugingetter.ReadLockFile(lockPath)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to read the plugins lock file %q: %s", lockPath, err))
+		return 1
+	}
+
 	for _, pluginRequirement := range reqs {
+		installRequirement := pluginRequirement
+		if locked := lock.Locked(pluginRequirement); locked != nil && !cla.Upgrade {
+			installRequirement = locked
+		}
+
 		// Get installed plugins that match requirement
 
-		installs, err := pluginRequirement.ListInstallations(opts)
+		installs, err := installRequirement.ListInstallations(opts)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -111,38 +102,69 @@ func (c *InitCommand) RunContext(buildCtx context.Context, cla *InitArgs) int {
 
 		log.Printf("[TRACE] for plugin %s found %d matching installation(s)", pluginRequirement.Identifier, len(installs))
 
-		if len(installs) > 0 && cla.Upgrade == false {
-			continue
+		if len(installs) == 0 || cla.Upgrade {
+			if c.installLatest(ui, installRequirement, opts, getters) != 0 {
+				ret = 1
+			}
 		}
 
-		newInstall, err := pluginRequirement.InstallLatest(plugingetter.InstallOptions{
-			InFolders:                 opts.FromFolders,
-			BinaryInstallationOptions: opts.BinaryInstallationOptions,
-			Getters:                   getters,
-		})
+		// Lock the highest installed version
+		installs, err = installRequirement.ListInstallations(opts)
 		if err != nil {
-			if pluginRequirement.Implicit {
-				msg := fmt.Sprintf(`
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		if len(installs) == 0 {
+			continue
+		}
+		if err := lock.Lock(pluginRequirement, installs[len(installs)-1], opts.Checksummers[0]); err != nil {
+			c.Ui.Error(err.Error())
+			ret = 1
+		}
+	}
+
+	if len(lock.Plugins) > 0 {
+		if err := lock.Write(lockPath); err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to write the plugins lock file %q: %s", lockPath, err))
+			ret = 1
+		}
+	}
+	return ret
+}
+
+// installLatest installs the latest version of the plugin that matches
+// pluginRequirement.
+func (c *InitCommand) installLatest(ui packersdk.Ui, pluginRequirement *plugingetter.Requirement, opts plugingetter.ListInstallationsOptions, getters []plugingetter.Getter) int {
+	newInstall, err := pluginRequirement.InstallLatest(plugingetter.InstallOptions{
+		InFolders:                 opts.FromFolders,
+		BinaryInstallationOptions: opts.BinaryInstallationOptions,
+		Getters:                   getters,
+	})
+	if err != nil {
+		if pluginRequirement.Implicit {
+			msg := fmt.Sprintf(`
 Warning! At least one component used in your config file(s) has moved out of 
 Packer into the %q plugin.
 For that reason, Packer init tried to install the latest version of the %s 
 plugin. Unfortunately, this failed :
 %s`,
-					pluginRequirement.Identifier,
-					pluginRequirement.Identifier.Type,
-					err)
-				c.Ui.Say(msg)
-			} else {
-				c.Ui.Error(err.Error())
-				ret = 1
-			}
+				pluginRequirement.Identifier,
+				pluginRequirement.Identifier.Type,
+				err)
+			c.Ui.Say(msg)
+			return 0
 		}
-		if newInstall != nil {
-			if pluginRequirement.Implicit {
-				msg := fmt.Sprintf("Installed implicitly required plugin %s %s in %q", pluginRequirement.Identifier, newInstall.Version, newInstall.BinaryPath)
-				ui.Say(msg)
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	if newInstall == nil {
+		return 0
+	}
+	if pluginRequirement.Implicit {
+		msg := fmt.Sprintf("Installed implicitly required plugin %s %s in %q", pluginRequirement.Identifier, newInstall.Version, newInstall.BinaryPath)
+		ui.Say(msg)
 
-				warn := fmt.Sprintf(`
+		warn := fmt.Sprintf(`
 Warning, at least one component used in your config file(s) has moved out of 
 Packer into the %[2]q plugin and is now being implicitly required. 
 For more details on implicitly required plugins see https://packer.io/docs/commands/init#implicit-required-plugin
@@ -159,19 +181,25 @@ packer {
   }
 }
 `,
-					pluginRequirement.Identifier.Type,
-					pluginRequirement.Identifier,
-					newInstall.Version,
-				)
-				ui.Error(warn)
-				continue
-			}
-			msg := fmt.Sprintf("Installed plugin %s %s in %q", pluginRequirement.Identifier, newInstall.Version, newInstall.BinaryPath)
-			ui.Say(msg)
+			pluginRequirement.Identifier.Type,
+			pluginRequirement.Identifier,
+			newInstall.Version,
+		)
+		ui.Error(warn)
+		return 0
+	}
+	msg := fmt.Sprintf("Installed plugin %s %s in %q", pluginRequirement.Identifier, newInstall.Version, newInstall.BinaryPath)
+	ui.Say(msg)
+	return 0
+}
 
-		}
+// lockFilePath returns the path of the plugins lock file of the configuration
+// in path, a file or a directory.
+func lockFilePath(path string) string {
+	if fi, err := os.Stat(path); err == nil && fi.IsDir() {
+		return filepath.Join(path, plugingetter.LockFileName)
 	}
-	return ret
+	return filepath.Join(filepath.Dir(path), plugingetter.LockFileName)
 }
 
 func (*InitCommand) Help() string {
@@ -187,12 +215,18 @@ Usage: packer init [options] [config.pkr.hcl|folder/]
   This command is always safe to run multiple times. Though subsequent runs may
   give errors, this command will never delete anything.
 
+  The selected version of each plugin, and the checksum of its binary, are
+  recorded in the .packer.lock.hcl file next to the config. Later runs
+  install that version, and build and validate refuse to use another one.
+  Commit this file with the config.
+
 Options:
   -upgrade                     On top of installing missing plugins, update
                                installed plugins to the latest available
-                               version, if there is a new higher one. Note that
-                               this still takes into consideration the version
-                               constraint of the config.
+                               version, if there is a new higher one, and lock
+                               that version. Note that this still takes into
+                               consideration the version constraint of the
+                               config.
 `
 
 	return strings.TrimSpace(helpText)
@@ -211,3 +245,48 @@ func (*InitCommand) AutocompleteFlags() complete.Flags {
 		"-upgrade": complete.PredictNothing,
 	}
 }
+
+// pluginInstallationOptions returns the options to list and install plugins
+// for the current system in folders.
+func pluginInstallationOptions(folders []string) plugingetter.ListInstallationsOptions {
+	opts := plugingetter.ListInstallationsOptions{
+		FromFolders: folders,
+		BinaryInstallationOptions: plugingetter.BinaryInstallationOptions{
+			OS:              runtime.GOOS,
+			ARCH:            runtime.GOARCH,
+			APIVersionMajor: pluginsdk.APIVersionMajor,
+			APIVersionMinor: pluginsdk.APIVersionMinor,
+			Checksummers: []plugingetter.Checksummer{
+				{Type: "sha256", Hash: sha256.New()},
+			},
+		},
+	}
+
+	if runtime.GOOS == "windows" && opts.Ext == "" {
+		opts.BinaryInstallationOptions.Ext = ".exe"
+	}
+	return opts
+}
+
+// pluginGetters returns the getters used to get the releases, checksums and
+// binaries of remote plugins. When mirror is set, plugins are only got from
+// that mirror.
+func pluginGetters(mirror string) []plugingetter.Getter {
+	if mirror != "" {
+		return []plugingetter.Getter{
+			&pluginmirror.Getter{Mirror: mirror},
+		}
+	}
+	return []plugingetter.Getter{
+		&github.Getter{
+			// In the past some terraform plugins downloads were blocked from a
+			// specific aws region by s3. Changing the user agent unblocked the
+			// downloads so having one user agent per version will help mitigate
+			// that a little more. Especially in the case someone forks this
+			// code to make it more aggressive or something.
+			// TODO: allow to set this from the config file or an environment
+			// variable.
+			UserAgent: "packer-getter-github-" + version.String(),
+		},
+	}
+}
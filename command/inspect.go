@@ -49,7 +49,8 @@ func (c *InspectCommand) RunContext(ctx context.Context, cla *InspectArgs) int {
 	_ = packerStarter.Initialize(packer.InitializeOptions{})
 
 	return packerStarter.InspectConfig(packer.InspectConfigOptions{
-		Ui: c.Ui,
+		Ui:   c.Ui,
+		JSON: cla.JSON,
 	})
 }
 
@@ -63,6 +64,7 @@ Usage: packer inspect TEMPLATE
 
 Options:
 
+  -json              Write the evaluated configuration as JSON
   -machine-readable  Machine-readable output
 `
 
@@ -79,6 +81,7 @@ func (c *InspectCommand) AutocompleteArgs() complete.Predictor {
 
 func (c *InspectCommand) AutocompleteFlags() complete.Flags {
 	return complete.Flags{
+		"-json":             complete.PredictNothing,
 		"-machine-readable": complete.PredictNothing,
 	}
 }
@@ -1,11 +1,13 @@
 package command
 
 import (
+	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/packer/packer"
 )
 
 func Test_commands(t *testing.T) {
@@ -181,3 +183,46 @@ and therefore only show in their raw form here.
 		})
 	}
 }
+
+func Test_inspectJSON(t *testing.T) {
+	p := helperCommand(t, "inspect", "-json", filepath.Join(testFixture("hcl-inspect-with-sensitive-vars")))
+	bs, err := p.Output()
+	if err != nil {
+		t.Fatalf("%v: %s", err, bs)
+	}
+
+	var out packer.InspectOutput
+	if err := json.Unmarshal(bs, &out); err != nil {
+		t.Fatalf("err: %s: %s", err, bs)
+	}
+	if out.FormatVersion != packer.InspectFormatVersion || out.ConfigType != "hcl2" {
+		t.Fatalf("unexpected output: %s", bs)
+	}
+
+	values := map[string]string{}
+	for _, v := range append(out.Variables, out.Locals...) {
+		if v.Sensitive {
+			values[v.Name] = "sensitive: " + string(v.Value)
+		} else {
+			values[v.Name] = string(v.Value)
+		}
+	}
+	expected := map[string]string{
+		"not_sensitive":         `"I am soooo not sensitive"`,
+		"not_sensitive_unknown": `null`,
+		"sensitive":             `sensitive: null`,
+		"sensitive_array":       `sensitive: null`,
+		"sensitive_tags":        `sensitive: null`,
+		"sensitive_unknown":     `sensitive: null`,
+	}
+	for name, value := range expected {
+		if values[name] != value {
+			t.Errorf("unexpected value for %s: %s", name, values[name])
+		}
+	}
+	if len(out.Locals) != 2 || out.Locals[0].Name != "not_sensitive" ||
+		string(out.Locals[0].Value) != `"I AM SOOOO NOT SENSITIVE"` ||
+		!out.Locals[1].Sensitive || string(out.Locals[1].Value) != "null" {
+		t.Errorf("unexpected locals: %#v", out.Locals)
+	}
+}
@@ -0,0 +1,201 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+	"github.com/hashicorp/packer/hcl2template"
+	"github.com/hashicorp/packer/packer"
+	"github.com/posener/complete"
+)
+
+type PlanCommand struct {
+	Meta
+}
+
+func (c *PlanCommand) Run(args []string) int {
+	ctx, cleanup := handleTermInterrupt(c.Ui)
+	defer cleanup()
+
+	cfg, ret := c.ParseArgs(args)
+	if ret != 0 {
+		return ret
+	}
+
+	return c.RunContext(ctx, cfg)
+}
+
+func (c *PlanCommand) ParseArgs(args []string) (*PlanArgs, int) {
+	var cfg PlanArgs
+	flags := c.Meta.FlagSet("plan", FlagSetBuildFilter|FlagSetVars)
+	flags.Usage = func() { c.Ui.Say(c.Help()) }
+	cfg.AddFlagSets(flags)
+	if err := flags.Parse(args); err != nil {
+		return &cfg, 1
+	}
+
+	args = flags.Args()
+	if len(args) != 1 {
+		flags.Usage()
+		return &cfg, 1
+	}
+	cfg.Path = args[0]
+	return &cfg, 0
+}
+
+func (c *PlanCommand) RunContext(ctx context.Context, cla *PlanArgs) int {
+	packerStarter, ret := c.GetConfig(&cla.MetaArgs)
+	if ret != 0 {
+		return ret
+	}
+
+	// Data sources are executed, so that the plan shows the values the
+	// builds will use.
+	diags := packerStarter.Initialize(packer.InitializeOptions{})
+	ret = writeDiags(c.Ui, nil, diags)
+	if ret != 0 {
+		return ret
+	}
+
+	// Builds are configured and prepared, like before they are run, but
+	// nothing is started.
+	builds, diags := packerStarter.GetBuilds(packer.GetBuildsOptions{
+		Only:   cla.Only,
+		Except: cla.Except,
+	})
+	ret = writeDiags(c.Ui, nil, diags)
+	if ret != 0 {
+		return ret
+	}
+	builds = sortBuildsByDependencies(builds)
+
+	// The evaluated configuration of the sources of HCL2 builds
+	sourceConfigs := map[string]json.RawMessage{}
+	if cfg, ok := packerStarter.(*hcl2template.PackerConfig); ok {
+		for _, build := range cfg.InspectOutput().Builds {
+			for _, source := range build.Sources {
+				sourceConfigs[source.Name] = source.Config
+			}
+		}
+	}
+
+	switch len(builds) {
+	case 0:
+		c.Ui.Say("Packer Plan: no builds would run.")
+		return 0
+	case 1:
+		c.Ui.Say("Packer Plan: 1 build would run.\n")
+	default:
+		c.Ui.Say(fmt.Sprintf("Packer Plan: %d builds would run, in this order.\n", len(builds)))
+	}
+	for _, b := range builds {
+		c.Ui.Say(planBuild(b, sourceConfigs[b.Name()]))
+	}
+	return 0
+}
+
+// planBuild describes what a build would do when run.
+func planBuild(b packersdk.Build, config json.RawMessage) string {
+	out := &strings.Builder{}
+	fmt.Fprintf(out, "  + %s\n", b.Name())
+
+	cb, ok := b.(*packer.CoreBuild)
+	if !ok {
+		return out.String()
+	}
+	if len(cb.DependsOn) > 0 {
+		fmt.Fprintf(out, "      depends on: %s\n", strings.Join(cb.DependsOn, ", "))
+	}
+
+	// Only the fields that are set are shown
+	var fields map[string]json.RawMessage
+	_ = json.Unmarshal(config, &fields)
+	keys := make([]string, 0, len(fields))
+	max := 0
+	for k, v := range fields {
+		if string(v) == "null" {
+			continue
+		}
+		keys = append(keys, k)
+		if len(k) > max {
+			max = len(k)
+		}
+	}
+	sort.Strings(keys)
+	if len(keys) > 0 {
+		fmt.Fprintf(out, "      source:\n")
+		for _, k := range keys {
+			fmt.Fprintf(out, "        %s%s = %s\n", k, strings.Repeat(" ", max-len(k)), fields[k])
+		}
+	}
+
+	if len(cb.Provisioners) > 0 {
+		fmt.Fprintf(out, "      provisioners:\n")
+		for _, p := range cb.Provisioners {
+			fmt.Fprintf(out, "        %s\n", pluginName(p.PType, p.PName))
+		}
+	}
+	if len(cb.PostProcessors) > 0 {
+		fmt.Fprintf(out, "      post-processors:\n")
+		for _, chain := range cb.PostProcessors {
+			names := make([]string, 0, len(chain))
+			for _, pp := range chain {
+				names = append(names, pluginName(pp.PType, pp.PName))
+			}
+			fmt.Fprintf(out, "        %s\n", strings.Join(names, " -> "))
+		}
+	}
+	return out.String()
+}
+
+func pluginName(pType, pName string) string {
+	if pName == "" || pName == pType {
+		return pType
+	}
+	return pType + "." + pName
+}
+
+func (*PlanCommand) Help() string {
+	helpText := `
+Usage: packer plan [options] TEMPLATE
+
+  Shows the builds that packer build would run with the same options,
+  without running them. The template is evaluated and its data sources
+  are executed, and the configuration of each build is checked, but no
+  instance, image or file is created.
+
+  For each build, the fields set in its source, its provisioners and its
+  chains of post-processors are shown. Builds are listed in the order
+  they would be started.
+
+Options:
+
+  -except=foo,bar,baz    Plan all builds other than these.
+  -only=foo,bar,baz      Plan only these builds.
+  -var 'key=value'       Variable for templates, can be used multiple times.
+  -var-file=path         JSON or HCL2 file containing user variables.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (*PlanCommand) Synopsis() string {
+	return "show the builds that would run, without running them"
+}
+
+func (*PlanCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (*PlanCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-except":   complete.PredictNothing,
+		"-only":     complete.PredictNothing,
+		"-var":      complete.PredictNothing,
+		"-var-file": complete.PredictNothing,
+	}
+}
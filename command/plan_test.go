@@ -0,0 +1,45 @@
+package command
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPlan(t *testing.T) {
+	c := &PlanCommand{
+		Meta: testMetaFile(t),
+	}
+	args := []string{filepath.Join(testFixture("hcl"), "depends-on", "build.pkr.hcl")}
+
+	defer cleanup("base.txt", "app.txt")
+
+	if code := c.Run(args); code != 0 {
+		fatalCommand(t, c.Meta)
+	}
+
+	out, _ := outputCommand(t, c.Meta)
+	expected := `Packer Plan: 2 builds would run, in this order.
+
+  + base.file.base
+      source:
+        content = "base"
+        target  = "base.txt"
+
+  + app.file.app
+      depends on: base
+      source:
+        source = "base.txt"
+        target = "app.txt"
+`
+	if !strings.Contains(out, expected) {
+		t.Fatalf("unexpected plan:\n%s", out)
+	}
+
+	// Nothing is built
+	for _, f := range []string{"base.txt", "app.txt"} {
+		if fileExists(f) {
+			t.Errorf("%s should not be created", f)
+		}
+	}
+}
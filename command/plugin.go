@@ -31,11 +31,19 @@ import (
 	uclouduhostbuilder "github.com/hashicorp/packer/builder/ucloud/uhost"
 	vagrantbuilder "github.com/hashicorp/packer/builder/vagrant"
 	yandexbuilder "github.com/hashicorp/packer/builder/yandex"
+	gitdatasource "github.com/hashicorp/packer/datasource/git"
+	httpdatasource "github.com/hashicorp/packer/datasource/http"
+	localexecdatasource "github.com/hashicorp/packer/datasource/local-exec"
+	vaultdatasource "github.com/hashicorp/packer/datasource/vault"
 	artificepostprocessor "github.com/hashicorp/packer/post-processor/artifice"
+	azureimportpostprocessor "github.com/hashicorp/packer/post-processor/azure-import"
 	checksumpostprocessor "github.com/hashicorp/packer/post-processor/checksum"
 	compresspostprocessor "github.com/hashicorp/packer/post-processor/compress"
 	digitaloceanimportpostprocessor "github.com/hashicorp/packer/post-processor/digitalocean-import"
+	hetznerimportpostprocessor "github.com/hashicorp/packer/post-processor/hetzner-import"
 	manifestpostprocessor "github.com/hashicorp/packer/post-processor/manifest"
+	ociarchivepostprocessor "github.com/hashicorp/packer/post-processor/oci-archive"
+	oracleociimportpostprocessor "github.com/hashicorp/packer/post-processor/oracle-oci-import"
 	shelllocalpostprocessor "github.com/hashicorp/packer/post-processor/shell-local"
 	ucloudimportpostprocessor "github.com/hashicorp/packer/post-processor/ucloud-import"
 	vagrantpostprocessor "github.com/hashicorp/packer/post-processor/vagrant"
@@ -98,10 +106,14 @@ var Provisioners = map[string]packersdk.Provisioner{
 
 var PostProcessors = map[string]packersdk.PostProcessor{
 	"artifice":            new(artificepostprocessor.PostProcessor),
+	"azure-import":        new(azureimportpostprocessor.PostProcessor),
 	"checksum":            new(checksumpostprocessor.PostProcessor),
 	"compress":            new(compresspostprocessor.PostProcessor),
 	"digitalocean-import": new(digitaloceanimportpostprocessor.PostProcessor),
+	"hetzner-import":      new(hetznerimportpostprocessor.PostProcessor),
 	"manifest":            new(manifestpostprocessor.PostProcessor),
+	"oci-archive":         new(ociarchivepostprocessor.PostProcessor),
+	"oracle-oci-import":   new(oracleociimportpostprocessor.PostProcessor),
 	"shell-local":         new(shelllocalpostprocessor.PostProcessor),
 	"ucloud-import":       new(ucloudimportpostprocessor.PostProcessor),
 	"vagrant":             new(vagrantpostprocessor.PostProcessor),
@@ -110,7 +122,12 @@ var PostProcessors = map[string]packersdk.PostProcessor{
 	"yandex-import":       new(yandeximportpostprocessor.PostProcessor),
 }
 
-var Datasources = map[string]packersdk.Datasource{}
+var Datasources = map[string]packersdk.Datasource{
+	"git":        new(gitdatasource.Datasource),
+	"http":       new(httpdatasource.Datasource),
+	"local-exec": new(localexecdatasource.Datasource),
+	"vault":      new(vaultdatasource.Datasource),
+}
 
 var pluginRegexp = regexp.MustCompile("packer-(builder|post-processor|provisioner|datasource)-(.+)")
 
@@ -0,0 +1,216 @@
+package command
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	gversion "github.com/hashicorp/go-version"
+	pluginsdk "github.com/hashicorp/packer-plugin-sdk/plugin"
+	"github.com/hashicorp/packer/hcl2template/addrs"
+	plugingetter "github.com/hashicorp/packer/packer/plugin-getter"
+	"github.com/posener/complete"
+)
+
+type PluginsInstallCommand struct {
+	Meta
+}
+
+func (c *PluginsInstallCommand) Run(args []string) int {
+	ctx, cleanup := handleTermInterrupt(c.Ui)
+	defer cleanup()
+
+	cfg, ret := c.ParseArgs(args)
+	if ret != 0 {
+		return ret
+	}
+
+	return c.RunContext(ctx, cfg)
+}
+
+func (c *PluginsInstallCommand) ParseArgs(args []string) (*PluginsInstallArgs, int) {
+	var cfg PluginsInstallArgs
+	flags := c.Meta.FlagSet("plugins install", 0)
+	flags.Usage = func() { c.Ui.Say(c.Help()) }
+	cfg.AddFlagSets(flags)
+	if err := flags.Parse(args); err != nil {
+		return &cfg, 1
+	}
+
+	args = flags.Args()
+	if len(args) < 1 || len(args) > 2 {
+		flags.Usage()
+		return &cfg, 1
+	}
+	cfg.Plugin = args[0]
+	if len(args) == 2 {
+		cfg.Version = args[1]
+	}
+	return &cfg, 0
+}
+
+func (c *PluginsInstallCommand) RunContext(ctx context.Context, cla *PluginsInstallArgs) int {
+	plugin, diags := addrs.ParsePluginSourceString(cla.Plugin)
+	if ret := writeDiags(c.Ui, nil, diags); ret != 0 {
+		return ret
+	}
+
+	req := &plugingetter.Requirement{
+		Accessor:   plugin.Type,
+		Identifier: plugin,
+	}
+	if cla.Version != "" {
+		constraints, err := gversion.NewConstraint(cla.Version)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Invalid version constraint %q: %s", cla.Version, err))
+			return 1
+		}
+		req.VersionConstraints = constraints
+	}
+
+	opts := pluginInstallationOptions(c.Meta.CoreConfig.Components.PluginConfig.KnownPluginFolders)
+
+	if cla.Path != "" {
+		install, err := installPluginFromFile(req, cla.Path, opts)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to install %s from %q: %s", plugin, cla.Path, err))
+			return 1
+		}
+		c.Ui.Say(fmt.Sprintf("Installed plugin %s %s in %q", plugin, install.Version, install.BinaryPath))
+		return 0
+	}
+
+	// The checksum of the downloaded binary is checked against the SHA256SUMS
+	// file of its release.
+	install, err := req.InstallLatest(plugingetter.InstallOptions{
+		InFolders:                 opts.FromFolders,
+		BinaryInstallationOptions: opts.BinaryInstallationOptions,
+		Getters:                   pluginGetters(c.Meta.CoreConfig.Components.PluginConfig.PluginMirror),
+	})
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	if install == nil {
+		c.Ui.Say(fmt.Sprintf("The latest matching version of %s is already installed", plugin))
+		return 0
+	}
+	c.Ui.Say(fmt.Sprintf("Installed plugin %s %s in %q", plugin, install.Version, install.BinaryPath))
+	return 0
+}
+
+// installPluginFromFile installs the plugin binary in path, for example a
+// plugin built locally, the same way as a plugin downloaded from its
+// releases: it is renamed after its version and the protocol it uses, as
+// told by its describe command, and the file of its checksum is written next
+// to it.
+func installPluginFromFile(req *plugingetter.Requirement, path string, opts plugingetter.ListInstallationsOptions) (*plugingetter.Installation, error) {
+	out, err := exec.Command(path, "describe").Output()
+	if err != nil {
+		return nil, fmt.Errorf("could not describe the plugin: %w", err)
+	}
+	var desc pluginsdk.SetDescription
+	if err := json.Unmarshal(out, &desc); err != nil {
+		return nil, fmt.Errorf("could not read the description of the plugin: %w", err)
+	}
+
+	v, err := gversion.NewVersion(desc.Version)
+	if err != nil {
+		return nil, fmt.Errorf("invalid plugin version %q: %w", desc.Version, err)
+	}
+	if !req.VersionConstraints.Check(v) {
+		return nil, fmt.Errorf("version %s does not match the constraint %q", v, req.VersionConstraints)
+	}
+	apiVersion := "x" + strings.TrimPrefix(desc.APIVersion, "x")
+	if err := opts.CheckProtocolVersion(apiVersion); err != nil {
+		return nil, err
+	}
+
+	outputFolder := filepath.Join(
+		// Pick last folder as it's the one with the highest priority
+		opts.FromFolders[len(opts.FromFolders)-1],
+		filepath.Join(req.Identifier.Parts()...),
+	)
+	if err := os.MkdirAll(outputFolder, 0755); err != nil {
+		return nil, fmt.Errorf("could not create plugin folder %q: %w", outputFolder, err)
+	}
+	outputFileName := filepath.Join(outputFolder, fmt.Sprintf("%sv%s_%s_%s_%s%s",
+		req.FilenamePrefix(), v, apiVersion, opts.OS, opts.ARCH, opts.Ext))
+
+	in, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+	outputFile, err := os.OpenFile(outputFileName, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", outputFileName, err)
+	}
+	defer outputFile.Close()
+	if _, err := io.Copy(outputFile, in); err != nil {
+		return nil, fmt.Errorf("failed to copy the plugin: %w", err)
+	}
+	if _, err := outputFile.Seek(0, 0); err != nil {
+		return nil, err
+	}
+
+	// Installed plugins are only used when the file of their checksum matches
+	checksummer := opts.Checksummers[0]
+	cs, err := checksummer.Sum(outputFile)
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(outputFileName+checksummer.FileExt(), []byte(hex.EncodeToString(cs)), 0555); err != nil {
+		return nil, fmt.Errorf("failed to write the checksum of the plugin: %w", err)
+	}
+
+	return &plugingetter.Installation{
+		BinaryPath: strings.ReplaceAll(outputFileName, "\\", "/"),
+		Version:    "v" + v.String(),
+	}, nil
+}
+
+func (*PluginsInstallCommand) Help() string {
+	helpText := `
+Usage: packer plugins install [options] PLUGIN [VERSION_CONSTRAINT]
+
+  Install the latest version of PLUGIN that matches VERSION_CONSTRAINT,
+  using the same version constraint syntax as required_plugins. Without a
+  constraint, the latest version is installed.
+
+  PLUGIN is the source of the plugin, like github.com/hashicorp/amazon.
+  The plugin is downloaded from its releases, and its checksum is checked
+  against the SHA256SUMS file of the release.
+
+    $ packer plugins install github.com/hashicorp/amazon "~> 1.0"
+
+Options:
+
+  -path=FILE    Install the plugin binary FILE, for example a plugin built
+                locally, instead of downloading it. Its version is the one
+                it describes.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (*PluginsInstallCommand) Synopsis() string {
+	return "Install a plugin, optionally matching a version constraint"
+}
+
+func (*PluginsInstallCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (*PluginsInstallCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-path": complete.PredictFiles("*"),
+	}
+}
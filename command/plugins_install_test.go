@@ -0,0 +1,64 @@
+package command
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	gversion "github.com/hashicorp/go-version"
+	pluginsdk "github.com/hashicorp/packer-plugin-sdk/plugin"
+	"github.com/hashicorp/packer/hcl2template/addrs"
+	plugingetter "github.com/hashicorp/packer/packer/plugin-getter"
+)
+
+func Test_installPluginFromFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake plugin is a shell script")
+	}
+
+	dir, err := ioutil.TempDir("", "packer-plugins-install")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// A fake plugin that only describes itself
+	pluginPath := filepath.Join(dir, "packer-plugin-happycloud")
+	script := fmt.Sprintf("#!/bin/sh\necho '{\"version\": \"1.2.3\", \"api_version\": \"x%s.%s\"}'\n",
+		pluginsdk.APIVersionMajor, pluginsdk.APIVersionMinor)
+	if err := ioutil.WriteFile(pluginPath, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	pluginsDir := filepath.Join(dir, "plugins")
+	opts := pluginInstallationOptions([]string{pluginsDir})
+	req := &plugingetter.Requirement{
+		Identifier: &addrs.Plugin{Hostname: "github.com", Namespace: "azr", Type: "happycloud"},
+	}
+
+	req.VersionConstraints, _ = gversion.NewConstraint(">= 2.0.0")
+	if _, err := installPluginFromFile(req, pluginPath, opts); err == nil {
+		t.Fatal("expected the version constraint to fail")
+	}
+
+	req.VersionConstraints, _ = gversion.NewConstraint("~> 1.2")
+	install, err := installPluginFromFile(req, pluginPath, opts)
+	if err != nil {
+		t.Fatalf("installPluginFromFile: %s", err)
+	}
+	if install.Version != "v1.2.3" {
+		t.Errorf("unexpected version %q", install.Version)
+	}
+
+	// The installed plugin is found like a downloaded one
+	installs, err := req.ListInstallations(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(installs) != 1 || installs[0].BinaryPath != install.BinaryPath {
+		t.Fatalf("expected %q to be listed, got %s", install.BinaryPath, installs)
+	}
+}
@@ -0,0 +1,124 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+
+	plugingetter "github.com/hashicorp/packer/packer/plugin-getter"
+	"github.com/posener/complete"
+)
+
+type PluginsMirrorCommand struct {
+	Meta
+}
+
+func (c *PluginsMirrorCommand) Run(args []string) int {
+	ctx, cleanup := handleTermInterrupt(c.Ui)
+	defer cleanup()
+
+	cfg, ret := c.ParseArgs(args)
+	if ret != 0 {
+		return ret
+	}
+
+	return c.RunContext(ctx, cfg)
+}
+
+func (c *PluginsMirrorCommand) ParseArgs(args []string) (*PluginsMirrorArgs, int) {
+	var cfg PluginsMirrorArgs
+	flags := c.Meta.FlagSet("plugins mirror", 0)
+	flags.Usage = func() { c.Ui.Say(c.Help()) }
+	cfg.AddFlagSets(flags)
+	if err := flags.Parse(args); err != nil {
+		return &cfg, 1
+	}
+
+	args = flags.Args()
+	if len(args) != 2 {
+		flags.Usage()
+		return &cfg, 1
+	}
+	cfg.Folder, cfg.Path = args[0], args[1]
+	if len(cfg.Platforms) == 0 {
+		cfg.Platforms = []string{runtime.GOOS + "_" + runtime.GOARCH}
+	}
+	return &cfg, 0
+}
+
+func (c *PluginsMirrorCommand) RunContext(ctx context.Context, cla *PluginsMirrorArgs) int {
+	packerStarter, ret := c.GetConfig(&cla.MetaArgs)
+	if ret != 0 {
+		return ret
+	}
+
+	reqs, diags := packerStarter.PluginRequirements()
+	if ret := writeDiags(c.Ui, nil, diags); ret != 0 {
+		return ret
+	}
+
+	// The OS and ARCH of these options are replaced by the platforms
+	opts := pluginInstallationOptions(nil)
+	for _, pluginRequirement := range reqs {
+		// The releases of the plugins are always mirrored, and not the ones of
+		// a configured mirror.
+		v, err := pluginRequirement.Mirror(plugingetter.MirrorOptions{
+			Getters:                   pluginGetters(""),
+			Folder:                    cla.Folder,
+			Platforms:                 cla.Platforms,
+			BinaryInstallationOptions: opts.BinaryInstallationOptions,
+		})
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to mirror %s: %s", pluginRequirement.Identifier, err))
+			ret = 1
+			continue
+		}
+		c.Ui.Say(fmt.Sprintf("Mirrored plugin %s v%s for %s in %q",
+			pluginRequirement.Identifier, v, strings.Join(cla.Platforms, ", "), cla.Folder))
+	}
+	return ret
+}
+
+func (*PluginsMirrorCommand) Help() string {
+	helpText := `
+Usage: packer plugins mirror [options] MIRROR_DIR [config.pkr.hcl|folder/]
+
+  Download the latest version of the plugins required in a Packer config
+  that matches their version constraints into MIRROR_DIR, to populate a
+  mirror for machines without internet access.
+
+  The content of MIRROR_DIR can then be copied to these machines, or served
+  over http, and set as the plugin_mirror of their Packer config file, or
+  with the PACKER_PLUGIN_MIRROR environment variable. packer init will then
+  install plugins from that mirror.
+
+    $ packer plugins mirror ./mirror ./ubuntu
+
+Options:
+
+  -platform=os_arch             The platform to mirror the plugins for, like
+                                linux_amd64. This option can be used multiple
+                                times. Defaults to the current platform.
+  -var 'key=value'              Variable for templates, can be used multiple times.
+  -var-file=path                JSON or HCL2 file containing user variables.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (*PluginsMirrorCommand) Synopsis() string {
+	return "Download the plugins required by a config into a mirror"
+}
+
+func (*PluginsMirrorCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (*PluginsMirrorCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-platform": complete.PredictNothing,
+		"-var":      complete.PredictNothing,
+		"-var-file": complete.PredictNothing,
+	}
+}
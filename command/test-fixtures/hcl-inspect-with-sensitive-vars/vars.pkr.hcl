@@ -27,3 +27,8 @@ variable "sensitive_tags" {
 variable "sensitive_unknown" {
   sensitive = true
 }
+
+locals {
+  not_sensitive = upper(var.not_sensitive)
+  sensitive     = upper(var.sensitive)
+}
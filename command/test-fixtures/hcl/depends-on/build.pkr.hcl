@@ -0,0 +1,21 @@
+source "file" "base" {
+  content = "base"
+  target  = "base.txt"
+}
+
+source "file" "app" {
+  source = "base.txt"
+  target = "app.txt"
+}
+
+// app is declared first, but copies the file written by the base build.
+build {
+  name       = "app"
+  depends_on = ["base"]
+  sources    = ["source.file.app"]
+}
+
+build {
+  name    = "base"
+  sources = ["source.file.base"]
+}
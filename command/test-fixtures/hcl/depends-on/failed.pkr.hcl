@@ -0,0 +1,20 @@
+source "file" "base" {
+  source = "does-not-exist.txt"
+  target = "base.txt"
+}
+
+source "file" "app" {
+  content = "app"
+  target  = "app.txt"
+}
+
+build {
+  name    = "base"
+  sources = ["source.file.base"]
+}
+
+build {
+  name       = "app"
+  depends_on = ["base"]
+  sources    = ["source.file.app"]
+}
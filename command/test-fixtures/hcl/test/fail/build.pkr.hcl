@@ -0,0 +1,21 @@
+variable "flavour" {
+  type    = string
+  default = "vanilla"
+}
+
+data "mock" "topping" {
+  foo = "sprinkles"
+}
+
+locals {
+  target = "${var.flavour}.txt"
+}
+
+source "file" "cake" {
+  content = data.mock.topping.foo
+  target  = local.target
+}
+
+build {
+  sources = ["source.file.cake"]
+}
@@ -0,0 +1,10 @@
+run "strawberry" {
+  variables = {
+    flavour = "strawberry"
+  }
+
+  assert {
+    condition     = local.target == "vanilla.txt"
+    error_message = "The cake is vanilla."
+  }
+}
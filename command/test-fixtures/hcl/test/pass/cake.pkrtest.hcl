@@ -0,0 +1,28 @@
+run "default" {
+  assert {
+    condition     = local.target == "vanilla.txt"
+    error_message = "The cake is vanilla by default."
+  }
+}
+
+run "chocolate" {
+  variables = {
+    flavour = "chocolate"
+  }
+
+  mock_data "mock" "topping" {
+    values = {
+      foo = "cherries"
+    }
+  }
+
+  assert {
+    condition     = source.file.cake.target == "chocolate.txt"
+    error_message = "The target must be named after the flavour."
+  }
+
+  assert {
+    condition     = source.file.cake.content == "cherries"
+    error_message = "The content must be the topping."
+  }
+}
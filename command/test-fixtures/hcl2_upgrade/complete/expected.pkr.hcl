@@ -173,12 +173,12 @@ build {
   sources = ["source.amazon-ebs.autogenerated_1", "source.amazon-ebs.named_builder"]
 
   provisioner "breakpoint" {
-    only         = ["amazon-ebs"]
+    only         = ["amazon-ebs.autogenerated_1"]
     pause_before = "5s"
   }
 
   provisioner "shell" {
-    except      = ["amazon-ebs"]
+    except      = ["amazon-ebs.autogenerated_1"]
     inline      = ["echo ${var.secret_account}", "echo ${build.ID}", "echo ${build.SSHPublicKey} | head -c 14", "echo ${path.root} is not ${path.cwd}", "echo ${packer.version}", "echo ${uuidv4()}"]
     max_retries = "5"
   }
@@ -201,44 +201,20 @@ build {
     inline = ["echo mybuild-{{ clean_resource_name `${timestamp()}` }}"]
   }
 
-
-  # 1 error occurred upgrading the following block:
-  # unhandled "lower" call:
-  # there is no way to automatically upgrade the "lower" call.
-  # Please manually upgrade to `lower(var.example)`
-  # Visit https://www.packer.io/docs/templates/hcl_templates/functions/string/lower for more infos.
   provisioner "shell" {
-    inline = ["echo {{ lower `SOMETHING` }}"]
+    inline = ["echo ${lower("SOMETHING")}"]
   }
 
-
-  # 1 error occurred upgrading the following block:
-  # unhandled "upper" call:
-  # there is no way to automatically upgrade the "upper" call.
-  # Please manually upgrade to `upper(var.example)`
-  # Visit https://www.packer.io/docs/templates/hcl_templates/functions/string/upper for more infos.
   provisioner "shell" {
-    inline = ["echo {{ upper `something` }}"]
+    inline = ["echo ${upper("something")}"]
   }
 
-
-  # 1 error occurred upgrading the following block:
-  # unhandled "split" call:
-  # there is no way to automatically upgrade the "split" call.
-  # Please manually upgrade to `split(separator, string)`
-  # Visit https://www.packer.io/docs/templates/hcl_templates/functions/string/split for more infos.
   provisioner "shell" {
-    inline = ["echo {{ split `some-string` `-` 0 }}"]
+    inline = ["echo ${split("-", "some-string")[0]}"]
   }
 
-
-  # 1 error occurred upgrading the following block:
-  # unhandled "replace_all" call:
-  # there is no way to automatically upgrade the "replace_all" call.
-  # Please manually upgrade to `replace(string, substring, replacement)` or `regex_replace(string, substring, replacement)`
-  # Visit https://www.packer.io/docs/templates/hcl_templates/functions/string/replace or https://www.packer.io/docs/templates/hcl_templates/functions/string/regex_replace for more infos.
   provisioner "shell" {
-    inline = ["echo {{ replace_all `-` `/` `${build.name}` }}"]
+    inline = ["echo ${replace(build.name, "-", "/")}"]
   }
 
 
@@ -253,7 +229,7 @@ build {
 
   provisioner "shell-local" {
     inline       = ["sleep 100000"]
-    only         = ["amazon-ebs"]
+    only         = ["amazon-ebs.autogenerated_1"]
     pause_before = "5s"
     timeout      = "5s"
   }
@@ -272,10 +248,10 @@ build {
       keep_input_artifact = true
       files               = ["path/something.ova"]
       name                = "very_special_artifice_post-processor"
-      only                = ["amazon-ebs"]
+      only                = ["amazon-ebs.autogenerated_1"]
     }
     post-processor "amazon-import" {
-      except         = ["amazon-ebs"]
+      except         = ["amazon-ebs.autogenerated_1"]
       license_type   = "BYOL"
       s3_bucket_name = "hashicorp.adrien"
       tags = {
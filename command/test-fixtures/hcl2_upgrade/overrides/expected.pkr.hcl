@@ -0,0 +1,35 @@
+variable "name" {
+  type    = string
+  default = "Packer"
+}
+
+source "null" "autogenerated_1" {
+  communicator = "none"
+}
+
+source "null" "second" {
+  communicator = "none"
+}
+
+build {
+  sources = ["source.null.autogenerated_1", "source.null.second"]
+
+  provisioner "shell-local" {
+    inline = ["echo ${lower(var.name)}"]
+    only   = ["null.second"]
+    override = {
+      autogenerated_1 = {
+        inline = ["echo ${split("-", build.name)[0]}"]
+      }
+    }
+  }
+
+  post-processors {
+    post-processor "shell-local" {
+      except = ["null.autogenerated_1"]
+      inline = ["echo ${upper(var.name)}"]
+    }
+    post-processor "manifest" {
+    }
+  }
+}
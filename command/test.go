@@ -0,0 +1,156 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/packer/hcl2template"
+	"github.com/hashicorp/packer/version"
+	"github.com/posener/complete"
+)
+
+type TestCommand struct {
+	Meta
+}
+
+func (c *TestCommand) Run(args []string) int {
+	ctx, cleanup := handleTermInterrupt(c.Ui)
+	defer cleanup()
+
+	cfg, ret := c.ParseArgs(args)
+	if ret != 0 {
+		return ret
+	}
+
+	return c.RunContext(ctx, cfg)
+}
+
+func (c *TestCommand) ParseArgs(args []string) (*TestArgs, int) {
+	var cfg TestArgs
+	flags := c.Meta.FlagSet("test", FlagSetVars)
+	flags.Usage = func() { c.Ui.Say(c.Help()) }
+	cfg.AddFlagSets(flags)
+	if err := flags.Parse(args); err != nil {
+		return &cfg, 1
+	}
+
+	args = flags.Args()
+	if len(args) != 1 {
+		flags.Usage()
+		return &cfg, 1
+	}
+	cfg.Path = args[0]
+	return &cfg, 0
+}
+
+func (c *TestCommand) RunContext(ctx context.Context, cla *TestArgs) int {
+	cfgType, err := cla.GetConfigType()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("%q: %s", cla.Path, err))
+		return 1
+	}
+	if cfgType != ConfigTypeHCL2 {
+		c.Ui.Error("packer test only supports HCL2 configurations.")
+		return 1
+	}
+
+	testFiles, diags := hcl2template.GetTestFiles(cla.Path)
+	if ret := writeDiags(c.Ui, nil, diags); ret != 0 {
+		return ret
+	}
+	if len(testFiles) == 0 {
+		c.Ui.Error(fmt.Sprintf("No test files found in %q, test files end with "+
+			"\".pkrtest.hcl\" or \".pkrtest.json\".", cla.Path))
+		return 1
+	}
+
+	testParser := hclparse.NewParser()
+	passed, failed := 0, 0
+	for _, testFile := range testFiles {
+		runs, diags := hcl2template.ParseTestFile(testParser, testFile)
+		if ret := writeDiags(c.Ui, testParser.Files(), diags); ret != 0 {
+			return ret
+		}
+
+		c.Ui.Say(filepath.Base(testFile) + "...")
+		for _, run := range runs {
+			// Each run starts from a freshly parsed configuration, as it is
+			// initialized with the values of the run.
+			parser := &hcl2template.Parser{
+				CorePackerVersion:       version.SemVer,
+				CorePackerVersionString: version.FormattedVersion(),
+				Parser:                  hclparse.NewParser(),
+				PluginConfig:            c.CoreConfig.Components.PluginConfig,
+			}
+			cfg, diags := parser.Parse(cla.Path, cla.VarFiles, cla.Vars)
+			if !diags.HasErrors() {
+				diags = append(diags, cfg.RunTest(run)...)
+			}
+
+			if diags.HasErrors() {
+				failed++
+				c.Ui.Error(fmt.Sprintf("  run %q... fail", run.Name))
+			} else {
+				passed++
+				c.Ui.Say(fmt.Sprintf("  run %q... pass", run.Name))
+			}
+
+			files := map[string]*hcl.File{}
+			for name, file := range parser.Files() {
+				files[name] = file
+			}
+			for name, file := range testParser.Files() {
+				files[name] = file
+			}
+			writeDiags(c.Ui, files, diags)
+		}
+	}
+
+	summary := fmt.Sprintf("%d passed, %d failed.", passed, failed)
+	if failed > 0 {
+		c.Ui.Error("\nFailure! " + summary)
+		return 1
+	}
+	c.Ui.Say("\nSuccess! " + summary)
+	return 0
+}
+
+func (*TestCommand) Help() string {
+	helpText := `
+Usage: packer test [options] TEMPLATE
+
+  Runs the tests of an HCL2 template, without building anything. Tests are
+  written in the files of the template directory that end with
+  ".pkrtest.hcl" or ".pkrtest.json".
+
+  Each run block of a test file sets variables and mocks the values of data
+  sources, then checks its assert blocks against the evaluated template.
+  Data sources are never executed: those that are not mocked are unknown.
+
+Options:
+
+  -var 'key=value'       Variable for templates, can be used multiple times.
+  -var-file=path         JSON or HCL2 file containing user variables.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (*TestCommand) Synopsis() string {
+	return "run the tests of a template"
+}
+
+func (*TestCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (*TestCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-var":      complete.PredictNothing,
+		"-var-file": complete.PredictNothing,
+	}
+}
@@ -0,0 +1,52 @@
+package command
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTest(t *testing.T) {
+	c := &TestCommand{
+		Meta: testMetaFile(t),
+	}
+	args := []string{filepath.Join(testFixture("hcl"), "test", "pass")}
+
+	if code := c.Run(args); code != 0 {
+		fatalCommand(t, c.Meta)
+	}
+
+	out, _ := outputCommand(t, c.Meta)
+	for _, expected := range []string{
+		`run "default"... pass`,
+		`run "chocolate"... pass`,
+		"Success! 2 passed, 0 failed.",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected %q in output:\n%s", expected, out)
+		}
+	}
+}
+
+func TestTest_failure(t *testing.T) {
+	c := &TestCommand{
+		Meta: testMetaFile(t),
+	}
+	args := []string{filepath.Join(testFixture("hcl"), "test", "fail")}
+
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	_, stderr := outputCommand(t, c.Meta)
+	for _, expected := range []string{
+		`run "strawberry"... fail`,
+		"Test assertion failed",
+		"The cake is vanilla.",
+		"Failure! 0 passed, 1 failed.",
+	} {
+		if !strings.Contains(stderr, expected) {
+			t.Errorf("expected %q in errors:\n%s", expected, stderr)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"strings"
 
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
 	"github.com/hashicorp/packer/packer"
 
 	"github.com/posener/complete"
@@ -45,6 +46,11 @@ func (c *ValidateCommand) ParseArgs(args []string) (*ValidateArgs, int) {
 }
 
 func (c *ValidateCommand) RunContext(ctx context.Context, cla *ValidateArgs) int {
+	if cla.JSONDiagnostics {
+		defer func(ui packersdk.Ui) { c.Ui = ui }(c.Ui)
+		c.Ui = newJSONDiagnosticsUi(c.Ui)
+	}
+
 	packerStarter, ret := c.GetConfig(&cla.MetaArgs)
 	if ret != 0 {
 		return 1
@@ -52,7 +58,9 @@ func (c *ValidateCommand) RunContext(ctx context.Context, cla *ValidateArgs) int
 
 	// If we're only checking syntax, then we're done already
 	if cla.SyntaxOnly {
-		c.Ui.Say("Syntax-only check passed. Everything looks okay.")
+		if !cla.JSONDiagnostics {
+			c.Ui.Say("Syntax-only check passed. Everything looks okay.")
+		}
 		return 0
 	}
 
@@ -92,6 +100,7 @@ Options:
 
   -syntax-only           Only check syntax. Do not verify config of the template.
   -except=foo,bar,baz    Validate all builds other than these.
+  -json-diagnostics      Write the errors and warnings as JSON, one per line.
   -machine-readable      Produce machine-readable output.
   -only=foo,bar,baz      Validate only these builds.
   -var 'key=value'       Variable for templates, can be used multiple times.
@@ -113,6 +122,7 @@ func (*ValidateCommand) AutocompleteFlags() complete.Flags {
 	return complete.Flags{
 		"-syntax-only":      complete.PredictNothing,
 		"-except":           complete.PredictNothing,
+		"-json-diagnostics": complete.PredictNothing,
 		"-only":             complete.PredictNothing,
 		"-var":              complete.PredictNothing,
 		"-machine-readable": complete.PredictNothing,
@@ -1,7 +1,9 @@
 package command
 
 import (
+	"encoding/json"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -197,3 +199,59 @@ func TestValidateCommandExcept(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateCommand_JSONDiagnostics(t *testing.T) {
+	c := &ValidateCommand{
+		Meta: testMetaFile(t),
+	}
+	path := filepath.Join(testFixture("validate"), "var_foo_with_no_default.pkr.hcl")
+	if code := c.Run([]string{"-json-diagnostics", path}); code != 1 {
+		t.Fatalf("Expected exit code 1")
+	}
+
+	stdout, stderr := outputCommand(t, c.Meta)
+	if stderr != "" {
+		t.Fatalf("Unexpected error output: %s", stderr)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Expected a single diagnostic, got: %s", stdout)
+	}
+	var diag jsonDiagnostic
+	if err := json.Unmarshal([]byte(lines[0]), &diag); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if diag.Severity != "error" || diag.Summary != "Unset variable \"foo\"" {
+		t.Fatalf("Unexpected diagnostic: %#v", diag)
+	}
+	if diag.Range == nil || diag.Range.Filename != path || diag.Range.Start.Line != 2 {
+		t.Fatalf("Unexpected range: %#v", diag.Range)
+	}
+}
+
+func TestValidateCommand_JSONDiagnostics_legacyJSON(t *testing.T) {
+	c := &ValidateCommand{
+		Meta: testMetaFile(t),
+	}
+	c.CoreConfig.Version = "100.0.0"
+	args := []string{
+		"-json-diagnostics",
+		filepath.Join(testFixture("validate"), "template.json"),
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("Expected exit code 1")
+	}
+
+	stdout, _ := outputCommand(t, c.Meta)
+	var diag jsonDiagnostic
+	if err := json.Unmarshal([]byte(stdout), &diag); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := jsonDiagnostic{
+		Severity: "error",
+		Detail:   "This template requires Packer version 101.0.0 or higher; using 100.0.0",
+	}
+	if diff := cmp.Diff(expected, diag); diff != "" {
+		t.Errorf("Unexpected diagnostic: %s", diff)
+	}
+}
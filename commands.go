@@ -56,12 +56,36 @@ func init() {
 			}, nil
 		},
 
+		"plan": func() (cli.Command, error) {
+			return &command.PlanCommand{
+				Meta: *CommandMeta,
+			}, nil
+		},
+
 		"plugin": func() (cli.Command, error) {
 			return &command.PluginCommand{
 				Meta: *CommandMeta,
 			}, nil
 		},
 
+		"plugins install": func() (cli.Command, error) {
+			return &command.PluginsInstallCommand{
+				Meta: *CommandMeta,
+			}, nil
+		},
+
+		"plugins mirror": func() (cli.Command, error) {
+			return &command.PluginsMirrorCommand{
+				Meta: *CommandMeta,
+			}, nil
+		},
+
+		"test": func() (cli.Command, error) {
+			return &command.TestCommand{
+				Meta: *CommandMeta,
+			}, nil
+		},
+
 		"validate": func() (cli.Command, error) {
 			return &command.ValidateCommand{
 				Meta: *CommandMeta,
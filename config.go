@@ -26,6 +26,8 @@ type config struct {
 	RawBuilders                map[string]string `json:"builders"`
 	RawProvisioners            map[string]string `json:"provisioners"`
 	RawPostProcessors          map[string]string `json:"post-processors"`
+	PluginMirror               string            `json:"plugin_mirror"`
+	Hooks                      packer.EventHooks `json:"hooks"`
 
 	Plugins *packer.PluginConfig
 }
@@ -0,0 +1,104 @@
+//go:generate packer-sdc struct-markdown
+//go:generate packer-sdc mapstructure-to-hcl2 -type DatasourceOutput,Config
+
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hcldec"
+	"github.com/hashicorp/packer-plugin-sdk/hcl2helper"
+	"github.com/hashicorp/packer-plugin-sdk/template/config"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type Config struct {
+	// The path of a directory of the git repository. This defaults to the
+	// current directory. Use `path.root` for the repository of the template.
+	Path string `mapstructure:"path" required:"false"`
+}
+
+type Datasource struct {
+	config Config
+}
+
+type DatasourceOutput struct {
+	// The SHA of the commit checked out.
+	CommitSha string `mapstructure:"commit_sha"`
+	// The abbreviated SHA of the commit checked out.
+	ShortSha string `mapstructure:"short_sha"`
+	// The name of the branch checked out. It is empty when the `HEAD` is
+	// detached, like in the checkouts of many CI systems.
+	Branch string `mapstructure:"branch"`
+	// The tag pointing to the commit checked out. It is empty when the commit
+	// isn't tagged.
+	Tag string `mapstructure:"tag"`
+	// Whether the working tree has changes that aren't committed, including
+	// untracked files.
+	IsDirty bool `mapstructure:"is_dirty"`
+	// The author of the commit checked out, as `Name <email>`.
+	Author string `mapstructure:"author"`
+}
+
+func (d *Datasource) ConfigSpec() hcldec.ObjectSpec {
+	return d.config.FlatMapstructure().HCL2Spec()
+}
+
+func (d *Datasource) Configure(raws ...interface{}) error {
+	err := config.Decode(&d.config, nil, raws...)
+	if err != nil {
+		return err
+	}
+
+	if d.config.Path == "" {
+		d.config.Path = "."
+	}
+	return nil
+}
+
+func (d *Datasource) OutputSpec() hcldec.ObjectSpec {
+	return (&DatasourceOutput{}).FlatMapstructure().HCL2Spec()
+}
+
+func (d *Datasource) Execute() (cty.Value, error) {
+	var output DatasourceOutput
+	var err error
+
+	if output.CommitSha, err = d.git("rev-parse", "HEAD"); err != nil {
+		return cty.NullVal(cty.EmptyObject), err
+	}
+	if output.ShortSha, err = d.git("rev-parse", "--short", "HEAD"); err != nil {
+		return cty.NullVal(cty.EmptyObject), err
+	}
+	if output.Author, err = d.git("log", "-1", "--format=%an <%ae>"); err != nil {
+		return cty.NullVal(cty.EmptyObject), err
+	}
+	status, err := d.git("status", "--porcelain")
+	if err != nil {
+		return cty.NullVal(cty.EmptyObject), err
+	}
+	output.IsDirty = status != ""
+
+	// These fail when the HEAD is detached or isn't tagged.
+	output.Branch, _ = d.git("symbolic-ref", "--short", "-q", "HEAD")
+	output.Tag, _ = d.git("describe", "--tags", "--exact-match", "HEAD")
+
+	return hcl2helper.HCL2ValueFromConfig(output, d.OutputSpec()), nil
+}
+
+// git runs a git command in the repository and returns its output, without
+// the trailing newline.
+func (d *Datasource) git(args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", append([]string{"-C", d.config.Path}, args...)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("error running git %s in %s: %s: %s",
+			strings.Join(args, " "), d.config.Path, err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
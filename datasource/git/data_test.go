@@ -0,0 +1,104 @@
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// testRepository creates a git repository with a tagged commit on the main
+// branch.
+func testRepository(t *testing.T) string {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+
+	dir, err := ioutil.TempDir("", "packer-git")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "template.pkr.hcl"), []byte("\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"checkout", "-q", "-b", "main"},
+		{"add", "template.pkr.hcl"},
+		{"-c", "user.name=Packer", "-c", "user.email=packer@example.com", "commit", "-q", "-m", "Add template"},
+		{"tag", "v1.0.0"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %s: %s", args, err, out)
+		}
+	}
+	return dir
+}
+
+func TestDatasource_Execute(t *testing.T) {
+	dir := testRepository(t)
+	defer os.RemoveAll(dir)
+
+	var d Datasource
+	if err := d.Configure(map[string]interface{}{"path": dir}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	value, err := d.Execute()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	sha := value.GetAttr("commit_sha").AsString()
+	if len(sha) != 40 {
+		t.Fatalf("bad commit sha %q", sha)
+	}
+	if short := value.GetAttr("short_sha").AsString(); short == "" || sha[:len(short)] != short {
+		t.Fatalf("bad short sha %q", short)
+	}
+	for attr, expected := range map[string]string{
+		"branch": "main",
+		"tag":    "v1.0.0",
+		"author": "Packer <packer@example.com>",
+	} {
+		if got := value.GetAttr(attr).AsString(); got != expected {
+			t.Fatalf("expected %s to be %q, got %q", attr, expected, got)
+		}
+	}
+	if value.GetAttr("is_dirty").True() {
+		t.Fatal("repository should be clean")
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "variables.pkr.hcl"), []byte("\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	value, err = d.Execute()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !value.GetAttr("is_dirty").True() {
+		t.Fatal("repository should be dirty")
+	}
+}
+
+func TestDatasource_Execute_notRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	dir, err := ioutil.TempDir("", "packer-git")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var d Datasource
+	if err := d.Configure(map[string]interface{}{"path": dir}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := d.Execute(); err == nil {
+		t.Fatal("should fail outside of a git repository")
+	}
+}
@@ -0,0 +1,186 @@
+//go:generate packer-sdc struct-markdown
+//go:generate packer-sdc mapstructure-to-hcl2 -type DatasourceOutput,Config
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/hcl/v2/hcldec"
+	"github.com/hashicorp/packer-plugin-sdk/hcl2helper"
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+	"github.com/hashicorp/packer-plugin-sdk/template/config"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type Config struct {
+	// The URL to request data from. This URL must respond with a `200 OK`
+	// response.
+	Url string `mapstructure:"url" required:"true"`
+	// A map of strings representing additional HTTP headers to include in
+	// the request, for example an `Authorization` header.
+	RequestHeaders map[string]string `mapstructure:"request_headers" required:"false"`
+	// The user name used for HTTP basic authentication.
+	Username string `mapstructure:"username" required:"false"`
+	// The password used for HTTP basic authentication.
+	Password string `mapstructure:"password" required:"false"`
+	// A path to a value of the JSON response body, made of the object keys
+	// and array indexes separated by dots, for example `releases.0.iso_url`.
+	// The value is exposed as `value`. When it is not a string, it is
+	// exposed encoded as JSON.
+	JSONPath string `mapstructure:"json_path" required:"false"`
+	// The timeout of the request, including reading the response body, for
+	// example `1m`. Defaults to `30s`.
+	Timeout time.Duration `mapstructure:"timeout" required:"false"`
+}
+
+// defaultTimeout is the timeout of the request when none is configured.
+const defaultTimeout = 30 * time.Second
+
+type Datasource struct {
+	config Config
+}
+
+type DatasourceOutput struct {
+	// The URL the data was requested from.
+	Url string `mapstructure:"url"`
+	// The raw body of the HTTP response.
+	ResponseBody string `mapstructure:"body"`
+	// A map of strings representing the response HTTP headers. Duplicate
+	// headers are joined with a comma, as described in
+	// [RFC 2616](https://www.w3.org/Protocols/rfc2616/rfc2616-sec4.html#sec4.2).
+	ResponseHeaders map[string]string `mapstructure:"response_headers"`
+	// The value at `json_path` in the JSON response body. It is empty when
+	// `json_path` is not set.
+	Value string `mapstructure:"value"`
+}
+
+func (d *Datasource) ConfigSpec() hcldec.ObjectSpec {
+	return d.config.FlatMapstructure().HCL2Spec()
+}
+
+func (d *Datasource) Configure(raws ...interface{}) error {
+	err := config.Decode(&d.config, nil, raws...)
+	if err != nil {
+		return err
+	}
+
+	var errs *packersdk.MultiError
+	if d.config.Url == "" {
+		errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("the `url` must be specified"))
+	}
+	if d.config.Password != "" && d.config.Username == "" {
+		errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("the `username` must be specified with the `password`"))
+	}
+	if d.config.Timeout < 0 {
+		errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("the `timeout` must not be negative"))
+	}
+	if d.config.Timeout == 0 {
+		d.config.Timeout = defaultTimeout
+	}
+
+	if errs != nil && len(errs.Errors) > 0 {
+		return errs
+	}
+
+	packersdk.LogSecretFilter.Set(d.config.Password)
+	return nil
+}
+
+func (d *Datasource) OutputSpec() hcldec.ObjectSpec {
+	return (&DatasourceOutput{}).FlatMapstructure().HCL2Spec()
+}
+
+func (d *Datasource) Execute() (cty.Value, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.config.Url, nil)
+	if err != nil {
+		return cty.NullVal(cty.EmptyObject), err
+	}
+	for name, value := range d.config.RequestHeaders {
+		req.Header.Set(name, value)
+	}
+	if d.config.Username != "" {
+		req.SetBasicAuth(d.config.Username, d.config.Password)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return cty.NullVal(cty.EmptyObject), err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return cty.NullVal(cty.EmptyObject), fmt.Errorf("HTTP request to %s failed with status: %s",
+			d.config.Url, resp.Status)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return cty.NullVal(cty.EmptyObject), fmt.Errorf("error reading response body: %s", err)
+	}
+
+	output := DatasourceOutput{
+		Url:             d.config.Url,
+		ResponseBody:    string(body),
+		ResponseHeaders: make(map[string]string),
+	}
+	for name, values := range resp.Header {
+		output.ResponseHeaders[name] = strings.Join(values, ", ")
+	}
+
+	if d.config.JSONPath != "" {
+		output.Value, err = jsonPathValue(body, d.config.JSONPath)
+		if err != nil {
+			return cty.NullVal(cty.EmptyObject), err
+		}
+	}
+
+	return hcl2helper.HCL2ValueFromConfig(output, d.OutputSpec()), nil
+}
+
+// jsonPathValue returns the value at path in the JSON document data. Strings
+// are returned as is, other values are encoded as JSON.
+func jsonPathValue(data []byte, path string) (string, error) {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return "", fmt.Errorf("error parsing JSON response: %s", err)
+	}
+
+	for _, key := range strings.Split(path, ".") {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			child, ok := v[key]
+			if !ok {
+				return "", fmt.Errorf("json_path %q: key %q not found", path, key)
+			}
+			value = child
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return "", fmt.Errorf("json_path %q: invalid index %q of an array of %d elements", path, key, len(v))
+			}
+			value = v[i]
+		default:
+			return "", fmt.Errorf("json_path %q: %q is not an object or an array", path, key)
+		}
+	}
+
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
@@ -0,0 +1,153 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func testServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/release.json":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"releases":[{"version":"1.2","iso":{"url":"https://example.com/1.2.iso","size":42}}]}`)
+		case "/private":
+			if user, pass, ok := r.BasicAuth(); !ok || user != "packer" || pass != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if r.Header.Get("X-Api-Version") != "2" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, "hello")
+		case "/slow":
+			time.Sleep(500 * time.Millisecond)
+			fmt.Fprint(w, "hello")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestDatasource_Configure(t *testing.T) {
+	tc := []struct {
+		name   string
+		config map[string]interface{}
+		fail   bool
+	}{
+		{"url", map[string]interface{}{"url": "https://example.com"}, false},
+		{"no url", map[string]interface{}{}, true},
+		{"password without username", map[string]interface{}{"url": "https://example.com", "password": "secret"}, true},
+		{"timeout", map[string]interface{}{"url": "https://example.com", "timeout": "1m"}, false},
+		{"negative timeout", map[string]interface{}{"url": "https://example.com", "timeout": "-1m"}, true},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			var d Datasource
+			err := d.Configure(tt.config)
+			if tt.fail && err == nil {
+				t.Fatal("should fail")
+			}
+			if !tt.fail && err != nil {
+				t.Fatalf("err: %s", err)
+			}
+		})
+	}
+}
+
+func TestDatasource_Execute(t *testing.T) {
+	ts := testServer()
+	defer ts.Close()
+
+	tc := []struct {
+		name  string
+		path  string
+		value string
+	}{
+		{"string", "releases.0.iso.url", "https://example.com/1.2.iso"},
+		{"number", "releases.0.iso.size", "42"},
+		{"object", "releases.0.iso", `{"size":42,"url":"https://example.com/1.2.iso"}`},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			var d Datasource
+			err := d.Configure(map[string]interface{}{
+				"url":       ts.URL + "/release.json",
+				"json_path": tt.path,
+			})
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			output, err := d.Execute()
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if value := output.GetAttr("value").AsString(); value != tt.value {
+				t.Fatalf("expected value %q, got %q", tt.value, value)
+			}
+			headers := output.GetAttr("response_headers").AsValueMap()
+			if headers["Content-Type"].AsString() != "application/json" {
+				t.Fatalf("bad headers: %#v", headers)
+			}
+		})
+	}
+}
+
+func TestDatasource_Execute_auth(t *testing.T) {
+	ts := testServer()
+	defer ts.Close()
+
+	var d Datasource
+	err := d.Configure(map[string]interface{}{
+		"url":             ts.URL + "/private",
+		"username":        "packer",
+		"password":        "secret",
+		"request_headers": map[string]string{"X-Api-Version": "2"},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	output, err := d.Execute()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if body := output.GetAttr("body").AsString(); body != "hello" {
+		t.Fatalf("bad body: %q", body)
+	}
+}
+
+func TestDatasource_Execute_errors(t *testing.T) {
+	ts := testServer()
+	defer ts.Close()
+
+	tc := []struct {
+		name   string
+		config map[string]interface{}
+	}{
+		{"not found", map[string]interface{}{"url": ts.URL + "/missing"}},
+		{"unauthorized", map[string]interface{}{"url": ts.URL + "/private"}},
+		{"missing key", map[string]interface{}{"url": ts.URL + "/release.json", "json_path": "releases.0.checksum"}},
+		{"bad index", map[string]interface{}{"url": ts.URL + "/release.json", "json_path": "releases.1"}},
+		{"not json", map[string]interface{}{"url": ts.URL + "/private", "json_path": "version",
+			"username": "packer", "password": "secret", "request_headers": map[string]string{"X-Api-Version": "2"}}},
+		{"timeout", map[string]interface{}{"url": ts.URL + "/slow", "timeout": "50ms"}},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			var d Datasource
+			if err := d.Configure(tt.config); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if _, err := d.Execute(); err == nil {
+				t.Fatal("should fail")
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+//go:generate packer-sdc struct-markdown
+//go:generate packer-sdc mapstructure-to-hcl2 -type DatasourceOutput,Config
+
+package localexec
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/hcl/v2/hcldec"
+	"github.com/hashicorp/packer-plugin-sdk/hcl2helper"
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+	"github.com/hashicorp/packer-plugin-sdk/template/config"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type Config struct {
+	// The command to run and its arguments, for example
+	// `["python3", "lookup.py", "--env", "prod"]`. The command isn't run in a
+	// shell. It must write a JSON object to its standard output.
+	Command []string `mapstructure:"command" required:"true"`
+	// The directory the command is run in. This defaults to the current
+	// directory.
+	WorkingDirectory string `mapstructure:"working_directory" required:"false"`
+	// Environment variables set for the command, in addition to the
+	// environment of Packer.
+	Environment map[string]string `mapstructure:"environment" required:"false"`
+	// The time to wait for the command to exit, for example `5m`. This
+	// defaults to `1m`.
+	Timeout time.Duration `mapstructure:"timeout" required:"false"`
+}
+
+type Datasource struct {
+	config Config
+}
+
+type DatasourceOutput struct {
+	// The keys and values of the JSON object written by the command. Values
+	// that are not strings are encoded as JSON, and can be decoded with the
+	// `jsondecode` function.
+	Result map[string]string `mapstructure:"result"`
+	// The raw standard output of the command.
+	Stdout string `mapstructure:"stdout"`
+}
+
+func (d *Datasource) ConfigSpec() hcldec.ObjectSpec {
+	return d.config.FlatMapstructure().HCL2Spec()
+}
+
+func (d *Datasource) Configure(raws ...interface{}) error {
+	err := config.Decode(&d.config, nil, raws...)
+	if err != nil {
+		return err
+	}
+
+	var errs *packersdk.MultiError
+	if len(d.config.Command) == 0 || d.config.Command[0] == "" {
+		errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("the `command` must be specified"))
+	}
+	if d.config.Timeout < 0 {
+		errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("the `timeout` can't be negative"))
+	}
+	if d.config.Timeout == 0 {
+		d.config.Timeout = time.Minute
+	}
+
+	if errs != nil && len(errs.Errors) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (d *Datasource) OutputSpec() hcldec.ObjectSpec {
+	return (&DatasourceOutput{}).FlatMapstructure().HCL2Spec()
+}
+
+func (d *Datasource) Execute() (cty.Value, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.config.Timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, d.config.Command[0], d.config.Command[1:]...)
+	cmd.Dir = d.config.WorkingDirectory
+	cmd.Env = os.Environ()
+	for k, v := range d.config.Environment {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
+	}
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	name := strings.Join(d.config.Command, " ")
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			err = fmt.Errorf("timed out after %s", d.config.Timeout)
+		}
+		return cty.NullVal(cty.EmptyObject), fmt.Errorf("error running %q: %s: %s",
+			name, err, strings.TrimSpace(stderr.String()))
+	}
+
+	result, err := parseResult(stdout.Bytes())
+	if err != nil {
+		return cty.NullVal(cty.EmptyObject), fmt.Errorf("error parsing the output of %q: %s", name, err)
+	}
+
+	output := DatasourceOutput{
+		Result: result,
+		Stdout: stdout.String(),
+	}
+	return hcl2helper.HCL2ValueFromConfig(output, d.OutputSpec()), nil
+}
+
+// parseResult parses the JSON object written by the command. Values that are
+// not strings are kept as JSON.
+func parseResult(stdout []byte) (map[string]string, error) {
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(stdout, &values); err != nil {
+		return nil, fmt.Errorf("the command must write a JSON object to its standard output: %s", err)
+	}
+	if values == nil {
+		return nil, fmt.Errorf("the command must write a JSON object to its standard output, got null")
+	}
+
+	result := make(map[string]string, len(values))
+	for key, value := range values {
+		var s string
+		if err := json.Unmarshal(value, &s); err == nil {
+			result[key] = s
+		} else {
+			result[key] = string(value)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,102 @@
+package localexec
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestDatasource_Configure(t *testing.T) {
+	tc := []struct {
+		name   string
+		config map[string]interface{}
+		fail   bool
+	}{
+		{"command", map[string]interface{}{"command": []string{"lookup"}}, false},
+		{"no command", map[string]interface{}{}, true},
+		{"timeout", map[string]interface{}{"command": []string{"lookup"}, "timeout": "5m"}, false},
+		{"negative timeout", map[string]interface{}{"command": []string{"lookup"}, "timeout": "-5m"}, true},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			var d Datasource
+			err := d.Configure(tt.config)
+			if tt.fail && err == nil {
+				t.Fatal("should fail")
+			}
+			if !tt.fail && err != nil {
+				t.Fatalf("err: %s", err)
+			}
+		})
+	}
+}
+
+func TestDatasource_Execute(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test commands need a POSIX shell")
+	}
+
+	tc := []struct {
+		name     string
+		script   string
+		expected map[string]string
+		fail     bool
+	}{
+		{"strings", `echo '{"subnet":"subnet-1234","zone":"eu-west-1a"}'`, map[string]string{"subnet": "subnet-1234", "zone": "eu-west-1a"}, false},
+		{"json values", `echo '{"count":3,"tags":["a","b"]}'`, map[string]string{"count": "3", "tags": `["a","b"]`}, false},
+		{"environment", `echo "{\"env\":\"$LOOKUP_ENV\"}"`, map[string]string{"env": "prod"}, false},
+		{"not an object", `echo '["a"]'`, nil, true},
+		{"not json", `echo hello`, nil, true},
+		{"failure", `echo '{}'; exit 1`, nil, true},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			var d Datasource
+			err := d.Configure(map[string]interface{}{
+				"command":     []string{"sh", "-c", tt.script},
+				"environment": map[string]string{"LOOKUP_ENV": "prod"},
+			})
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			value, err := d.Execute()
+			if tt.fail {
+				if err == nil {
+					t.Fatal("should fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			result := value.GetAttr("result").AsValueMap()
+			if len(result) != len(tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, result)
+			}
+			for k, v := range tt.expected {
+				if got := result[k].AsString(); got != v {
+					t.Fatalf("expected %s to be %q, got %q", k, v, got)
+				}
+			}
+		})
+	}
+}
+
+func TestDatasource_Execute_timeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test commands need a POSIX shell")
+	}
+
+	var d Datasource
+	err := d.Configure(map[string]interface{}{
+		"command": []string{"sleep", "5"},
+		"timeout": "100ms",
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := d.Execute(); err == nil {
+		t.Fatal("should time out")
+	}
+}
@@ -0,0 +1,46 @@
+package vault
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+)
+
+// awsLoginData returns the data to log in with the AWS IAM auth method: a
+// signed sts:GetCallerIdentity request, that Vault sends to AWS to
+// authenticate the caller.
+func awsLoginData(role, serverID string) (map[string]interface{}, error) {
+	sess, err := session.NewSession()
+	if err != nil {
+		return nil, fmt.Errorf("error creating AWS session: %s", err)
+	}
+
+	req, _ := sts.New(sess).GetCallerIdentityRequest(&sts.GetCallerIdentityInput{})
+	if serverID != "" {
+		req.HTTPRequest.Header.Add("X-Vault-AWS-IAM-Server-ID", serverID)
+	}
+	if err := req.Sign(); err != nil {
+		return nil, fmt.Errorf("error signing sts:GetCallerIdentity request: %s", err)
+	}
+
+	headers, err := json.Marshal(req.HTTPRequest.Header)
+	if err != nil {
+		return nil, err
+	}
+	body, err := ioutil.ReadAll(req.HTTPRequest.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"role":                    role,
+		"iam_http_request_method": req.HTTPRequest.Method,
+		"iam_request_url":         base64.StdEncoding.EncodeToString([]byte(req.HTTPRequest.URL.String())),
+		"iam_request_headers":     base64.StdEncoding.EncodeToString(headers),
+		"iam_request_body":        base64.StdEncoding.EncodeToString(body),
+	}, nil
+}
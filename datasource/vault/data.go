@@ -0,0 +1,287 @@
+//go:generate packer-sdc struct-markdown
+//go:generate packer-sdc mapstructure-to-hcl2 -type DatasourceOutput,Config
+
+package vault
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/hashicorp/hcl/v2/hcldec"
+	"github.com/hashicorp/packer-plugin-sdk/hcl2helper"
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+	"github.com/hashicorp/packer-plugin-sdk/template/config"
+	"github.com/hashicorp/vault/api"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	authMethodToken   = "token"
+	authMethodAppRole = "approle"
+	authMethodAWS     = "aws"
+)
+
+type Config struct {
+	// The address of the Vault server. This defaults to the `VAULT_ADDR`
+	// environment variable.
+	Address string `mapstructure:"address" required:"false"`
+	// The Vault Enterprise namespace of the secret. This defaults to the
+	// `VAULT_NAMESPACE` environment variable.
+	Namespace string `mapstructure:"namespace" required:"false"`
+	// The path of the secret to read, for example `secret/data/app` for a
+	// secret of a KV version 2 secrets engine mounted at `secret`, or
+	// `database/creds/readonly` for dynamic database credentials.
+	Path string `mapstructure:"path" required:"true"`
+	// The version of the KV secrets engine of the secret, `1` or `2`. The
+	// data of a KV version 2 secret is nested in a `data` object, that is
+	// unwrapped. By default, the version is detected from the secret.
+	KVVersion int `mapstructure:"kv_version" required:"false"`
+	// The method used to log in to Vault: `token`, `approle` or `aws` for
+	// the AWS IAM auth method. This defaults to `token`.
+	AuthMethod string `mapstructure:"auth_method" required:"false"`
+	// The path the auth method is mounted at. This defaults to the name of
+	// the auth method.
+	AuthMount string `mapstructure:"auth_mount" required:"false"`
+	// The token used with the `token` auth method. This defaults to the
+	// `VAULT_TOKEN` environment variable.
+	Token string `mapstructure:"token" required:"false"`
+	// The role ID used with the `approle` auth method.
+	RoleID string `mapstructure:"role_id" required:"false"`
+	// The secret ID used with the `approle` auth method.
+	SecretID string `mapstructure:"secret_id" required:"false"`
+	// The Vault role used with the `aws` auth method. The request to the AWS
+	// STS is signed with the AWS credentials found in the environment.
+	AWSRole string `mapstructure:"aws_role" required:"false"`
+	// The value of the `X-Vault-AWS-IAM-Server-ID` header signed with the
+	// `aws` auth method, when the auth method requires one.
+	AWSIAMServerID string `mapstructure:"aws_iam_server_id" required:"false"`
+	// Renew the lease of the secret until Packer exits, for dynamic secrets
+	// that may expire before the end of the build. This defaults to `true`.
+	// Leases that are not renewable are never renewed.
+	RenewLease *bool `mapstructure:"renew_lease" required:"false"`
+}
+
+type Datasource struct {
+	config Config
+}
+
+type DatasourceOutput struct {
+	// The data of the secret. Values that are not strings are encoded as
+	// JSON.
+	Data map[string]string `mapstructure:"data"`
+	// The ID of the lease of a dynamic secret.
+	LeaseID string `mapstructure:"lease_id"`
+	// The duration of the lease of a dynamic secret, in seconds.
+	LeaseDuration int `mapstructure:"lease_duration"`
+	// Whether the lease of the secret can be renewed.
+	Renewable bool `mapstructure:"renewable"`
+}
+
+func (d *Datasource) ConfigSpec() hcldec.ObjectSpec {
+	return d.config.FlatMapstructure().HCL2Spec()
+}
+
+func (d *Datasource) Configure(raws ...interface{}) error {
+	err := config.Decode(&d.config, nil, raws...)
+	if err != nil {
+		return err
+	}
+
+	if d.config.AuthMethod == "" {
+		d.config.AuthMethod = authMethodToken
+	}
+	if d.config.AuthMount == "" {
+		d.config.AuthMount = d.config.AuthMethod
+	}
+	if d.config.RenewLease == nil {
+		renew := true
+		d.config.RenewLease = &renew
+	}
+
+	var errs *packersdk.MultiError
+	if d.config.Path == "" {
+		errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("the `path` must be specified"))
+	}
+	if d.config.KVVersion != 0 && d.config.KVVersion != 1 && d.config.KVVersion != 2 {
+		errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("the `kv_version` must be 1 or 2, got %d", d.config.KVVersion))
+	}
+
+	switch d.config.AuthMethod {
+	case authMethodToken:
+	case authMethodAppRole:
+		if d.config.RoleID == "" {
+			errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("the `role_id` must be specified with the approle auth method"))
+		}
+	case authMethodAWS:
+		if d.config.AWSRole == "" {
+			errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("the `aws_role` must be specified with the aws auth method"))
+		}
+	default:
+		errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("the `auth_method` must be one of %q, %q or %q, got %q",
+			authMethodToken, authMethodAppRole, authMethodAWS, d.config.AuthMethod))
+	}
+
+	if errs != nil && len(errs.Errors) > 0 {
+		return errs
+	}
+
+	packersdk.LogSecretFilter.Set(d.config.Token, d.config.SecretID)
+	return nil
+}
+
+func (d *Datasource) OutputSpec() hcldec.ObjectSpec {
+	return (&DatasourceOutput{}).FlatMapstructure().HCL2Spec()
+}
+
+func (d *Datasource) Execute() (cty.Value, error) {
+	client, err := d.client()
+	if err != nil {
+		return cty.NullVal(cty.EmptyObject), err
+	}
+
+	secret, err := client.Logical().Read(d.config.Path)
+	if err != nil {
+		return cty.NullVal(cty.EmptyObject), fmt.Errorf("error reading vault secret %s: %s", d.config.Path, err)
+	}
+	if secret == nil {
+		return cty.NullVal(cty.EmptyObject), fmt.Errorf("vault secret %s not found", d.config.Path)
+	}
+
+	data, err := secretData(secret.Data, d.config.KVVersion)
+	if err != nil {
+		return cty.NullVal(cty.EmptyObject), fmt.Errorf("error reading vault secret %s: %s", d.config.Path, err)
+	}
+	values := make([]string, 0, len(data))
+	for _, v := range data {
+		values = append(values, v)
+	}
+	packersdk.LogSecretFilter.Set(values...)
+
+	if secret.Renewable && secret.LeaseID != "" && *d.config.RenewLease {
+		if err := renewLease(client, secret); err != nil {
+			return cty.NullVal(cty.EmptyObject), err
+		}
+	}
+
+	output := DatasourceOutput{
+		Data:          data,
+		LeaseID:       secret.LeaseID,
+		LeaseDuration: secret.LeaseDuration,
+		Renewable:     secret.Renewable,
+	}
+	return hcl2helper.HCL2ValueFromConfig(output, d.OutputSpec()), nil
+}
+
+// client returns a Vault client logged in with the configured auth method.
+func (d *Datasource) client() (*api.Client, error) {
+	vaultConfig := api.DefaultConfig()
+	if err := vaultConfig.Error; err != nil {
+		return nil, fmt.Errorf("error configuring vault client: %s", err)
+	}
+	if d.config.Address != "" {
+		vaultConfig.Address = d.config.Address
+	}
+
+	client, err := api.NewClient(vaultConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error creating vault client: %s", err)
+	}
+	if d.config.Namespace != "" {
+		client.SetNamespace(d.config.Namespace)
+	} else if namespace := os.Getenv("VAULT_NAMESPACE"); namespace != "" {
+		client.SetNamespace(namespace)
+	}
+
+	switch d.config.AuthMethod {
+	case authMethodToken:
+		if d.config.Token != "" {
+			client.SetToken(d.config.Token)
+		}
+	case authMethodAppRole:
+		err = login(client, d.config.AuthMount, map[string]interface{}{
+			"role_id":   d.config.RoleID,
+			"secret_id": d.config.SecretID,
+		})
+	case authMethodAWS:
+		var loginData map[string]interface{}
+		loginData, err = awsLoginData(d.config.AWSRole, d.config.AWSIAMServerID)
+		if err == nil {
+			err = login(client, d.config.AuthMount, loginData)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error logging in to vault with the %s auth method: %s", d.config.AuthMethod, err)
+	}
+	return client, nil
+}
+
+// login logs in to the auth method mounted at mount, and sets the token of
+// the client.
+func login(client *api.Client, mount string, data map[string]interface{}) error {
+	secret, err := client.Logical().Write(fmt.Sprintf("auth/%s/login", mount), data)
+	if err != nil {
+		return err
+	}
+	if secret == nil || secret.Auth == nil || secret.Auth.ClientToken == "" {
+		return fmt.Errorf("no token returned")
+	}
+	packersdk.LogSecretFilter.Set(secret.Auth.ClientToken)
+	client.SetToken(secret.Auth.ClientToken)
+	return nil
+}
+
+// renewLease renews the lease of the secret in the background, until the
+// lease can't be renewed anymore or Packer exits.
+func renewLease(client *api.Client, secret *api.Secret) error {
+	renewer, err := client.NewRenewer(&api.RenewerInput{Secret: secret})
+	if err != nil {
+		return fmt.Errorf("error renewing vault lease %s: %s", secret.LeaseID, err)
+	}
+	go renewer.Renew()
+	go func() {
+		for {
+			select {
+			case err := <-renewer.DoneCh():
+				if err != nil {
+					log.Printf("[WARN] vault lease %s is no longer renewed: %s", secret.LeaseID, err)
+				}
+				return
+			case renewal := <-renewer.RenewCh():
+				log.Printf("[DEBUG] renewed vault lease %s at %s", secret.LeaseID, renewal.RenewedAt)
+			}
+		}
+	}()
+	return nil
+}
+
+// secretData returns the data of a secret, unwrapping the data of a secret
+// of a KV version 2 secrets engine.
+func secretData(data map[string]interface{}, kvVersion int) (map[string]string, error) {
+	nested, isKV2 := data["data"].(map[string]interface{})
+	if _, ok := data["metadata"]; !ok && kvVersion == 0 {
+		isKV2 = false
+	}
+	switch {
+	case kvVersion == 2 && !isKV2:
+		return nil, fmt.Errorf("the secret is not a KV version 2 secret, " +
+			"its path must include `data/` after the mount path of the secrets engine")
+	case kvVersion != 1 && isKV2:
+		data = nested
+	}
+
+	result := make(map[string]string, len(data))
+	for k, v := range data {
+		if s, ok := v.(string); ok {
+			result[k] = s
+			continue
+		}
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		result[k] = string(encoded)
+	}
+	return result, nil
+}
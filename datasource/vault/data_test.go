@@ -0,0 +1,164 @@
+package vault
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func testServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/auth/approle/login":
+			var data map[string]string
+			if err := json.NewDecoder(r.Body).Decode(&data); err != nil ||
+				data["role_id"] != "packer" || data["secret_id"] != "secret" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, `{"auth":{"client_token":"approle-token"}}`)
+			return
+		}
+
+		if token := r.Header.Get("X-Vault-Token"); token != "root" && token != "approle-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/kv/app":
+			fmt.Fprint(w, `{"data":{"user":"packer","port":22}}`)
+		case "/v1/secret/data/app":
+			fmt.Fprint(w, `{"data":{"data":{"user":"packer"},"metadata":{"version":3}}}`)
+		case "/v1/database/creds/readonly":
+			fmt.Fprint(w, `{"lease_id":"database/creds/readonly/abc","lease_duration":3600,"renewable":false,"data":{"username":"v-packer","password":"pass"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestDatasource_Configure(t *testing.T) {
+	tc := []struct {
+		name   string
+		config map[string]interface{}
+		fail   bool
+	}{
+		{"path", map[string]interface{}{"path": "secret/data/app"}, false},
+		{"no path", map[string]interface{}{}, true},
+		{"bad kv version", map[string]interface{}{"path": "secret/data/app", "kv_version": 3}, true},
+		{"approle", map[string]interface{}{"path": "secret/data/app", "auth_method": "approle", "role_id": "packer"}, false},
+		{"approle without role id", map[string]interface{}{"path": "secret/data/app", "auth_method": "approle"}, true},
+		{"aws without role", map[string]interface{}{"path": "secret/data/app", "auth_method": "aws"}, true},
+		{"unknown auth method", map[string]interface{}{"path": "secret/data/app", "auth_method": "ldap"}, true},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			var d Datasource
+			err := d.Configure(tt.config)
+			if tt.fail && err == nil {
+				t.Fatal("should fail")
+			}
+			if !tt.fail && err != nil {
+				t.Fatalf("err: %s", err)
+			}
+		})
+	}
+}
+
+func TestSecretData(t *testing.T) {
+	kv1 := map[string]interface{}{"user": "packer", "port": float64(22)}
+	kv2 := map[string]interface{}{
+		"data":     map[string]interface{}{"user": "packer"},
+		"metadata": map[string]interface{}{"version": float64(3)},
+	}
+
+	tc := []struct {
+		name      string
+		data      map[string]interface{}
+		kvVersion int
+		expected  map[string]string
+		fail      bool
+	}{
+		{"kv1", kv1, 0, map[string]string{"user": "packer", "port": "22"}, false},
+		{"kv2", kv2, 0, map[string]string{"user": "packer"}, false},
+		{"kv2 read as kv1", kv2, 1, map[string]string{"data": `{"user":"packer"}`, "metadata": `{"version":3}`}, false},
+		{"kv1 read as kv2", kv1, 2, nil, true},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := secretData(tt.data, tt.kvVersion)
+			if tt.fail {
+				if err == nil {
+					t.Fatal("should fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if len(data) != len(tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, data)
+			}
+			for k, v := range tt.expected {
+				if data[k] != v {
+					t.Fatalf("expected %v, got %v", tt.expected, data)
+				}
+			}
+		})
+	}
+}
+
+func TestDatasource_Execute(t *testing.T) {
+	ts := testServer()
+	defer ts.Close()
+
+	tc := []struct {
+		name   string
+		config map[string]interface{}
+		key    string
+		value  string
+		lease  string
+	}{
+		{"kv2", map[string]interface{}{"path": "secret/data/app", "token": "root"}, "user", "packer", ""},
+		{"approle", map[string]interface{}{"path": "kv/app", "auth_method": "approle", "role_id": "packer", "secret_id": "secret"}, "port", "22", ""},
+		{"dynamic", map[string]interface{}{"path": "database/creds/readonly", "token": "root"}, "username", "v-packer", "database/creds/readonly/abc"},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config["address"] = ts.URL
+			var d Datasource
+			if err := d.Configure(tt.config); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			value, err := d.Execute()
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			data := value.GetAttr("data").AsValueMap()
+			if got := data[tt.key].AsString(); got != tt.value {
+				t.Fatalf("expected %s to be %q, got %q", tt.key, tt.value, got)
+			}
+			if got := value.GetAttr("lease_id").AsString(); got != tt.lease {
+				t.Fatalf("expected lease %q, got %q", tt.lease, got)
+			}
+		})
+	}
+}
+
+func TestDatasource_Execute_notFound(t *testing.T) {
+	ts := testServer()
+	defer ts.Close()
+
+	var d Datasource
+	if err := d.Configure(map[string]interface{}{"address": ts.URL, "path": "secret/data/missing", "token": "root"}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := d.Execute(); err == nil {
+		t.Fatal("should fail on a missing secret")
+	}
+}
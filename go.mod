@@ -60,8 +60,10 @@ require (
 	github.com/hashicorp/packer-plugin-virtualbox v0.0.1
 	github.com/hashicorp/packer-plugin-vmware v0.0.1
 	github.com/hashicorp/packer-plugin-vsphere v0.0.1
+	github.com/hashicorp/vault/api v1.0.4
 	github.com/hetznercloud/hcloud-go v1.15.1
 	github.com/joyent/triton-go v0.0.0-20180628001255-830d2b111e62
+	github.com/klauspost/compress v1.11.7
 	github.com/klauspost/pgzip v0.0.0-20151221113845-47f36e165cec
 	github.com/masterzen/winrm v0.0.0-20201030141608-56ca5c5f2380
 	github.com/mattn/go-tty v0.0.0-20191112051231-74040eebce08
@@ -1,6 +1,7 @@
 package hcl2template
 
 import (
+	"regexp"
 	"testing"
 	"time"
 
@@ -335,11 +336,16 @@ var versionConstraintComparer = cmp.Comparer(func(x, y *version.Constraint) bool
 	return x.String() == y.String()
 })
 
+var regexpComparer = cmp.Comparer(func(x, y *regexp.Regexp) bool {
+	return x.String() == y.String()
+})
+
 var cmpOpts = []cmp.Option{
 	ctyValueComparer,
 	ctyTypeComparer,
 	versionComparer,
 	versionConstraintComparer,
+	regexpComparer,
 	cmpopts.IgnoreUnexported(
 		PackerConfig{},
 		Variable{},
@@ -2,6 +2,7 @@ package hcl2template
 
 import (
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/dynblock"
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
 )
@@ -13,6 +14,47 @@ type Decodable interface {
 	ConfigSpec() hcldec.ObjectSpec
 }
 
+// decodeHCL2Spec decodes body, after expanding its dynamic blocks. The dynamic
+// blocks are expanded with the same context as the rest of the body, so that
+// for example the dynamic blocks of a provisioner can use the source and build
+// variables.
 func decodeHCL2Spec(body hcl.Body, ectx *hcl.EvalContext, dec Decodable) (cty.Value, hcl.Diagnostics) {
-	return hcldec.Decode(body, dec.ConfigSpec(), ectx)
+	return hcldec.Decode(dynblock.Expand(body, ectx), dec.ConfigSpec(), ectx)
+}
+
+// dynamicBlockSchema is the schema of the dynamic blocks, as seen before they
+// are expanded.
+var dynamicBlockSchema = hcl.BlockHeaderSchema{Type: "dynamic", LabelNames: []string{"type"}}
+
+// expandDynamicBlocks returns the content of body, where dynamic blocks
+// generate blocks of the types of schema, like provisioner blocks in a build
+// block. The bodies of the blocks are not expanded, this is left to the
+// decoding of each block, as it can have a different evaluation context.
+func expandDynamicBlocks(body hcl.Body, schema *hcl.BodySchema, ectx *hcl.EvalContext) (*hcl.BodyContent, hcl.Diagnostics) {
+	content, diags := dynblock.Expand(body, ectx).Content(schema)
+	if diags.HasErrors() {
+		return content, diags
+	}
+
+	rawSchema := &hcl.BodySchema{
+		Attributes: schema.Attributes,
+		Blocks:     append([]hcl.BlockHeaderSchema{dynamicBlockSchema}, schema.Blocks...),
+	}
+	rawContent, _, _ := body.PartialContent(rawSchema)
+	if rawContent == nil {
+		return content, diags
+	}
+
+	// Blocks written in the body keep their definition range once expanded,
+	// blocks generated by a dynamic block have the range of the dynamic block.
+	rawBlocks := map[hcl.Range]*hcl.Block{}
+	for _, block := range rawContent.Blocks {
+		rawBlocks[block.DefRange] = block
+	}
+	for i, block := range content.Blocks {
+		if raw, found := rawBlocks[block.DefRange]; found && raw.Type == block.Type {
+			content.Blocks[i] = raw
+		}
+	}
+	return content, diags
 }
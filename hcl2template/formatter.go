@@ -8,17 +8,23 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hclwrite"
 )
 
 type HCL2Formatter struct {
 	ShowDiff, Write, Recursive bool
-	Output                     io.Writer
-	parser                     *hclparse.Parser
+	// SortAttributes sorts the attributes of each group of attributes of a
+	// block by name. A group of attributes is delimited by empty lines and
+	// nested blocks.
+	SortAttributes bool
+	Output         io.Writer
+	parser         *hclparse.Parser
 }
 
 // NewHCL2Formatter creates a new formatter, ready to format configuration files.
@@ -131,12 +137,16 @@ func (f *HCL2Formatter) processFile(filename string) ([]byte, error) {
 		return nil, fmt.Errorf("failed to read %s: %s", filename, err)
 	}
 
-	_, diags := f.parser.ParseHCL(inSrc, filename)
+	file, diags := f.parser.ParseHCL(inSrc, filename)
 	if diags.HasErrors() {
 		return nil, fmt.Errorf("failed to parse HCL %s", filename)
 	}
 
-	outSrc := hclwrite.Format(inSrc)
+	outSrc := inSrc
+	if body, ok := file.Body.(*hclsyntax.Body); ok && f.SortAttributes {
+		outSrc = sortAttributes(inSrc, body)
+	}
+	outSrc = hclwrite.Format(outSrc)
 
 	if bytes.Equal(inSrc, outSrc) {
 		return nil, nil
@@ -168,6 +178,111 @@ func (f *HCL2Formatter) processFile(filename string) ([]byte, error) {
 	return outSrc, nil
 }
 
+// attributeLines are the lines of an attribute, with its lead comments.
+type attributeLines struct {
+	name       string
+	start, end int
+}
+
+// sortAttributes sorts the attributes of body and of its nested blocks by
+// name, in src. Only the attributes written on consecutive lines are sorted
+// together; the comments written on the lines right above an attribute move
+// with it. Bodies with attributes that don't stand on their own lines, e.g.
+// single line blocks, are left as is.
+func sortAttributes(src []byte, body *hclsyntax.Body) []byte {
+	type replacement struct {
+		start, end int
+		data       []byte
+	}
+	var replacements []replacement
+
+	var walk func(body *hclsyntax.Body)
+	walk = func(body *hclsyntax.Body) {
+		for _, block := range body.Blocks {
+			walk(block.Body)
+		}
+
+		attrs := make([]attributeLines, 0, len(body.Attributes))
+		for name, attr := range body.Attributes {
+			lines, ok := attributeLinesOf(src, name, attr.SrcRange)
+			if !ok {
+				return
+			}
+			attrs = append(attrs, lines)
+		}
+		sort.Slice(attrs, func(i, j int) bool { return attrs[i].start < attrs[j].start })
+
+		for i := 0; i < len(attrs); {
+			j := i + 1
+			for j < len(attrs) && attrs[j-1].end == attrs[j].start {
+				j++
+			}
+			group := attrs[i:j]
+			i = j
+			if sort.SliceIsSorted(group, func(a, b int) bool { return group[a].name < group[b].name }) {
+				continue
+			}
+
+			start, end := group[0].start, group[len(group)-1].end
+			sorted := append([]attributeLines{}, group...)
+			sort.SliceStable(sorted, func(a, b int) bool { return sorted[a].name < sorted[b].name })
+			var data []byte
+			for _, attr := range sorted {
+				data = append(data, src[attr.start:attr.end]...)
+				if data[len(data)-1] != '\n' {
+					data = append(data, '\n')
+				}
+			}
+			if src[end-1] != '\n' {
+				data = data[:len(data)-1]
+			}
+			replacements = append(replacements, replacement{start, end, data})
+		}
+	}
+	walk(body)
+
+	if len(replacements) == 0 {
+		return src
+	}
+	sort.Slice(replacements, func(i, j int) bool { return replacements[i].start < replacements[j].start })
+	var out []byte
+	offset := 0
+	for _, r := range replacements {
+		out = append(out, src[offset:r.start]...)
+		out = append(out, r.data...)
+		offset = r.end
+	}
+	return append(out, src[offset:]...)
+}
+
+// attributeLinesOf returns the lines of the attribute in rng, including the
+// comment lines right above it and the end of its last line. It returns false
+// when the attribute shares a line with something else than a comment.
+func attributeLinesOf(src []byte, name string, rng hcl.Range) (attributeLines, bool) {
+	start := bytes.LastIndexByte(src[:rng.Start.Byte], '\n') + 1
+	if len(bytes.TrimSpace(src[start:rng.Start.Byte])) != 0 {
+		return attributeLines{}, false
+	}
+	for start > 0 {
+		prev := bytes.LastIndexByte(src[:start-1], '\n') + 1
+		line := bytes.TrimSpace(src[prev : start-1])
+		if !bytes.HasPrefix(line, []byte("#")) && !bytes.HasPrefix(line, []byte("//")) {
+			break
+		}
+		start = prev
+	}
+
+	end := len(src)
+	if i := bytes.IndexByte(src[rng.End.Byte:], '\n'); i >= 0 {
+		end = rng.End.Byte + i + 1
+	}
+	rest := bytes.TrimSpace(src[rng.End.Byte:end])
+	if len(rest) != 0 && !bytes.HasPrefix(rest, []byte("#")) && !bytes.HasPrefix(rest, []byte("//")) {
+		return attributeLines{}, false
+	}
+	return attributeLines{name: name, start: start, end: end}, true
+}
+
 // bytesDiff returns the unified diff of b1 and b2
 // Shamelessly copied from Terraform's fmt command.
 func bytesDiff(b1, b2 []byte, path string) (data []byte, err error) {
@@ -9,6 +9,9 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
 )
 
 func TestHCL2Formatter_Format(t *testing.T) {
@@ -107,3 +110,88 @@ func TestHCL2Formatter_Format_ShowDiff(t *testing.T) {
 	}
 
 }
+
+func TestHCL2Formatter_sortAttributes(t *testing.T) {
+	tt := []struct {
+		Name     string
+		Input    string
+		Expected string
+	}{
+		{
+			Name: "groups are sorted separately",
+			Input: `
+source "null" "example" {
+  // the communicator
+  communicator = "none"
+  boot_wait = "1s" # waiting
+
+  c = 3
+  b = 2
+  a = {
+    y = 1
+    x = 2
+  }
+}
+`,
+			Expected: `
+source "null" "example" {
+  boot_wait = "1s" # waiting
+  // the communicator
+  communicator = "none"
+
+  a = {
+    y = 1
+    x = 2
+  }
+  b = 2
+  c = 3
+}
+`,
+		},
+		{
+			Name: "nested blocks delimit groups",
+			Input: `
+build {
+  name = "b"
+  description = "a"
+
+  provisioner "shell" {
+    inline = ["echo"]
+    environment_vars = ["A=1"]
+  }
+  sources = ["source.null.example"]
+}
+`,
+			Expected: `
+build {
+  description = "a"
+  name        = "b"
+
+  provisioner "shell" {
+    environment_vars = ["A=1"]
+    inline           = ["echo"]
+  }
+  sources = ["source.null.example"]
+}
+`,
+		},
+		{
+			Name:     "single line blocks are left as is",
+			Input:    "locals { b = 2 }\nb = 2\na = 1",
+			Expected: "locals { b = 2 }\na = 1\nb = 2",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			file, diags := hclsyntax.ParseConfig([]byte(tc.Input), "test.pkr.hcl", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatalf("failed to parse %s", diags.Error())
+			}
+			out := hclwrite.Format(sortAttributes([]byte(tc.Input), file.Body.(*hclsyntax.Body)))
+			if diff := cmp.Diff(tc.Expected, string(out)); diff != "" {
+				t.Errorf("Unexpected sorted output %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package function
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"io/ioutil"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestBase64Gzip(t *testing.T) {
+	tests := []string{
+		"",
+		"test",
+		"Hello World\n",
+	}
+
+	for _, test := range tests {
+		t.Run(test, func(t *testing.T) {
+			got, err := Base64Gzip(cty.StringVal(test))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			compressed, err := base64.StdEncoding.DecodeString(got.AsString())
+			if err != nil {
+				t.Fatalf("result is not base64: %s", err)
+			}
+			gz, err := gzip.NewReader(bytes.NewReader(compressed))
+			if err != nil {
+				t.Fatalf("result is not gzip: %s", err)
+			}
+			raw, err := ioutil.ReadAll(gz)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if string(raw) != test {
+				t.Errorf("wrong result\ngot:  %q\nwant: %q", raw, test)
+			}
+		})
+	}
+}
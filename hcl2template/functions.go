@@ -34,6 +34,7 @@ func Functions(basedir string) map[string]function.Function {
 		"basename":           filesystem.BasenameFunc,
 		"base64decode":       encoding.Base64DecodeFunc,
 		"base64encode":       encoding.Base64EncodeFunc,
+		"base64gzip":         pkrfunction.Base64GzipFunc,
 		"bcrypt":             crypto.BcryptFunc,
 		"can":                tryfunc.CanFunc,
 		"ceil":               stdlib.CeilFunc,
@@ -102,6 +103,12 @@ func Functions(basedir string) map[string]function.Function {
 		"timestamp":          pkrfunction.TimestampFunc,
 		"timeadd":            stdlib.TimeAddFunc,
 		"title":              stdlib.TitleFunc,
+		"tobool":             stdlib.MakeToFunc(cty.Bool),
+		"tolist":             stdlib.MakeToFunc(cty.List(cty.DynamicPseudoType)),
+		"tomap":              stdlib.MakeToFunc(cty.Map(cty.DynamicPseudoType)),
+		"tonumber":           stdlib.MakeToFunc(cty.Number),
+		"toset":              stdlib.MakeToFunc(cty.Set(cty.DynamicPseudoType)),
+		"tostring":           stdlib.MakeToFunc(cty.String),
 		"trim":               stdlib.TrimFunc,
 		"trimprefix":         stdlib.TrimPrefixFunc,
 		"trimspace":          stdlib.TrimSpaceFunc,
@@ -7,7 +7,6 @@ import (
 
 	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/hcl/v2"
-	"github.com/hashicorp/hcl/v2/ext/dynblock"
 	"github.com/hashicorp/hcl/v2/hclparse"
 	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
 	"github.com/hashicorp/packer/packer"
@@ -290,16 +289,20 @@ func filterVarsFromLogs(inputOrLocal Variables) {
 		if !variable.Sensitive {
 			continue
 		}
-		value := variable.Value()
-		_ = cty.Walk(value, func(_ cty.Path, nested cty.Value) (bool, error) {
-			if nested.IsWhollyKnown() && !nested.IsNull() && nested.Type().Equals(cty.String) {
-				packersdk.LogSecretFilter.Set(nested.AsString())
-			}
-			return true, nil
-		})
+		filterValueFromLogs(variable.Value())
 	}
 }
 
+// filterValueFromLogs hides the strings of value from the logs.
+func filterValueFromLogs(value cty.Value) {
+	_ = cty.Walk(value, func(_ cty.Path, nested cty.Value) (bool, error) {
+		if nested.IsWhollyKnown() && !nested.IsNull() && nested.Type().Equals(cty.String) {
+			packersdk.LogSecretFilter.Set(nested.AsString())
+		}
+		return true, nil
+	})
+}
+
 func (cfg *PackerConfig) Initialize(opts packer.InitializeOptions) hcl.Diagnostics {
 	var diags hcl.Diagnostics
 
@@ -324,6 +327,7 @@ func (cfg *PackerConfig) Initialize(opts packer.InitializeOptions) hcl.Diagnosti
 	for _, file := range cfg.files {
 		diags = append(diags, cfg.parser.parseConfig(file, cfg)...)
 	}
+	diags = append(diags, cfg.Builds.validateDependencies()...)
 
 	diags = append(diags, cfg.initializeBlocks()...)
 
@@ -336,7 +340,6 @@ func (p *Parser) parseConfig(f *hcl.File, cfg *PackerConfig) hcl.Diagnostics {
 	var diags hcl.Diagnostics
 
 	body := f.Body
-	body = dynblock.Expand(body, cfg.EvalContext(DatasourceContext, nil))
 	content, moreDiags := body.Content(configSchema)
 	diags = append(diags, moreDiags...)
 
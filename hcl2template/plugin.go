@@ -4,6 +4,7 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"log"
+	"path/filepath"
 	"runtime"
 
 	"github.com/hashicorp/hcl/v2"
@@ -74,7 +75,34 @@ func (cfg *PackerConfig) detectPluginBinaries() hcl.Diagnostics {
 		return diags
 	}
 
+	// When packer init locked the versions of the plugins, only these
+	// versions are used.
+	lockFilePath := filepath.Join(cfg.Basedir, plugingetter.LockFileName)
+	lock, err := plugingetter.ReadLockFile(lockFilePath)
+	if err != nil {
+		return append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Failed to read the plugins lock file",
+			Detail:   err.Error(),
+		})
+	}
+
 	for _, pluginRequirement := range pluginReqs {
+		if _, locked := lock.Plugins[pluginRequirement.Identifier.String()]; locked {
+			lockedRequirement := lock.Locked(pluginRequirement)
+			if lockedRequirement == nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  fmt.Sprintf("Locked version of %s doesn't match %s", pluginRequirement.Identifier, pluginRequirement.VersionConstraints.String()),
+					Detail: fmt.Sprintf("The version of the plugin locked in %s doesn't match the "+
+						"version constraints of the configuration anymore. Run packer init to "+
+						"select a new version.", lockFilePath),
+				})
+				continue
+			}
+			pluginRequirement = lockedRequirement
+		}
+
 		sortedInstalls, err := pluginRequirement.ListInstallations(opts)
 		if err != nil {
 			diags = append(diags, &hcl.Diagnostic{
@@ -94,6 +122,15 @@ func (cfg *PackerConfig) detectPluginBinaries() hcl.Diagnostics {
 		}
 		log.Printf("[TRACE] Found the following %q installations: %v", pluginRequirement.Identifier, sortedInstalls)
 		install := sortedInstalls[len(sortedInstalls)-1]
+		if err := lock.CheckInstallation(pluginRequirement, install, opts.Checksummers[0]); err != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("Plugin %s doesn't match the lock file", pluginRequirement.Identifier),
+				Detail: fmt.Sprintf("%s. Run packer init to install the locked version, or "+
+					"packer init -upgrade to lock the installed one.", err),
+			})
+			continue
+		}
 		err = cfg.parser.PluginConfig.DiscoverMultiPlugin(pluginRequirement.Accessor, install.BinaryPath)
 		if err != nil {
 			diags = append(diags, &hcl.Diagnostic{
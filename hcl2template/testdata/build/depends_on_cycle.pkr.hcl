@@ -0,0 +1,14 @@
+build {
+    name       = "base"
+    depends_on = ["app"]
+    sources    = ["source.virtualbox-iso.ubuntu-1204"]
+}
+
+build {
+    name       = "app"
+    depends_on = ["base"]
+    sources    = ["source.virtualbox-iso.ubuntu-1204"]
+}
+
+source "virtualbox-iso" "ubuntu-1204" {
+}
@@ -0,0 +1,8 @@
+build {
+    name       = "app"
+    depends_on = ["base"]
+    sources    = ["source.virtualbox-iso.ubuntu-1204"]
+}
+
+source "virtualbox-iso" "ubuntu-1204" {
+}
@@ -0,0 +1,39 @@
+
+// starts resources to provision them.
+build {
+    sources = [
+        "source.virtualbox-iso.ubuntu-1204"
+    ]
+
+    dynamic "provisioner" {
+        for_each = ["shell", "file"]
+        labels   = [provisioner.value]
+        content {
+            string = "provisioner ${provisioner.key}"
+        }
+    }
+
+    provisioner "shell" {
+        dynamic "tag" {
+            for_each = [source.name]
+            content {
+                key   = "source"
+                value = tag.value
+            }
+        }
+    }
+
+    post-processors {
+        dynamic "post-processor" {
+            for_each = ["amazon-import"]
+            iterator = pp
+            labels   = [pp.value]
+            content {
+                string = "post-processor ${pp.key}"
+            }
+        }
+    }
+}
+
+source "virtualbox-iso" "ubuntu-1204" {
+}
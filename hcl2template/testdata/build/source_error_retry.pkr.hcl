@@ -0,0 +1,14 @@
+build {
+    source "virtualbox-iso.ubuntu-1204" {
+        string = "retried"
+
+        error_retry {
+            max_attempts     = 3
+            backoff          = "1m"
+            retryable_errors = ["(?i)timeout"]
+        }
+    }
+}
+
+source "virtualbox-iso" "ubuntu-1204" {
+}
@@ -0,0 +1,18 @@
+
+build {
+    source "virtualbox-iso.ubuntu-1204" {
+        for_each = {
+            focal = "20.04"
+            jammy = "22.04"
+        }
+        name   = "ubuntu-${each.key}"
+        string = each.value
+    }
+
+    provisioner "shell" {
+        string = "${source.name} ${each.value}"
+    }
+}
+
+source "virtualbox-iso" "ubuntu-1204" {
+}
@@ -0,0 +1,10 @@
+
+build {
+    source "virtualbox-iso.ubuntu-1204" {
+        for_each = ["20.04", "22.04"]
+        string   = each.value
+    }
+}
+
+source "virtualbox-iso" "ubuntu-1204" {
+}
@@ -0,0 +1,9 @@
+build {
+    source "virtualbox-iso.ubuntu-1204" {
+        timeout = "1h30m"
+        string  = "timed"
+    }
+}
+
+source "virtualbox-iso" "ubuntu-1204" {
+}
@@ -0,0 +1,8 @@
+build {
+    source "virtualbox-iso.ubuntu-1204" {
+        timeout = "soon"
+    }
+}
+
+source "virtualbox-iso" "ubuntu-1204" {
+}
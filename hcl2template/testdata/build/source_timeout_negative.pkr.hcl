@@ -0,0 +1,8 @@
+build {
+    source "virtualbox-iso.ubuntu-1204" {
+        timeout = "-1h"
+    }
+}
+
+source "virtualbox-iso" "ubuntu-1204" {
+}
@@ -2,6 +2,7 @@ package hcl2template
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/gohcl"
@@ -56,6 +57,10 @@ type BuildBlock struct {
 	// call for example.
 	Description string
 
+	// DependsOn are the names of the builds that must succeed before this
+	// build starts.
+	DependsOn []string
+
 	// Sources is the list of sources that we want to start in this build block.
 	Sources []SourceUseBlock
 
@@ -87,6 +92,7 @@ func (p *Parser) decodeBuildConfig(block *hcl.Block, cfg *PackerConfig) (*BuildB
 		Name        string   `hcl:"name,optional"`
 		Description string   `hcl:"description,optional"`
 		FromSources []string `hcl:"sources,optional"`
+		DependsOn   []string `hcl:"depends_on,optional"`
 		Config      hcl.Body `hcl:",remain"`
 	}
 	diags := gohcl.DecodeBody(body, nil, &b)
@@ -96,6 +102,8 @@ func (p *Parser) decodeBuildConfig(block *hcl.Block, cfg *PackerConfig) (*BuildB
 
 	build.Name = b.Name
 	build.Description = b.Description
+	build.DependsOn = b.DependsOn
+	build.HCL2Ref = newHCL2Ref(block, b.Config)
 
 	for _, buildFrom := range b.FromSources {
 		ref := sourceRefFromString(buildFrom)
@@ -120,7 +128,7 @@ func (p *Parser) decodeBuildConfig(block *hcl.Block, cfg *PackerConfig) (*BuildB
 	}
 
 	body = b.Config
-	content, moreDiags := body.Content(buildSchema)
+	content, moreDiags := expandDynamicBlocks(body, buildSchema, cfg.EvalContext(BuildContext, nil))
 	diags = append(diags, moreDiags...)
 	if diags.HasErrors() {
 		return nil, diags
@@ -128,12 +136,12 @@ func (p *Parser) decodeBuildConfig(block *hcl.Block, cfg *PackerConfig) (*BuildB
 	for _, block := range content.Blocks {
 		switch block.Type {
 		case sourceLabel:
-			ref, moreDiags := p.decodeBuildSource(block)
+			refs, moreDiags := p.decodeBuildSource(block, cfg.EvalContext(BuildContext, nil))
 			diags = append(diags, moreDiags...)
 			if moreDiags.HasErrors() {
 				continue
 			}
-			build.Sources = append(build.Sources, ref)
+			build.Sources = append(build.Sources, refs...)
 		case buildProvisionerLabel:
 			p, moreDiags := p.decodeProvisioner(block, cfg)
 			diags = append(diags, moreDiags...)
@@ -165,7 +173,7 @@ func (p *Parser) decodeBuildConfig(block *hcl.Block, cfg *PackerConfig) (*BuildB
 			build.PostProcessorsLists = append(build.PostProcessorsLists, []*PostProcessorBlock{pp})
 		case buildPostProcessorsLabel:
 
-			content, moreDiags := block.Body.Content(postProcessorsSchema)
+			content, moreDiags := expandDynamicBlocks(block.Body, postProcessorsSchema, cfg.EvalContext(BuildContext, nil))
 			diags = append(diags, moreDiags...)
 			if moreDiags.HasErrors() {
 				continue
@@ -190,3 +198,66 @@ func (p *Parser) decodeBuildConfig(block *hcl.Block, cfg *PackerConfig) (*BuildB
 
 	return build, diags
 }
+
+// validateDependencies checks that builds only depend on existing builds, and
+// that their dependencies have no cycles.
+func (builds Builds) validateDependencies() hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	byName := map[string]*BuildBlock{}
+	for _, build := range builds {
+		if build.Name != "" {
+			byName[build.Name] = build
+		}
+	}
+	for _, build := range builds {
+		for _, dep := range build.DependsOn {
+			if _, found := byName[dep]; !found {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Unknown build dependency",
+					Detail:   fmt.Sprintf("The build %q depends on %q, which is not the name of a build.", build.Name, dep),
+					Subject:  build.HCL2Ref.DefRange.Ptr(),
+				})
+			}
+		}
+	}
+	if diags.HasErrors() {
+		return diags
+	}
+
+	// Depth-first search of the dependencies, a build that is visited again
+	// while its dependencies are visited is part of a cycle.
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := map[string]int{}
+	var visit func(build *BuildBlock, path []string) *hcl.Diagnostic
+	visit = func(build *BuildBlock, path []string) *hcl.Diagnostic {
+		switch state[build.Name] {
+		case visiting:
+			return &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Cycle in build dependencies",
+				Detail:   fmt.Sprintf("The builds depend on each other: %s.", strings.Join(append(path, build.Name), " -> ")),
+				Subject:  build.HCL2Ref.DefRange.Ptr(),
+			}
+		case visited:
+			return nil
+		}
+		state[build.Name] = visiting
+		for _, dep := range build.DependsOn {
+			if diag := visit(byName[dep], append(path, build.Name)); diag != nil {
+				return diag
+			}
+		}
+		state[build.Name] = visited
+		return nil
+	}
+	for _, build := range builds {
+		if diag := visit(build, nil); diag != nil {
+			return append(diags, diag)
+		}
+	}
+	return diags
+}
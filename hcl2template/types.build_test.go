@@ -2,11 +2,14 @@ package hcl2template
 
 import (
 	"path/filepath"
+	"regexp"
 	"testing"
+	"time"
 
 	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
 	. "github.com/hashicorp/packer/hcl2template/internal"
 	"github.com/hashicorp/packer/packer"
+	"github.com/zclconf/go-cty/cty"
 )
 
 func TestParse_build(t *testing.T) {
@@ -421,6 +424,401 @@ func TestParse_build(t *testing.T) {
 			},
 			false,
 		},
+		{"dynamic provisioners and post-processors",
+			defaultParser,
+			parseTestArgs{"testdata/build/dynamic.pkr.hcl", nil, nil},
+			&PackerConfig{
+				CorePackerVersionString: lockedVersion,
+				Basedir:                 filepath.Join("testdata", "build"),
+				Sources: map[SourceRef]SourceBlock{
+					refVBIsoUbuntu1204: {Type: "virtualbox-iso", Name: "ubuntu-1204"},
+				},
+				Builds: Builds{
+					&BuildBlock{
+						Sources: []SourceUseBlock{
+							{
+								SourceRef: refVBIsoUbuntu1204,
+							},
+						},
+						ProvisionerBlocks: []*ProvisionerBlock{
+							{
+								PType: "shell",
+							},
+							{
+								PType: "file",
+							},
+							{
+								PType: "shell",
+							},
+						},
+						PostProcessorsLists: [][]*PostProcessorBlock{
+							{
+								{
+									PType: "amazon-import",
+								},
+							},
+						},
+					},
+				},
+			},
+			false, false,
+			[]packersdk.Build{
+				&packer.CoreBuild{
+					Type:     "virtualbox-iso.ubuntu-1204",
+					Prepared: true,
+					Builder:  emptyMockBuilder,
+					Provisioners: []packer.CoreBuildProvisioner{
+						{
+							PType: "shell",
+							Provisioner: &HCL2Provisioner{
+								Provisioner: &MockProvisioner{
+									Config: MockConfig{
+										NestedMockConfig: NestedMockConfig{
+											String: "provisioner 0",
+											Tags:   []MockTag{},
+										},
+										NestedSlice: []NestedMockConfig{},
+									},
+								},
+							},
+						},
+						{
+							PType: "file",
+							Provisioner: &HCL2Provisioner{
+								Provisioner: &MockProvisioner{
+									Config: MockConfig{
+										NestedMockConfig: NestedMockConfig{
+											String: "provisioner 1",
+											Tags:   []MockTag{},
+										},
+										NestedSlice: []NestedMockConfig{},
+									},
+								},
+							},
+						},
+						{
+							PType: "shell",
+							Provisioner: &HCL2Provisioner{
+								Provisioner: &MockProvisioner{
+									Config: MockConfig{
+										NestedMockConfig: NestedMockConfig{
+											Tags: []MockTag{
+												{Key: "source", Value: "ubuntu-1204"},
+											},
+										},
+										NestedSlice: []NestedMockConfig{},
+									},
+								},
+							},
+						},
+					},
+					PostProcessors: [][]packer.CoreBuildPostProcessor{
+						{
+							{
+								PType: "amazon-import",
+								PostProcessor: &HCL2PostProcessor{
+									PostProcessor: &MockPostProcessor{
+										Config: MockConfig{
+											NestedMockConfig: NestedMockConfig{
+												String: "post-processor 0",
+												Tags:   []MockTag{},
+											},
+											NestedSlice: []NestedMockConfig{},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{"source with for_each",
+			defaultParser,
+			parseTestArgs{"testdata/build/source_for_each.pkr.hcl", nil, nil},
+			&PackerConfig{
+				CorePackerVersionString: lockedVersion,
+				Basedir:                 filepath.Join("testdata", "build"),
+				Sources: map[SourceRef]SourceBlock{
+					refVBIsoUbuntu1204: {Type: "virtualbox-iso", Name: "ubuntu-1204"},
+				},
+				Builds: Builds{
+					&BuildBlock{
+						Sources: []SourceUseBlock{
+							{
+								SourceRef: refVBIsoUbuntu1204,
+								LocalName: "ubuntu-focal",
+								Each: map[string]cty.Value{
+									"key":   cty.StringVal("focal"),
+									"value": cty.StringVal("20.04"),
+								},
+							},
+							{
+								SourceRef: refVBIsoUbuntu1204,
+								LocalName: "ubuntu-jammy",
+								Each: map[string]cty.Value{
+									"key":   cty.StringVal("jammy"),
+									"value": cty.StringVal("22.04"),
+								},
+							},
+						},
+						ProvisionerBlocks: []*ProvisionerBlock{
+							{
+								PType: "shell",
+							},
+						},
+					},
+				},
+			},
+			false, false,
+			[]packersdk.Build{
+				&packer.CoreBuild{
+					Type:     "virtualbox-iso.ubuntu-focal",
+					Prepared: true,
+					Builder: &MockBuilder{
+						Config: MockConfig{
+							NestedMockConfig: NestedMockConfig{
+								String: "20.04",
+								Tags:   []MockTag{},
+							},
+							Nested:      NestedMockConfig{},
+							NestedSlice: []NestedMockConfig{},
+						},
+					},
+					Provisioners: []packer.CoreBuildProvisioner{
+						{
+							PType: "shell",
+							Provisioner: &HCL2Provisioner{
+								Provisioner: &MockProvisioner{
+									Config: MockConfig{
+										NestedMockConfig: NestedMockConfig{
+											String: "ubuntu-focal 20.04",
+											Tags:   []MockTag{},
+										},
+										NestedSlice: []NestedMockConfig{},
+									},
+								},
+							},
+						},
+					},
+					PostProcessors: [][]packer.CoreBuildPostProcessor{},
+				},
+				&packer.CoreBuild{
+					Type:     "virtualbox-iso.ubuntu-jammy",
+					Prepared: true,
+					Builder: &MockBuilder{
+						Config: MockConfig{
+							NestedMockConfig: NestedMockConfig{
+								String: "22.04",
+								Tags:   []MockTag{},
+							},
+							Nested:      NestedMockConfig{},
+							NestedSlice: []NestedMockConfig{},
+						},
+					},
+					Provisioners: []packer.CoreBuildProvisioner{
+						{
+							PType: "shell",
+							Provisioner: &HCL2Provisioner{
+								Provisioner: &MockProvisioner{
+									Config: MockConfig{
+										NestedMockConfig: NestedMockConfig{
+											String: "ubuntu-jammy 22.04",
+											Tags:   []MockTag{},
+										},
+										NestedSlice: []NestedMockConfig{},
+									},
+								},
+							},
+						},
+					},
+					PostProcessors: [][]packer.CoreBuildPostProcessor{},
+				},
+			},
+			false,
+		},
+		{"source with for_each and the same name",
+			defaultParser,
+			parseTestArgs{"testdata/build/source_for_each_duplicate.pkr.hcl", nil, nil},
+			&PackerConfig{
+				CorePackerVersionString: lockedVersion,
+				Basedir:                 filepath.Join("testdata", "build"),
+				Sources: map[SourceRef]SourceBlock{
+					refVBIsoUbuntu1204: {Type: "virtualbox-iso", Name: "ubuntu-1204"},
+				},
+			},
+			true, true,
+			nil,
+			false,
+		},
+		{"source with timeout",
+			defaultParser,
+			parseTestArgs{"testdata/build/source_timeout.pkr.hcl", nil, nil},
+			&PackerConfig{
+				CorePackerVersionString: lockedVersion,
+				Basedir:                 filepath.Join("testdata", "build"),
+				Sources: map[SourceRef]SourceBlock{
+					refVBIsoUbuntu1204: {Type: "virtualbox-iso", Name: "ubuntu-1204"},
+				},
+				Builds: Builds{
+					&BuildBlock{
+						Sources: []SourceUseBlock{
+							{
+								SourceRef: refVBIsoUbuntu1204,
+								Timeout:   90 * time.Minute,
+							},
+						},
+					},
+				},
+			},
+			false, false,
+			[]packersdk.Build{
+				&packer.CoreBuild{
+					Type:     "virtualbox-iso.ubuntu-1204",
+					Prepared: true,
+					Timeout:  90 * time.Minute,
+					Builder: &MockBuilder{
+						Config: MockConfig{
+							NestedMockConfig: NestedMockConfig{
+								String: "timed",
+								Tags:   []MockTag{},
+							},
+							Nested:      NestedMockConfig{},
+							NestedSlice: []NestedMockConfig{},
+						},
+					},
+					Provisioners:   []packer.CoreBuildProvisioner{},
+					PostProcessors: [][]packer.CoreBuildPostProcessor{},
+				},
+			},
+			false,
+		},
+		{"source with error_retry",
+			defaultParser,
+			parseTestArgs{"testdata/build/source_error_retry.pkr.hcl", nil, nil},
+			&PackerConfig{
+				CorePackerVersionString: lockedVersion,
+				Basedir:                 filepath.Join("testdata", "build"),
+				Sources: map[SourceRef]SourceBlock{
+					refVBIsoUbuntu1204: {Type: "virtualbox-iso", Name: "ubuntu-1204"},
+				},
+				Builds: Builds{
+					&BuildBlock{
+						Sources: []SourceUseBlock{
+							{
+								SourceRef: refVBIsoUbuntu1204,
+								ErrorRetry: &packer.BuildRetry{
+									MaxAttempts:     3,
+									Backoff:         time.Minute,
+									RetryableErrors: []*regexp.Regexp{regexp.MustCompile("(?i)timeout")},
+								},
+							},
+						},
+					},
+				},
+			},
+			false, false,
+			[]packersdk.Build{
+				&packer.CoreBuild{
+					Type:     "virtualbox-iso.ubuntu-1204",
+					Prepared: true,
+					Retry: packer.BuildRetry{
+						MaxAttempts:     3,
+						Backoff:         time.Minute,
+						RetryableErrors: []*regexp.Regexp{regexp.MustCompile("(?i)timeout")},
+					},
+					Builder: &MockBuilder{
+						Config: MockConfig{
+							NestedMockConfig: NestedMockConfig{
+								String: "retried",
+								Tags:   []MockTag{},
+							},
+							Nested:      NestedMockConfig{},
+							NestedSlice: []NestedMockConfig{},
+						},
+					},
+					Provisioners:   []packer.CoreBuildProvisioner{},
+					PostProcessors: [][]packer.CoreBuildPostProcessor{},
+				},
+			},
+			false,
+		},
+		{"build depending on an unknown build",
+			defaultParser,
+			parseTestArgs{"testdata/build/depends_on_unknown.pkr.hcl", nil, nil},
+			&PackerConfig{
+				CorePackerVersionString: lockedVersion,
+				Basedir:                 filepath.Join("testdata", "build"),
+				Sources: map[SourceRef]SourceBlock{
+					refVBIsoUbuntu1204: {Type: "virtualbox-iso", Name: "ubuntu-1204"},
+				},
+				Builds: Builds{
+					&BuildBlock{
+						Name:      "app",
+						DependsOn: []string{"base"},
+						Sources:   []SourceUseBlock{{SourceRef: refVBIsoUbuntu1204}},
+					},
+				},
+			},
+			true, true,
+			nil,
+			false,
+		},
+		{"builds depending on each other",
+			defaultParser,
+			parseTestArgs{"testdata/build/depends_on_cycle.pkr.hcl", nil, nil},
+			&PackerConfig{
+				CorePackerVersionString: lockedVersion,
+				Basedir:                 filepath.Join("testdata", "build"),
+				Sources: map[SourceRef]SourceBlock{
+					refVBIsoUbuntu1204: {Type: "virtualbox-iso", Name: "ubuntu-1204"},
+				},
+				Builds: Builds{
+					&BuildBlock{
+						Name:      "base",
+						DependsOn: []string{"app"},
+						Sources:   []SourceUseBlock{{SourceRef: refVBIsoUbuntu1204}},
+					},
+					&BuildBlock{
+						Name:      "app",
+						DependsOn: []string{"base"},
+						Sources:   []SourceUseBlock{{SourceRef: refVBIsoUbuntu1204}},
+					},
+				},
+			},
+			true, true,
+			nil,
+			false,
+		},
+		{"source with an invalid timeout",
+			defaultParser,
+			parseTestArgs{"testdata/build/source_timeout_invalid.pkr.hcl", nil, nil},
+			&PackerConfig{
+				CorePackerVersionString: lockedVersion,
+				Basedir:                 filepath.Join("testdata", "build"),
+				Sources: map[SourceRef]SourceBlock{
+					refVBIsoUbuntu1204: {Type: "virtualbox-iso", Name: "ubuntu-1204"},
+				},
+			},
+			true, true,
+			nil,
+			false,
+		},
+		{"source with a negative timeout",
+			defaultParser,
+			parseTestArgs{"testdata/build/source_timeout_negative.pkr.hcl", nil, nil},
+			&PackerConfig{
+				CorePackerVersionString: lockedVersion,
+				Basedir:                 filepath.Join("testdata", "build"),
+				Sources: map[SourceRef]SourceBlock{
+					refVBIsoUbuntu1204: {Type: "virtualbox-iso", Name: "ubuntu-1204"},
+				},
+			},
+			true, true,
+			nil,
+			false,
+		},
 	}
 	testParse(t, tests)
 }
@@ -62,6 +62,25 @@ func (ds *Datasources) Values() (map[string]cty.Value, hcl.Diagnostics) {
 	return res, diags
 }
 
+// sensitiveDatasources are the types of the datasources whose outputs are
+// secrets. Their values are hidden from the logs of Packer, and not only of
+// the plugin reading them, and the locals computed from them are sensitive.
+var sensitiveDatasources = map[string]bool{
+	"vault": true,
+}
+
+// markedValues returns the values of the datasources, where the values of
+// sensitive datasources are marked as sensitive.
+func (ds *Datasources) markedValues() (map[string]cty.Value, hcl.Diagnostics) {
+	res, diags := ds.Values()
+	for dsType, value := range res {
+		if sensitiveDatasources[dsType] {
+			res[dsType] = value.Mark(sensitiveMark)
+		}
+	}
+	return res, diags
+}
+
 func (cfg *PackerConfig) startDatasource(dataSourceStore packer.DatasourceStore, ref DatasourceRef) (packersdk.Datasource, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
 	block := cfg.Datasources[ref].block
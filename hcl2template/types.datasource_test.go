@@ -4,7 +4,10 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+	"github.com/zclconf/go-cty/cty"
 )
 
 func TestParse_datasource(t *testing.T) {
@@ -123,3 +126,31 @@ func TestParse_datasource(t *testing.T) {
 	}
 	testParse(t, tests)
 }
+
+func TestEvaluateLocalVariables_sensitiveDatasource(t *testing.T) {
+	expr, diags := hclsyntax.ParseExpression([]byte(`data.vault.secret.value`), "locals.pkr.hcl", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	cfg := &PackerConfig{
+		Datasources: Datasources{
+			{Type: "vault", Name: "secret"}: {
+				Type:  "vault",
+				Name:  "secret",
+				value: cty.ObjectVal(map[string]cty.Value{"value": cty.StringVal("s3cr3t")}),
+			},
+		},
+	}
+
+	diags = cfg.evaluateLocalVariables([]*LocalBlock{{Name: "password", Expr: expr}})
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	local := cfg.LocalVariables["password"]
+	if !local.Sensitive {
+		t.Fatal("a local computed from a vault datasource should be sensitive")
+	}
+	if local.Value().ContainsMarked() {
+		t.Fatal("the value of the local should not be marked")
+	}
+}
@@ -1,6 +1,7 @@
 package hcl2template
 
 import (
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
@@ -14,6 +15,7 @@ import (
 	"github.com/hashicorp/packer/packer"
 	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/function"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
 )
 
 // PackerConfig represents a loaded Packer HCL config. It will contain
@@ -75,6 +77,7 @@ const (
 	buildAccessor          = "build"
 	packerAccessor         = "packer"
 	dataAccessor           = "data"
+	eachAccessor           = "each"
 )
 
 type BlockContext int
@@ -93,6 +96,13 @@ const (
 func (cfg *PackerConfig) EvalContext(ctx BlockContext, variables map[string]cty.Value) *hcl.EvalContext {
 	inputVariables := cfg.InputVariables.Values()
 	localVariables := cfg.LocalVariables.Values()
+	switch ctx {
+	case LocalContext, NilContext:
+		// The values computed from sensitive variables in locals or in the
+		// console are marked, to hide them as well.
+		inputVariables = cfg.InputVariables.markedValues()
+		localVariables = cfg.LocalVariables.markedValues()
+	}
 	ectx := &hcl.EvalContext{
 		Functions: Functions(cfg.Basedir),
 		Variables: map[string]cty.Value{
@@ -124,7 +134,10 @@ func (cfg *PackerConfig) EvalContext(ctx BlockContext, variables map[string]cty.
 	// dependency tree, so that any block can use any block whatever the
 	// order.
 	switch ctx {
-	case LocalContext, BuildContext:
+	case LocalContext, NilContext:
+		datasourceVariables, _ := cfg.Datasources.markedValues()
+		ectx.Variables[dataAccessor] = cty.ObjectVal(datasourceVariables)
+	case BuildContext:
 		datasourceVariables, _ := cfg.Datasources.Values()
 		ectx.Variables[dataAccessor] = cty.ObjectVal(datasourceVariables)
 	}
@@ -263,9 +276,16 @@ func (c *PackerConfig) evaluateLocalVariable(local *LocalBlock) hcl.Diagnostics
 	if moreDiags.HasErrors() {
 		return diags
 	}
+	// A local computed from a sensitive variable, local or datasource is
+	// sensitive too.
+	sensitive := local.Sensitive
+	if value.ContainsMarked() {
+		sensitive = true
+		value, _ = value.UnmarkDeep()
+	}
 	c.LocalVariables[local.Name] = &Variable{
 		Name:      local.Name,
-		Sensitive: local.Sensitive,
+		Sensitive: sensitive,
 		Values: []VariableAssignment{{
 			Value: value,
 			Expr:  local.Expr,
@@ -307,6 +327,9 @@ func (cfg *PackerConfig) evaluateDatasources(skipExecution bool) hcl.Diagnostics
 			})
 			continue
 		}
+		if sensitiveDatasources[ref.Type] {
+			filterValueFromLogs(realValue)
+		}
 		ds.value = realValue
 		cfg.Datasources[ref] = ds
 	}
@@ -443,6 +466,15 @@ func (cfg *PackerConfig) GetBuilds(opts packer.GetBuildsOptions) ([]packersdk.Bu
 			pcb := &packer.CoreBuild{
 				BuildName: build.Name,
 				Type:      srcUsage.String(),
+				Timeout:   opts.Timeout,
+				Retry:     opts.Retry(),
+				DependsOn: build.DependsOn,
+			}
+			if srcUsage.Timeout > 0 {
+				pcb.Timeout = srcUsage.Timeout
+			}
+			if srcUsage.ErrorRetry != nil {
+				pcb.Retry = *srcUsage.ErrorRetry
 			}
 
 			// Apply the -only and -except command-line options to exclude matching builds.
@@ -485,7 +517,7 @@ func (cfg *PackerConfig) GetBuilds(opts packer.GetBuildsOptions) ([]packersdk.Bu
 				}
 			}
 
-			builder, moreDiags, generatedVars := cfg.startBuilder(srcUsage, cfg.EvalContext(BuildContext, nil))
+			builder, moreDiags, generatedVars := cfg.startBuilder(srcUsage, cfg.EvalContext(BuildContext, srcUsage.eachVariables()))
 			diags = append(diags, moreDiags...)
 			if moreDiags.HasErrors() {
 				continue
@@ -506,6 +538,9 @@ func (cfg *PackerConfig) GetBuilds(opts packer.GetBuildsOptions) ([]packersdk.Bu
 				sourcesAccessor: cty.ObjectVal(srcUsage.ctyValues()),
 				buildAccessor:   cty.ObjectVal(unknownBuildValues),
 			}
+			for k, v := range srcUsage.eachVariables() {
+				variables[k] = v
+			}
 
 			provisioners, moreDiags := cfg.getCoreBuildProvisioners(srcUsage, build.ProvisionerBlocks, cfg.EvalContext(BuildContext, variables))
 			diags = append(diags, moreDiags...)
@@ -556,10 +591,11 @@ func (cfg *PackerConfig) GetBuilds(opts packer.GetBuildsOptions) ([]packersdk.Bu
 var PackerConsoleHelp = strings.TrimSpace(`
 Packer console HCL2 Mode.
 The Packer console allows you to experiment with Packer interpolations.
-You may access variables and functions in the Packer config you called the
-console with.
+You may access variables, locals, data sources and functions in the Packer
+config you called the console with.
 
-Type in the interpolation to test and hit <enter> to see the result.
+Type in the interpolation to test and hit <enter> to see the result. An
+expression with unclosed brackets or heredocs continues on the next lines.
 
 "upper(var.foo.id)" would evaluate to the ID of "foo" and uppercase is, if it
 exists in your config file.
@@ -670,6 +706,9 @@ func (p *PackerConfig) handleEval(line string) (out string, exit bool, diags hcl
 	if valueDiags.HasErrors() {
 		return "", false, diags
 	}
+	if val.ContainsMarked() {
+		return "<sensitive>", false, diags
+	}
 
 	return PrintableCtyValue(val), false, diags
 }
@@ -682,8 +721,114 @@ func (p *PackerConfig) FixConfig(_ packer.FixConfigOptions) (diags hcl.Diagnosti
 func (p *PackerConfig) InspectConfig(opts packer.InspectConfigOptions) int {
 
 	ui := opts.Ui
+	if opts.JSON {
+		return packer.WriteInspectOutput(ui, p.InspectOutput())
+	}
 	ui.Say("Packer Inspect: HCL2 mode\n")
 	ui.Say(p.printVariables())
 	ui.Say(p.printBuilds())
 	return 0
 }
+
+// InspectOutput returns the evaluated configuration, as written by packer
+// inspect -json. The configurations of the sources are decoded with the specs
+// of their builders; sensitive and unknown values are null.
+func (p *PackerConfig) InspectOutput() packer.InspectOutput {
+	out := packer.InspectOutput{ConfigType: "hcl2"}
+	out.Variables = inspectVariables(p.InputVariables)
+	out.Locals = inspectVariables(p.LocalVariables)
+
+	for _, build := range p.Builds {
+		b := packer.InspectBuild{
+			Name:           build.Name,
+			Description:    build.Description,
+			DependsOn:      build.DependsOn,
+			Sources:        []packer.InspectSource{},
+			Provisioners:   []packer.InspectPlugin{},
+			PostProcessors: [][]packer.InspectPlugin{},
+		}
+		for _, source := range build.Sources {
+			name := source.String()
+			if build.Name != "" {
+				name = build.Name + "." + name
+			}
+			b.Sources = append(b.Sources, packer.InspectSource{
+				Name:   name,
+				Type:   source.Type,
+				Config: p.inspectSourceConfig(source),
+			})
+		}
+		for _, prov := range build.ProvisionerBlocks {
+			b.Provisioners = append(b.Provisioners, packer.InspectPlugin{Type: prov.PType, Name: prov.PName})
+		}
+		for _, ppList := range build.PostProcessorsLists {
+			list := []packer.InspectPlugin{}
+			for _, pp := range ppList {
+				list = append(list, packer.InspectPlugin{Type: pp.PType, Name: pp.PName})
+			}
+			b.PostProcessors = append(b.PostProcessors, list)
+		}
+		out.Builds = append(out.Builds, b)
+	}
+	return out
+}
+
+func inspectVariables(variables Variables) []packer.InspectVariable {
+	keys := variables.Keys()
+	sort.Strings(keys)
+	res := make([]packer.InspectVariable, 0, len(keys))
+	for _, key := range keys {
+		v := variables[key]
+		value := json.RawMessage("null")
+		if !v.Sensitive {
+			value = inspectValue(v.Value())
+		}
+		res = append(res, packer.InspectVariable{
+			Name:        v.Name,
+			Description: v.Description,
+			Value:       value,
+			Sensitive:   v.Sensitive,
+		})
+	}
+	return res
+}
+
+// inspectSourceConfig decodes the configuration of a source with the spec of
+// its builder. It returns null when the builder can't be started or the
+// configuration can't be decoded.
+func (p *PackerConfig) inspectSourceConfig(source SourceUseBlock) json.RawMessage {
+	builder, err := p.parser.PluginConfig.Builders.Start(source.Type)
+	if err != nil {
+		return json.RawMessage("null")
+	}
+	decoded, diags := decodeHCL2Spec(source.Body, p.EvalContext(NilContext, source.eachVariables()), builder)
+	if diags.HasErrors() {
+		return json.RawMessage("null")
+	}
+	return inspectValue(decoded)
+}
+
+// inspectValue returns val as JSON, with its sensitive and unknown parts set
+// to null.
+func inspectValue(val cty.Value) json.RawMessage {
+	val, marks := val.UnmarkDeepWithPaths()
+	val, err := cty.Transform(val, func(path cty.Path, v cty.Value) (cty.Value, error) {
+		if !v.IsKnown() {
+			return cty.NullVal(v.Type()), nil
+		}
+		for _, mark := range marks {
+			if path.Equals(mark.Path) {
+				return cty.NullVal(v.Type()), nil
+			}
+		}
+		return v, nil
+	})
+	if err != nil {
+		return json.RawMessage("null")
+	}
+	data, err := ctyjson.Marshal(val, val.Type())
+	if err != nil {
+		return json.RawMessage("null")
+	}
+	return data
+}
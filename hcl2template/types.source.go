@@ -2,13 +2,16 @@ package hcl2template
 
 import (
 	"fmt"
+	"regexp"
 	"sort"
 	"strconv"
+	"time"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/gohcl"
 	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
 	hcl2shim "github.com/hashicorp/packer/hcl2template/shim"
+	"github.com/hashicorp/packer/packer"
 	"github.com/zclconf/go-cty/cty"
 )
 
@@ -42,6 +45,19 @@ type SourceUseBlock struct {
 	// content
 	// Body can be expanded by a dynamic tag.
 	Body hcl.Body
+
+	// Each is the value of the `each` variable of the source, when the
+	// build.source block has a for_each argument. It holds the key and the
+	// value of the element the source was generated for.
+	Each map[string]cty.Value
+
+	// Timeout is the maximum duration of the build of the source, when the
+	// build.source block has a timeout argument.
+	Timeout time.Duration
+
+	// ErrorRetry is the retry policy of the build of the source, when the
+	// build.source block has an error_retry block.
+	ErrorRetry *packer.BuildRetry
 }
 
 func (b *SourceUseBlock) name() string {
@@ -63,26 +79,246 @@ func (b *SourceUseBlock) ctyValues() map[string]cty.Value {
 	}
 }
 
+// eachVariables returns the `each` variable of the source, if any.
+func (b *SourceUseBlock) eachVariables() map[string]cty.Value {
+	if b.Each == nil {
+		return nil
+	}
+	return map[string]cty.Value{
+		eachAccessor: cty.ObjectVal(b.Each),
+	}
+}
+
 // decodeBuildSource reads a used source block from a build:
 //  build {
 //    source "type.example" {
 //      name = "local_name"
 //    }
 //  }
-func (p *Parser) decodeBuildSource(block *hcl.Block) (SourceUseBlock, hcl.Diagnostics) {
+//
+// With a for_each argument, a source is used for each element of its value,
+// and the `each` variable is set to the key and value of the element:
+//  build {
+//    source "type.example" {
+//      for_each = ["20.04", "22.04"]
+//      name     = "ubuntu-${each.value}"
+//    }
+//  }
+//
+// With a timeout argument, the build of the source is cancelled when it lasts
+// longer than the timeout:
+//  build {
+//    source "type.example" {
+//      timeout = "1h30m"
+//    }
+//  }
+func (p *Parser) decodeBuildSource(block *hcl.Block, ectx *hcl.EvalContext) ([]SourceUseBlock, hcl.Diagnostics) {
 	ref := sourceRefFromString(block.Labels[0])
-	out := SourceUseBlock{SourceRef: ref}
-	var b struct {
-		Name string   `hcl:"name,optional"`
-		Rest hcl.Body `hcl:",remain"`
-	}
+	var b buildSourceArgs
 	diags := gohcl.DecodeBody(block.Body, nil, &b)
 	if diags.HasErrors() {
-		return out, diags
+		return nil, diags
+	}
+
+	forEach, moreDiags := b.ForEach.Value(ectx)
+	diags = append(diags, moreDiags...)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	if forEach.IsNull() {
+		out := SourceUseBlock{SourceRef: ref, Body: b.Rest}
+		diags = append(diags, b.decode(&out, ectx)...)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		return []SourceUseBlock{out}, diags
+	}
+
+	ty := forEach.Type()
+	if !forEach.IsWhollyKnown() || !(ty.IsMapType() || ty.IsObjectType() ||
+		ty.IsListType() || ty.IsTupleType() || ty.IsSetType()) {
+		return nil, append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid for_each argument",
+			Detail:   "The for_each argument of a " + sourceLabel + " must be a known map, set, list or object.",
+			Subject:  b.ForEach.Range().Ptr(),
+		})
+	}
+
+	var sources []SourceUseBlock
+	names := map[string]bool{}
+	for it := forEach.ElementIterator(); it.Next(); {
+		key, value := it.Element()
+		if ty.IsSetType() {
+			key = value
+		}
+		each := map[string]cty.Value{
+			"key":   key,
+			"value": value,
+		}
+
+		out := SourceUseBlock{SourceRef: ref, Body: b.Rest, Each: each}
+		eachCtx := ectx.NewChild()
+		eachCtx.Variables = out.eachVariables()
+		moreDiags = b.decode(&out, eachCtx)
+		diags = append(diags, moreDiags...)
+		if moreDiags.HasErrors() {
+			return nil, diags
+		}
+		if names[out.name()] {
+			return nil, append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate " + sourceLabel + " name",
+				Detail: fmt.Sprintf("The %s %s is used more than once by the for_each argument, "+
+					"its name must use the each variable, for example `name = \"%s-${each.key}\"`.",
+					sourceLabel, out.String(), ref.Name),
+				Subject: b.Name.Range().Ptr(),
+			})
+		}
+		names[out.name()] = true
+		sources = append(sources, out)
+	}
+	return sources, diags
+}
+
+// buildSourceArgs are the arguments of a build.source block that are not
+// passed to the builder.
+type buildSourceArgs struct {
+	Name       hcl.Expression   `hcl:"name,optional"`
+	ForEach    hcl.Expression   `hcl:"for_each,optional"`
+	Timeout    hcl.Expression   `hcl:"timeout,optional"`
+	ErrorRetry *errorRetryBlock `hcl:"error_retry,block"`
+	Rest       hcl.Body         `hcl:",remain"`
+}
+
+// decode sets the fields of a used source from the arguments of its
+// build.source block, evaluated with ectx.
+func (args *buildSourceArgs) decode(out *SourceUseBlock, ectx *hcl.EvalContext) hcl.Diagnostics {
+	var diags, moreDiags hcl.Diagnostics
+	out.LocalName, moreDiags = decodeSourceName(args.Name, ectx)
+	diags = append(diags, moreDiags...)
+	out.Timeout, moreDiags = decodeSourceTimeout(args.Timeout, ectx)
+	diags = append(diags, moreDiags...)
+	if args.ErrorRetry != nil {
+		out.ErrorRetry, moreDiags = args.ErrorRetry.decode(ectx)
+		diags = append(diags, moreDiags...)
+	}
+	return diags
+}
+
+// errorRetryBlock is the error_retry block of a build.source block:
+//  build {
+//    source "type.example" {
+//      error_retry {
+//        max_attempts     = 3
+//        backoff          = "30s"
+//        retryable_errors = ["(?i)timeout"]
+//      }
+//    }
+//  }
+type errorRetryBlock struct {
+	Body hcl.Body `hcl:",remain"`
+}
+
+func (b *errorRetryBlock) decode(ectx *hcl.EvalContext) (*packer.BuildRetry, hcl.Diagnostics) {
+	var config struct {
+		MaxAttempts     int            `hcl:"max_attempts"`
+		Backoff         hcl.Expression `hcl:"backoff,optional"`
+		RetryableErrors []string       `hcl:"retryable_errors,optional"`
+	}
+	diags := gohcl.DecodeBody(b.Body, ectx, &config)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	retry := &packer.BuildRetry{
+		MaxAttempts: config.MaxAttempts,
+		Backoff:     packer.DefaultBuildRetryBackoff,
+	}
+	if config.MaxAttempts < 1 {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid max_attempts",
+			Detail:   "The max_attempts of an error_retry block must be at least 1.",
+			Subject:  b.Body.MissingItemRange().Ptr(),
+		})
+	}
+	backoff, moreDiags := decodeDuration(config.Backoff, ectx, "backoff of an error_retry block")
+	diags = append(diags, moreDiags...)
+	if backoff != nil {
+		retry.Backoff = *backoff
+	}
+	for _, expr := range config.RetryableErrors {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid retryable_errors",
+				Detail:   fmt.Sprintf("%q is not a valid regular expression: %s", expr, err),
+				Subject:  b.Body.MissingItemRange().Ptr(),
+			})
+			continue
+		}
+		retry.RetryableErrors = append(retry.RetryableErrors, re)
+	}
+	return retry, diags
+}
+
+// decodeSourceName returns the name given to a source in a build.source block,
+// or an empty string when it isn't set.
+func decodeSourceName(expr hcl.Expression, ectx *hcl.EvalContext) (string, hcl.Diagnostics) {
+	value, diags := expr.Value(ectx)
+	if diags.HasErrors() || value.IsNull() {
+		return "", diags
+	}
+	var name string
+	diags = append(diags, gohcl.DecodeExpression(expr, ectx, &name)...)
+	return name, diags
+}
+
+// decodeSourceTimeout returns the timeout of a source in a build.source block,
+// or zero when it isn't set.
+func decodeSourceTimeout(expr hcl.Expression, ectx *hcl.EvalContext) (time.Duration, hcl.Diagnostics) {
+	timeout, diags := decodeDuration(expr, ectx, "timeout of a "+sourceLabel)
+	if timeout == nil {
+		return 0, diags
+	}
+	return *timeout, diags
+}
+
+// decodeDuration returns the duration set by expr, or nil when it isn't set or
+// is invalid. what describes the duration in errors.
+func decodeDuration(expr hcl.Expression, ectx *hcl.EvalContext, what string) (*time.Duration, hcl.Diagnostics) {
+	if expr == nil {
+		return nil, nil
+	}
+	value, diags := expr.Value(ectx)
+	if diags.HasErrors() || value.IsNull() {
+		return nil, diags
+	}
+	var s string
+	diags = append(diags, gohcl.DecodeExpression(expr, ectx, &s)...)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return nil, append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid duration",
+			Detail:   fmt.Sprintf("The %s must be a duration, for example \"1h30m\": %s", what, err),
+			Subject:  expr.Range().Ptr(),
+		})
+	}
+	if d < 0 {
+		return nil, append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid duration",
+			Detail:   fmt.Sprintf("The %s must not be negative, got %q", what, s),
+			Subject:  expr.Range().Ptr(),
+		})
 	}
-	out.LocalName = b.Name
-	out.Body = b.Rest
-	return out, nil
+	return &d, diags
 }
 
 func (p *Parser) decodeSource(block *hcl.Block) (SourceBlock, hcl.Diagnostics) {
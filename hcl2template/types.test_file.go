@@ -0,0 +1,340 @@
+package hcl2template
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/packer/packer"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+const (
+	hcl2TestFileExt     = ".pkrtest.hcl"
+	hcl2TestJsonFileExt = ".pkrtest.json"
+
+	runLabel      = "run"
+	mockDataLabel = "mock_data"
+	assertLabel   = "assert"
+)
+
+var testFileSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: runLabel, LabelNames: []string{"name"}},
+	},
+}
+
+var testRunSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "variables"},
+	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: mockDataLabel, LabelNames: []string{"type", "name"}},
+		{Type: assertLabel},
+	},
+}
+
+var testAssertSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "condition", Required: true},
+		{Name: "error_message"},
+	},
+}
+
+// TestRun is a `run` block of a test file. A run evaluates the configuration
+// with its variables and mocked data sources, and checks its assertions.
+//
+//	run "name" {
+//	  variables = {
+//	    region = "eu-west-1"
+//	  }
+//
+//	  mock_data "amazon-ami" "ubuntu" {
+//	    values = {
+//	      id = "ami-0123456789"
+//	    }
+//	  }
+//
+//	  assert {
+//	    condition     = local.ami_name == "ubuntu-eu-west-1"
+//	    error_message = "The AMI name must contain the region."
+//	  }
+//	}
+type TestRun struct {
+	Name      string
+	Variables map[string]cty.Value
+	MockData  map[DatasourceRef]cty.Value
+	Asserts   []*TestAssert
+
+	HCL2Ref HCL2Ref
+}
+
+// TestAssert is an `assert` block of a test run.
+type TestAssert struct {
+	Condition    hcl.Expression
+	ErrorMessage string
+}
+
+// GetTestFiles returns the test files of the configuration in path, which are
+// the files of its directory with the .pkrtest.hcl or .pkrtest.json
+// extensions.
+func GetTestFiles(path string) ([]string, hcl.Diagnostics) {
+	if isDir, err := isDir(path); err == nil && !isDir {
+		path = filepath.Dir(path)
+	}
+	hclFiles, jsonFiles, diags := GetHCL2Files(path, hcl2TestFileExt, hcl2TestJsonFileExt)
+	return append(hclFiles, jsonFiles...), diags
+}
+
+// ParseTestFile reads the runs of a test file with parser, which keeps the file
+// to write the diagnostics of its runs.
+func ParseTestFile(parser *hclparse.Parser, filename string) ([]*TestRun, hcl.Diagnostics) {
+	var f *hcl.File
+	var diags hcl.Diagnostics
+	if strings.HasSuffix(filename, hcl2TestJsonFileExt) {
+		f, diags = parser.ParseJSONFile(filename)
+	} else {
+		f, diags = parser.ParseHCLFile(filename)
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	content, moreDiags := f.Body.Content(testFileSchema)
+	diags = append(diags, moreDiags...)
+
+	var runs []*TestRun
+	for _, block := range content.Blocks {
+		run, moreDiags := decodeTestRun(block)
+		diags = append(diags, moreDiags...)
+		if moreDiags.HasErrors() {
+			continue
+		}
+		runs = append(runs, run)
+	}
+	return runs, diags
+}
+
+func decodeTestRun(block *hcl.Block) (*TestRun, hcl.Diagnostics) {
+	run := &TestRun{
+		Name:     block.Labels[0],
+		MockData: map[DatasourceRef]cty.Value{},
+		HCL2Ref:  newHCL2Ref(block, block.Body),
+	}
+	content, diags := block.Body.Content(testRunSchema)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	// Test files have no variables of their own, their values can use
+	// functions.
+	ectx := &hcl.EvalContext{Functions: Functions("")}
+
+	if attr, ok := content.Attributes["variables"]; ok {
+		value, moreDiags := attr.Expr.Value(ectx)
+		diags = append(diags, moreDiags...)
+		if !moreDiags.HasErrors() {
+			if !value.Type().IsObjectType() && !value.Type().IsMapType() {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid variables",
+					Detail:   "The variables of a run must be an object, with a value for each variable.",
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+			} else {
+				run.Variables = value.AsValueMap()
+			}
+		}
+	}
+
+	for _, block := range content.Blocks {
+		switch block.Type {
+		case mockDataLabel:
+			ref := DatasourceRef{Type: block.Labels[0], Name: block.Labels[1]}
+			attrs, moreDiags := block.Body.JustAttributes()
+			diags = append(diags, moreDiags...)
+			values, ok := attrs["values"]
+			if !ok {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Missing values",
+					Detail:   "A mock_data block must set the values of the data source.",
+					Subject:  block.DefRange.Ptr(),
+				})
+				continue
+			}
+			value, moreDiags := values.Expr.Value(ectx)
+			diags = append(diags, moreDiags...)
+			run.MockData[ref] = value
+		case assertLabel:
+			content, moreDiags := block.Body.Content(testAssertSchema)
+			diags = append(diags, moreDiags...)
+			if moreDiags.HasErrors() {
+				continue
+			}
+			assert := &TestAssert{Condition: content.Attributes["condition"].Expr}
+			if attr, ok := content.Attributes["error_message"]; ok {
+				value, moreDiags := attr.Expr.Value(ectx)
+				diags = append(diags, moreDiags...)
+				if !moreDiags.HasErrors() && value.Type() == cty.String && value.IsKnown() && !value.IsNull() {
+					assert.ErrorMessage = value.AsString()
+				}
+			}
+			run.Asserts = append(run.Asserts, assert)
+		}
+	}
+	return run, diags
+}
+
+// RunTest initializes the configuration with the variables and the mocked
+// data sources of run, and checks its assertions. The data sources that are
+// not mocked are not executed, their values are unknown. The configuration
+// must be freshly parsed, as it can only be initialized once.
+//
+// The conditions of the assertions can use the input variables, locals and
+// data sources of the configuration, and the configuration of its sources
+// decoded with the specs of their builders, as source.<type>.<name>.
+func (cfg *PackerConfig) RunTest(run *TestRun) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	names := make([]string, 0, len(run.Variables))
+	for name := range run.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := run.Variables[name]
+		variable, found := cfg.InputVariables[name]
+		if !found {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Undefined variable",
+				Detail:   fmt.Sprintf("The run sets the variable %q, which is not declared in the configuration.", name),
+				Subject:  run.HCL2Ref.DefRange.Ptr(),
+			})
+			continue
+		}
+		if variable.Type != cty.NilType {
+			var err error
+			value, err = convert.Convert(value, variable.Type)
+			if err != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid value for variable",
+					Detail:   fmt.Sprintf("The value of %s is not compatible with the variable's type constraint: %s.", name, err),
+					Subject:  run.HCL2Ref.DefRange.Ptr(),
+				})
+				continue
+			}
+		}
+		variable.Values = append(variable.Values, VariableAssignment{
+			From:  "test",
+			Value: value,
+		})
+	}
+
+	for ref, value := range run.MockData {
+		ds, found := cfg.Datasources[ref]
+		if !found {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Unknown data source",
+				Detail:   fmt.Sprintf("The run mocks data.%s.%s, which is not declared in the configuration.", ref.Type, ref.Name),
+				Subject:  run.HCL2Ref.DefRange.Ptr(),
+			})
+			continue
+		}
+		ds.value = value
+		cfg.Datasources[ref] = ds
+	}
+	if diags.HasErrors() {
+		return diags
+	}
+
+	moreDiags := cfg.Initialize(packer.InitializeOptions{SkipDatasourcesExecution: true})
+	diags = append(diags, moreDiags...)
+	if moreDiags.HasErrors() {
+		return diags
+	}
+
+	ectx := cfg.EvalContext(BuildContext, map[string]cty.Value{
+		sourcesAccessor: cty.ObjectVal(cfg.testSourceValues()),
+	})
+	for _, assert := range run.Asserts {
+		result, moreDiags := assert.Condition.Value(ectx)
+		diags = append(diags, moreDiags...)
+		if moreDiags.HasErrors() {
+			continue
+		}
+		result, err := convert.Convert(result, cty.Bool)
+		if err != nil || result.IsNull() {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid condition",
+				Detail:   "The condition of an assertion must be a boolean.",
+				Subject:  assert.Condition.Range().Ptr(),
+			})
+			continue
+		}
+		if !result.IsKnown() {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Unknown condition",
+				Detail: "The condition depends on values that are only known when " +
+					"the build runs, or on a data source that is not mocked.",
+				Subject: assert.Condition.Range().Ptr(),
+			})
+			continue
+		}
+		if result.False() {
+			message := assert.ErrorMessage
+			if message == "" {
+				message = "The condition is false."
+			}
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Test assertion failed",
+				Detail:   message,
+				Subject:  assert.Condition.Range().Ptr(),
+			})
+		}
+	}
+	return diags
+}
+
+// testSourceValues returns the configurations of the sources, decoded with
+// the specs of their builders, by type and name. Sources whose builder can't
+// be started or configuration can't be decoded are left out.
+func (cfg *PackerConfig) testSourceValues() map[string]cty.Value {
+	byType := map[string]map[string]cty.Value{}
+	for ref, source := range cfg.Sources {
+		builder, err := cfg.parser.PluginConfig.Builders.Start(ref.Type)
+		if err != nil {
+			continue
+		}
+		ectx := cfg.EvalContext(BuildContext, map[string]cty.Value{
+			sourcesAccessor: cty.ObjectVal(map[string]cty.Value{
+				"type": cty.StringVal(ref.Type),
+				"name": cty.StringVal(ref.Name),
+			}),
+		})
+		decoded, diags := decodeHCL2Spec(source.block.Body, ectx, builder)
+		if diags.HasErrors() {
+			continue
+		}
+		if byType[ref.Type] == nil {
+			byType[ref.Type] = map[string]cty.Value{}
+		}
+		byType[ref.Type][ref.Name] = decoded
+	}
+
+	res := make(map[string]cty.Value, len(byType))
+	for typ, sources := range byType {
+		res[typ] = cty.ObjectVal(sources)
+	}
+	return res
+}
@@ -196,6 +196,22 @@ func (variables Variables) Values() map[string]cty.Value {
 	return res
 }
 
+// sensitiveMark marks the values of sensitive variables and locals, so that
+// the values derived from them can be told apart.
+const sensitiveMark = "sensitive"
+
+// markedValues returns the values of the variables, where the values of
+// sensitive variables are marked as sensitive.
+func (variables Variables) markedValues() map[string]cty.Value {
+	res := variables.Values()
+	for k, v := range variables {
+		if v.Sensitive {
+			res[k] = res[k].Mark(sensitiveMark)
+		}
+	}
+	return res
+}
+
 func (variables Variables) ValidateValues() hcl.Diagnostics {
 	var diags hcl.Diagnostics
 	for _, v := range variables {
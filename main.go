@@ -244,7 +244,8 @@ func wrappedMain() int {
 				Hook:         config.StarHook,
 				PluginConfig: config.Plugins,
 			},
-			Version: version.Version,
+			Version:    version.Version,
+			EventHooks: config.Hooks,
 		},
 		Ui: ui,
 	}
@@ -322,6 +323,7 @@ func loadConfig() (*config, error) {
 		PluginMinPort:      10000,
 		PluginMaxPort:      25000,
 		KnownPluginFolders: packer.PluginFolders("."),
+		PluginMirror:       os.Getenv("PACKER_PLUGIN_MIRROR"),
 
 		// BuilderRedirects
 		BuilderRedirects: map[string]string{
@@ -384,6 +386,13 @@ func loadConfig() (*config, error) {
 			// "vsphere-template": "github.com/hashicorp/vsphere",
 		},
 	}
+	if reattach := os.Getenv("PACKER_REATTACH_PLUGINS"); reattach != "" {
+		var err error
+		config.Plugins.Reattach, err = packer.ParseReattachPlugins(reattach)
+		if err != nil {
+			return nil, err
+		}
+	}
 	if err := config.Plugins.Discover(); err != nil {
 		return nil, err
 	}
@@ -426,6 +435,17 @@ func loadConfig() (*config, error) {
 
 	config.LoadExternalComponentsFromConfig()
 
+	for _, hook := range config.Hooks {
+		if err := hook.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid hook in %s: %w", configFilePath, err)
+		}
+	}
+
+	// PACKER_PLUGIN_MIRROR takes precedence over the config file
+	if config.Plugins.PluginMirror == "" {
+		config.Plugins.PluginMirror = config.PluginMirror
+	}
+
 	return &config, nil
 }
 
@@ -4,7 +4,10 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"regexp"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/packer-plugin-sdk/common"
 	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
@@ -28,6 +31,20 @@ type CoreBuild struct {
 	CleanupProvisioner CoreBuildProvisioner
 	TemplatePath       string
 	Variables          map[string]string
+	// Timeout is the maximum duration of the build. When it is exceeded the
+	// build is cancelled, its cleanup steps are run, and it fails. Zero means
+	// no timeout.
+	Timeout time.Duration
+	// Retry is the policy of the retries of the build when it fails.
+	Retry BuildRetry
+	// DependsOn are the names of the builds, as in BuildName, that must
+	// succeed before this build starts.
+	DependsOn []string
+	// EventNotifier notifies hooks when each provisioner of the build ends.
+	EventNotifier *EventNotifier
+	// Events is the JSON output of the build, if any, where the start and
+	// the end of each provisioner are written.
+	Events *JSONUi
 
 	// Indicates whether the build is already initialized before calling Prepare(..)
 	Prepared bool
@@ -39,6 +56,56 @@ type CoreBuild struct {
 	prepareCalled bool
 }
 
+// DefaultBuildRetryBackoff is the delay before the first retry of a build,
+// when none is set.
+const DefaultBuildRetryBackoff = 10 * time.Second
+
+// BuildRetry is the policy of the retries of a failed build. A build is
+// retried from scratch, when its builder failed.
+type BuildRetry struct {
+	// MaxAttempts is the maximum number of times the build is run. The build
+	// is not retried when it is 1 or less.
+	MaxAttempts int
+	// Backoff is the delay before the first retry, it doubles after every
+	// attempt.
+	Backoff time.Duration
+	// RetryableErrors are the errors the build is retried for. When it is
+	// empty, the build is retried for any error.
+	RetryableErrors []*regexp.Regexp
+}
+
+// retryable tells whether a build that failed with err can be retried.
+func (r *BuildRetry) retryable(err error) bool {
+	if len(r.RetryableErrors) == 0 {
+		return true
+	}
+	for _, re := range r.RetryableErrors {
+		if re.MatchString(err.Error()) {
+			return true
+		}
+	}
+	return false
+}
+
+// retryError is the error of a build that failed after several attempts. It
+// wraps the error of the last attempt.
+type retryError struct {
+	errs []error
+}
+
+func (e *retryError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Build failed after %d attempts:", len(e.errs))
+	for i, err := range e.errs {
+		fmt.Fprintf(&b, "\n* attempt %d: %s", i+1, err)
+	}
+	return b.String()
+}
+
+func (e *retryError) Unwrap() error {
+	return e.errs[len(e.errs)-1]
+}
+
 // CoreBuildPostProcessor Keeps track of the post-processor and the
 // configuration of the post-processor used within a build.
 type CoreBuildPostProcessor struct {
@@ -161,6 +228,39 @@ func (b *CoreBuild) Run(ctx context.Context, originalUi packersdk.Ui) ([]packers
 		panic("Prepare must be called first")
 	}
 
+	// Builds are only retried when the failed attempts are cleaned up.
+	retry := b.Retry.MaxAttempts > 1 && (b.onError == "" || b.onError == "cleanup")
+
+	var errs []error
+	backoff := b.Retry.Backoff
+	for attempt := 1; ; attempt++ {
+		artifacts, err := b.run(ctx, originalUi)
+		if err == nil || artifacts != nil || !retry || ctx.Err() != nil ||
+			!b.Retry.retryable(err) || attempt >= b.Retry.MaxAttempts {
+			if err != nil && len(errs) > 0 {
+				err = &retryError{errs: append(errs, err)}
+			}
+			return artifacts, err
+		}
+		errs = append(errs, err)
+
+		ui := &TargetedUI{
+			Target: b.Name(),
+			Ui:     originalUi,
+		}
+		ui.Error(fmt.Sprintf("Build attempt %d/%d failed: %s", attempt, b.Retry.MaxAttempts, err))
+		ui.Say(fmt.Sprintf("Retrying the build in %s...", backoff))
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil, &retryError{errs: append(errs, ctx.Err())}
+		}
+		backoff *= 2
+	}
+}
+
+// run runs the build once.
+func (b *CoreBuild) run(ctx context.Context, originalUi packersdk.Ui) ([]packersdk.Artifact, error) {
 	// Copy the hooks
 	hooks := make(map[string][]packersdk.Hook)
 	for hookName, hookList := range b.hooks {
@@ -196,7 +296,10 @@ func (b *CoreBuild) Run(ctx context.Context, originalUi packersdk.Ui) ([]packers
 		}
 
 		hooks[packersdk.HookProvision] = append(hooks[packersdk.HookProvision], &ProvisionHook{
-			Provisioners: hookedProvisioners,
+			Provisioners:  hookedProvisioners,
+			BuildName:     b.Name(),
+			EventNotifier: b.EventNotifier,
+			Events:        b.Events,
 		})
 	}
 
@@ -207,7 +310,10 @@ func (b *CoreBuild) Run(ctx context.Context, originalUi packersdk.Ui) ([]packers
 			b.CleanupProvisioner.PType,
 		}
 		hooks[packersdk.HookCleanupProvision] = []packersdk.Hook{&ProvisionHook{
-			Provisioners: []*HookedProvisioner{hookedCleanupProvisioner},
+			Provisioners:  []*HookedProvisioner{hookedCleanupProvisioner},
+			BuildName:     b.Name(),
+			EventNotifier: b.EventNotifier,
+			Events:        b.Events,
 		}}
 	}
 
@@ -220,10 +326,20 @@ func (b *CoreBuild) Run(ctx context.Context, originalUi packersdk.Ui) ([]packers
 		Ui:     originalUi,
 	}
 
+	if b.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.Timeout)
+		defer cancel()
+	}
+
+	startTime := time.Now()
 	log.Printf("Running builder: %s", b.BuilderType)
 	ts := CheckpointReporter.AddSpan(b.BuilderType, "builder", b.BuilderConfig)
 	builderArtifact, err := b.Builder.Run(ctx, builderUi, hook)
 	ts.End(err)
+	if b.Timeout > 0 && ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("Build timed out after %s: %w", b.Timeout, ctx.Err())
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -260,7 +376,10 @@ PostProcessorRunSeqLoop:
 				builderUi.Say(fmt.Sprintf("Running post-processor: %s (type %s)", corePP.PName, corePP.PType))
 			}
 			ts := CheckpointReporter.AddSpan(corePP.PType, "post-processor", corePP.config)
-			artifact, defaultKeep, forceOverride, err := corePP.PostProcessor.PostProcess(ctx, ppUi, priorArtifact)
+			artifact, defaultKeep, forceOverride, err := corePP.PostProcessor.PostProcess(ctx, ppUi, &buildArtifact{
+				Artifact:  priorArtifact,
+				startTime: startTime,
+			})
 			ts.End(err)
 			if err != nil {
 				errors = append(errors, fmt.Errorf("Post-processor failed: %s", err))
@@ -365,3 +484,21 @@ func (b *CoreBuild) SetOnError(val string) {
 
 	b.onError = val
 }
+
+// BuildStartTimeState is the state key of the artifacts given to
+// post-processors holding the time the build started, formatted as RFC 3339.
+const BuildStartTimeState = "build_start_time"
+
+// buildArtifact is the artifact given to the post-processors of a build. It
+// adds information about the build to the state of the artifact.
+type buildArtifact struct {
+	packersdk.Artifact
+	startTime time.Time
+}
+
+func (a *buildArtifact) State(name string) interface{} {
+	if name == BuildStartTimeState {
+		return a.startTime.Format(time.RFC3339Nano)
+	}
+	return a.Artifact.State(name)
+}
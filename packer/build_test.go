@@ -2,8 +2,11 @@ package packer
 
 import (
 	"context"
+	"errors"
 	"reflect"
+	"regexp"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/packer-plugin-sdk/common"
 	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
@@ -271,6 +274,15 @@ func TestBuild_Run(t *testing.T) {
 	if !pp.PostProcessCalled {
 		t.Fatal("should be called")
 	}
+
+	// Verify post-processor got the build start time
+	startTime, ok := pp.PostProcessArtifact.State(BuildStartTimeState).(string)
+	if !ok {
+		t.Fatalf("bad: %#v", pp.PostProcessArtifact.State(BuildStartTimeState))
+	}
+	if _, err := time.Parse(time.RFC3339Nano, startTime); err != nil {
+		t.Fatalf("err: %s", err)
+	}
 }
 
 func TestBuild_Run_Artifacts(t *testing.T) {
@@ -496,3 +508,62 @@ func TestBuild_Cancel(t *testing.T) {
 		t.Fatal("build should err")
 	}
 }
+
+func TestBuild_Timeout(t *testing.T) {
+	build := testBuild()
+	build.Timeout = 10 * time.Millisecond
+
+	build.Prepare()
+
+	builder := build.Builder.(*packersdk.MockBuilder)
+
+	builder.RunFn = func(ctx context.Context) {
+		<-ctx.Done()
+	}
+
+	_, err := build.Run(context.Background(), testUi())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("build should time out, got: %v", err)
+	}
+}
+
+func TestBuild_Retry(t *testing.T) {
+	build := testBuild()
+	build.Retry = BuildRetry{MaxAttempts: 3, Backoff: time.Millisecond}
+
+	build.Prepare()
+
+	builder := build.Builder.(*packersdk.MockBuilder)
+	builder.RunErrResult = true
+
+	_, err := build.Run(context.Background(), testUi())
+	retryErr, ok := err.(*retryError)
+	if !ok {
+		t.Fatalf("build should fail after retries, got: %v", err)
+	}
+	if len(retryErr.errs) != 3 {
+		t.Fatalf("build should be run 3 times, got: %v", err)
+	}
+}
+
+func TestBuild_Retry_notRetryable(t *testing.T) {
+	build := testBuild()
+	build.Retry = BuildRetry{
+		MaxAttempts:     3,
+		Backoff:         time.Millisecond,
+		RetryableErrors: []*regexp.Regexp{regexp.MustCompile("timeout")},
+	}
+
+	build.Prepare()
+
+	builder := build.Builder.(*packersdk.MockBuilder)
+	builder.RunErrResult = true
+
+	_, err := build.Run(context.Background(), testUi())
+	if err == nil {
+		t.Fatal("build should err")
+	}
+	if _, ok := err.(*retryError); ok {
+		t.Fatalf("build should not be retried, got: %v", err)
+	}
+}
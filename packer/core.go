@@ -43,6 +43,8 @@ type CoreConfig struct {
 	Variables          map[string]string
 	SensitiveVariables []string
 	Version            string
+	// EventHooks are notified of the events of the builds
+	EventHooks EventHooks
 
 	// These are set by command-line flags
 	Except []string
@@ -262,6 +264,10 @@ func (c *Core) GetBuilds(opts GetBuildsOptions) ([]packersdk.Build, hcl.Diagnost
 		b.SetDebug(opts.Debug)
 		b.SetForce(opts.Force)
 		b.SetOnError(opts.OnError)
+		if cb, ok := b.(*CoreBuild); ok {
+			cb.Timeout = opts.Timeout
+			cb.Retry = opts.Retry()
+		}
 
 		warnings, err := b.Prepare()
 		if err != nil {
@@ -466,6 +472,9 @@ func (c *Core) InspectConfig(opts InspectConfigOptions) int {
 	// Convenience...
 	ui := opts.Ui
 	tpl := c.Template
+	if opts.JSON {
+		return c.inspectJSON(ui)
+	}
 	ui.Say("Packer Inspect: JSON mode")
 
 	// Description
@@ -584,6 +593,72 @@ func (c *Core) InspectConfig(opts InspectConfigOptions) int {
 	return 0
 }
 
+// inspectJSON writes the template as JSON, see InspectOutput.
+func (c *Core) inspectJSON(ui packersdk.Ui) int {
+	tpl := c.Template
+	out := InspectOutput{
+		ConfigType:  "json",
+		Description: tpl.Description,
+	}
+
+	sensitive := make(map[string]bool, len(tpl.SensitiveVariables))
+	for _, v := range tpl.SensitiveVariables {
+		sensitive[v.Key] = true
+	}
+	keys := make([]string, 0, len(tpl.Variables))
+	for k := range tpl.Variables {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		value := json.RawMessage("null")
+		if v, ok := c.variables[k]; ok && !sensitive[k] {
+			value, _ = json.Marshal(v)
+		}
+		out.Variables = append(out.Variables, InspectVariable{
+			Name:      k,
+			Value:     value,
+			Sensitive: sensitive[k],
+		})
+	}
+
+	build := InspectBuild{
+		Sources:        []InspectSource{},
+		Provisioners:   []InspectPlugin{},
+		PostProcessors: [][]InspectPlugin{},
+	}
+	keys = make([]string, 0, len(tpl.Builders))
+	for k := range tpl.Builders {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		b := tpl.Builders[k]
+		config, err := json.Marshal(b.Config)
+		if err != nil {
+			config = json.RawMessage("null")
+		}
+		build.Sources = append(build.Sources, InspectSource{
+			Name:   b.Name,
+			Type:   b.Type,
+			Config: config,
+		})
+	}
+	for _, p := range tpl.Provisioners {
+		build.Provisioners = append(build.Provisioners, InspectPlugin{Type: p.Type})
+	}
+	for _, ps := range tpl.PostProcessors {
+		list := []InspectPlugin{}
+		for _, p := range ps {
+			list = append(list, InspectPlugin{Type: p.Type, Name: p.Name})
+		}
+		build.PostProcessors = append(build.PostProcessors, list)
+	}
+	out.Builds = []InspectBuild{build}
+
+	return WriteInspectOutput(ui, out)
+}
+
 func (c *Core) FixConfig(opts FixConfigOptions) hcl.Diagnostics {
 	var diags hcl.Diagnostics
 
@@ -0,0 +1,188 @@
+package packer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os/exec"
+	"sync"
+	"time"
+
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+)
+
+// EventHookTimeout is the maximum duration of the notification of an event
+// to a hook.
+var EventHookTimeout = 30 * time.Second
+
+// EventHookDrainTimeout is the maximum duration Packer waits for the pending
+// notifications of hooks when it exits.
+var EventHookDrainTimeout = 30 * time.Second
+
+// eventQueueSize is the number of events that can wait for their
+// notification before notifying an event blocks.
+const eventQueueSize = 128
+
+// EventHook is notified of the events of the builds, for example to send
+// them to a chat or to a monitoring system. Events are written as JSON, like
+// the events of the json output of packer build: they are POSTed to URL, or
+// written to the stdin of Command.
+type EventHook struct {
+	URL string `json:"url"`
+	// Command and its arguments
+	Command []string `json:"command"`
+	// Events is the list of the types of the events notified to the hook:
+	// build-start, provisioner-end, build-end, artifact or error. All the
+	// events are notified when it is empty.
+	Events []string `json:"events"`
+}
+
+// Validate checks that the hook has exactly one of a URL or a Command.
+func (h *EventHook) Validate() error {
+	if (h.URL == "") == (len(h.Command) == 0) {
+		return fmt.Errorf("a hook needs exactly one of url or command")
+	}
+	return nil
+}
+
+func (h *EventHook) wants(eventType string) bool {
+	if len(h.Events) == 0 {
+		return true
+	}
+	for _, t := range h.Events {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *EventHook) notify(ctx context.Context, body []byte) error {
+	if h.URL != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("POST %s: %s", h.URL, resp.Status)
+		}
+		return nil
+	}
+
+	cmd := exec.CommandContext(ctx, h.Command[0], h.Command[1:]...)
+	cmd.Stdin = bytes.NewReader(body)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", h.Command[0], err, out)
+	}
+	return nil
+}
+
+// EventHooks are the hooks notified of the events of builds.
+type EventHooks []*EventHook
+
+// notify notifies the hooks that want it of event, and waits for them.
+// Hooks can't fail a build: their errors are only logged.
+func (hs EventHooks) notify(ctx context.Context, event JSONEvent) {
+	if event.Timestamp == "" {
+		event.Timestamp = time.Now().UTC().Format(time.RFC3339Nano)
+	}
+	event.Message = packersdk.LogSecretFilter.FilterString(event.Message)
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("[ERR] Could not marshal %s event for hooks: %s", event.Type, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, EventHookTimeout)
+	defer cancel()
+	var wg sync.WaitGroup
+	for _, h := range hs {
+		if !h.wants(event.Type) {
+			continue
+		}
+		wg.Add(1)
+		go func(h *EventHook) {
+			defer wg.Done()
+			if err := h.notify(ctx, body); err != nil {
+				log.Printf("[WARN] Failed to notify hook of %s event: %s", event.Type, err)
+			}
+		}(h)
+	}
+	wg.Wait()
+}
+
+// EventNotifier notifies hooks of events in the background, in the order of
+// the events, so that slow hooks don't slow builds down. A nil
+// *EventNotifier notifies nothing.
+type EventNotifier struct {
+	hooks  EventHooks
+	events chan JSONEvent
+	done   chan struct{}
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewEventNotifier returns a notifier of hooks, or nil when there are no
+// hooks. It must be closed to deliver the pending notifications.
+func NewEventNotifier(hooks EventHooks) *EventNotifier {
+	if len(hooks) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	n := &EventNotifier{
+		hooks:  hooks,
+		events: make(chan JSONEvent, eventQueueSize),
+		done:   make(chan struct{}),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	go func() {
+		defer close(n.done)
+		for event := range n.events {
+			n.hooks.notify(n.ctx, event)
+		}
+	}()
+	return n
+}
+
+// Notify queues the notification of event to the hooks that want it.
+func (n *EventNotifier) Notify(event JSONEvent) {
+	if n == nil {
+		return
+	}
+	if event.Timestamp == "" {
+		event.Timestamp = time.Now().UTC().Format(time.RFC3339Nano)
+	}
+	n.events <- event
+}
+
+// Close waits for the pending notifications for at most timeout, and then
+// cancels the ones that are left. Events can't be notified once it is
+// closed.
+func (n *EventNotifier) Close(timeout time.Duration) {
+	if n == nil {
+		return
+	}
+	close(n.events)
+	defer n.cancel()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-n.done:
+	case <-timer.C:
+		log.Printf("[WARN] Hooks were not notified of all the events after %s, giving up", timeout)
+		n.cancel()
+		<-n.done
+	}
+}
@@ -0,0 +1,137 @@
+package packer
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEventHook_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		hook    EventHook
+		wantErr bool
+	}{
+		{"url", EventHook{URL: "https://example.com/hook"}, false},
+		{"command", EventHook{Command: []string{"notify.sh", "-v"}}, false},
+		{"nothing", EventHook{}, true},
+		{"both", EventHook{URL: "https://example.com/hook", Command: []string{"notify.sh"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.hook.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEventNotifier_Notify_url(t *testing.T) {
+	var l sync.Mutex
+	var received []JSONEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event JSONEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("could not decode event: %s", err)
+		}
+		l.Lock()
+		received = append(received, event)
+		l.Unlock()
+	}))
+	defer server.Close()
+
+	hooks := NewEventNotifier(EventHooks{
+		{URL: server.URL, Events: []string{"build-end", "artifact"}},
+	})
+	hooks.Notify(JSONEvent{Type: "build-start", Build: "file.chocolate"})
+	hooks.Notify(JSONEvent{Type: "build-end", Build: "file.chocolate", Code: "build_failed"})
+	hooks.Close(time.Minute)
+
+	if len(received) != 1 {
+		t.Fatalf("expected only the build-end event, got %v", received)
+	}
+	received[0].Timestamp = ""
+	want := JSONEvent{Type: "build-end", Build: "file.chocolate", Code: "build_failed"}
+	if diff := cmp.Diff(want, received[0]); diff != "" {
+		t.Fatalf("unexpected event: %s", diff)
+	}
+}
+
+func TestEventNotifier_Notify_command(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the hook command is a shell command")
+	}
+
+	dir, err := ioutil.TempDir("", "packer-event-hooks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	out := filepath.Join(dir, "event.json")
+
+	hooks := NewEventNotifier(EventHooks{
+		{Command: []string{"sh", "-c", "cat > " + out}},
+	})
+	hooks.Notify(JSONEvent{Type: "provisioner-end", Build: "file.chocolate", Data: []string{"shell"}})
+	hooks.Close(time.Minute)
+
+	content, err := ioutil.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var event JSONEvent
+	if err := json.Unmarshal(content, &event); err != nil {
+		t.Fatalf("could not decode %q: %s", content, err)
+	}
+	if event.Type != "provisioner-end" || event.Build != "file.chocolate" || event.Timestamp == "" {
+		t.Fatalf("unexpected event: %#v", event)
+	}
+}
+
+func TestEventNotifier_slowHook(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	hooks := NewEventNotifier(EventHooks{{URL: server.URL}})
+
+	// Builds don't wait for the hooks
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		hooks.Notify(JSONEvent{Type: "build-start", Build: "file.chocolate"})
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("Notify waited for the hook for %s", d)
+	}
+
+	// Pending notifications are cancelled once the drain timeout is exceeded
+	start = time.Now()
+	hooks.Close(100 * time.Millisecond)
+	if d := time.Since(start); d > 5*time.Second {
+		t.Fatalf("Close waited for %s", d)
+	}
+}
+
+func TestEventNotifier_nil(t *testing.T) {
+	hooks := NewEventNotifier(nil)
+	if hooks != nil {
+		t.Fatalf("expected no notifier without hooks, got %#v", hooks)
+	}
+	hooks.Notify(JSONEvent{Type: "build-start"})
+	hooks.Close(time.Second)
+}
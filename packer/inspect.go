@@ -0,0 +1,77 @@
+package packer
+
+import (
+	"encoding/json"
+	"fmt"
+
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+)
+
+// InspectFormatVersion is the version of the format written by
+// packer inspect -json. It is incremented with any change to its fields that
+// is not backwards compatible.
+const InspectFormatVersion = "1"
+
+// InspectOutput is the configuration written by packer inspect -json.
+type InspectOutput struct {
+	FormatVersion string `json:"format_version"`
+	// The type of the configuration, "hcl2" or "json".
+	ConfigType  string            `json:"config_type"`
+	Description string            `json:"description,omitempty"`
+	Variables   []InspectVariable `json:"variables"`
+	Locals      []InspectVariable `json:"locals,omitempty"`
+	Builds      []InspectBuild    `json:"builds"`
+}
+
+// InspectVariable is a variable or a local of a configuration. The value of a
+// sensitive variable is always null.
+type InspectVariable struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Value       json.RawMessage `json:"value"`
+	Sensitive   bool            `json:"sensitive"`
+}
+
+// InspectBuild is a build block of a configuration, legacy JSON templates
+// have a single build.
+type InspectBuild struct {
+	Name           string            `json:"name,omitempty"`
+	Description    string            `json:"description,omitempty"`
+	DependsOn      []string          `json:"depends_on,omitempty"`
+	Sources        []InspectSource   `json:"sources"`
+	Provisioners   []InspectPlugin   `json:"provisioners"`
+	PostProcessors [][]InspectPlugin `json:"post_processors"`
+}
+
+// InspectSource is a source of a build, with its configuration once evaluated.
+// The configuration is null when the source can't be evaluated, e.g. when its
+// plugin is not installed.
+type InspectSource struct {
+	Name   string          `json:"name"`
+	Type   string          `json:"type"`
+	Config json.RawMessage `json:"config"`
+}
+
+// InspectPlugin is a provisioner or a post-processor of a build.
+type InspectPlugin struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+}
+
+// WriteInspectOutput writes out as indented JSON to ui.
+func WriteInspectOutput(ui packersdk.Ui, out InspectOutput) int {
+	out.FormatVersion = InspectFormatVersion
+	if out.Variables == nil {
+		out.Variables = []InspectVariable{}
+	}
+	if out.Builds == nil {
+		out.Builds = []InspectBuild{}
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to write the configuration as JSON: %s", err))
+		return 1
+	}
+	ui.Say(string(data))
+	return 0
+}
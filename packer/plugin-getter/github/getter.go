@@ -1,7 +1,6 @@
 package github
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -33,45 +32,6 @@ type Getter struct {
 
 var _ plugingetter.Getter = &Getter{}
 
-func tranformChecksumStream() func(in io.ReadCloser) (io.ReadCloser, error) {
-	return func(in io.ReadCloser) (io.ReadCloser, error) {
-		defer in.Close()
-		rd := bufio.NewReader(in)
-		buffer := bytes.NewBufferString("[")
-		json := json.NewEncoder(buffer)
-		for i := 0; ; i++ {
-			line, err := rd.ReadString('\n')
-			if err != nil {
-				if err != io.EOF {
-					return nil, fmt.Errorf(
-						"Error reading checksum file: %s", err)
-				}
-				break
-			}
-			parts := strings.Fields(line)
-			switch len(parts) {
-			case 2: // nominal case
-				checksumString, checksumFilename := parts[0], parts[1]
-
-				if i > 0 {
-					_, _ = buffer.WriteString(",")
-				}
-				if err := json.Encode(struct {
-					Checksum string `json:"checksum"`
-					Filename string `json:"filename"`
-				}{
-					Checksum: checksumString,
-					Filename: checksumFilename,
-				}); err != nil {
-					return nil, err
-				}
-			}
-		}
-		_, _ = buffer.WriteString("]")
-		return ioutil.NopCloser(buffer), nil
-	}
-}
-
 // transformVersionStream get a stream from github tags and transforms it into
 // something Packer wants, namely a json list of Release.
 func transformVersionStream(in io.ReadCloser) (io.ReadCloser, error) {
@@ -202,7 +162,7 @@ func (g *Getter) Get(what string, opts plugingetter.GetOptions) (io.ReadCloser,
 			u,
 			nil,
 		)
-		transform = tranformChecksumStream()
+		transform = plugingetter.TransformChecksumStream
 	case "zip":
 		u := filepath.ToSlash("https://github.com/" + opts.PluginRequirement.Identifier.RealRelativePath() + "/releases/download/" + opts.Version() + "/" + opts.ExpectedZipFilename())
 		req, err = g.Client.NewRequest(
@@ -0,0 +1,190 @@
+package plugingetter
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// LockFileName is the name of the file, next to a configuration, where
+// packer init records the plugins it selected for that configuration.
+const LockFileName = ".packer.lock.hcl"
+
+const lockFileHeader = `# This file is maintained automatically by "packer init".
+# Manual edits may be lost in future updates.
+`
+
+// LockFile records the exact version of each plugin required by a
+// configuration, and the checksums of the binaries of that version, so that
+// the same plugins are used on every machine:
+//
+//	plugin "github.com/hashicorp/amazon" {
+//	  version     = "1.0.4"
+//	  constraints = "~> 1.0"
+//	  hashes = [
+//	    "sha256:8f7a3b...",
+//	  ]
+//	}
+type LockFile struct {
+	// Plugins by source, like github.com/hashicorp/amazon
+	Plugins map[string]*LockedPlugin
+}
+
+// LockedPlugin is the selected version of a plugin.
+type LockedPlugin struct {
+	// Version without a v prefix, like 1.0.4
+	Version string
+	// Constraints the version was selected with
+	Constraints string
+	// Hashes of the binaries of that version, one per system the
+	// configuration was initialized on, like sha256:8f7a3b...
+	Hashes []string
+}
+
+type lockFileBody struct {
+	Plugins []struct {
+		Source      string   `hcl:"source,label"`
+		Version     string   `hcl:"version"`
+		Constraints string   `hcl:"constraints,optional"`
+		Hashes      []string `hcl:"hashes,optional"`
+	} `hcl:"plugin,block"`
+}
+
+// ReadLockFile reads the lock file in path. An empty LockFile is returned
+// when the file doesn't exist.
+func ReadLockFile(path string) (*LockFile, error) {
+	lock := &LockFile{Plugins: map[string]*LockedPlugin{}}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return lock, nil
+	}
+
+	f, diags := hclparse.NewParser().ParseHCLFile(path)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	var body lockFileBody
+	if diags := gohcl.DecodeBody(f.Body, nil, &body); diags.HasErrors() {
+		return nil, diags
+	}
+	for _, plugin := range body.Plugins {
+		lock.Plugins[plugin.Source] = &LockedPlugin{
+			Version:     plugin.Version,
+			Constraints: plugin.Constraints,
+			Hashes:      plugin.Hashes,
+		}
+	}
+	return lock, nil
+}
+
+// Write writes the lock file to path.
+func (l *LockFile) Write(path string) error {
+	f := hclwrite.NewEmptyFile()
+	body := f.Body()
+
+	sources := make([]string, 0, len(l.Plugins))
+	for source := range l.Plugins {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	for _, source := range sources {
+		plugin := l.Plugins[source]
+		body.AppendNewline()
+		pluginBody := body.AppendNewBlock("plugin", []string{source}).Body()
+		pluginBody.SetAttributeValue("version", cty.StringVal(plugin.Version))
+		if plugin.Constraints != "" {
+			pluginBody.SetAttributeValue("constraints", cty.StringVal(plugin.Constraints))
+		}
+		if len(plugin.Hashes) > 0 {
+			hashes := make([]cty.Value, 0, len(plugin.Hashes))
+			for _, hash := range plugin.Hashes {
+				hashes = append(hashes, cty.StringVal(hash))
+			}
+			pluginBody.SetAttributeValue("hashes", cty.ListVal(hashes))
+		}
+	}
+
+	return ioutil.WriteFile(path, append([]byte(lockFileHeader), f.Bytes()...), 0644)
+}
+
+// Lock records that install was selected for the plugin required by pr. The
+// checksum of its binary is added to the hashes of that version, or replaces
+// them when another version was locked.
+func (l *LockFile) Lock(pr *Requirement, install *Installation, checksummer Checksummer) error {
+	cs, err := checksummer.GetCacheChecksumOfFile(install.BinaryPath)
+	if err != nil {
+		return fmt.Errorf("could not get the checksum of %q: %w", install.BinaryPath, err)
+	}
+	hash := checksummer.Type + ":" + Checksum(cs).String()
+	v := strings.TrimPrefix(install.Version, "v")
+
+	plugin, found := l.Plugins[pr.Identifier.String()]
+	if !found || plugin.Version != v {
+		plugin = &LockedPlugin{Version: v}
+		l.Plugins[pr.Identifier.String()] = plugin
+	}
+	plugin.Constraints = pr.VersionConstraints.String()
+	for _, h := range plugin.Hashes {
+		if h == hash {
+			return nil
+		}
+	}
+	plugin.Hashes = append(plugin.Hashes, hash)
+	sort.Strings(plugin.Hashes)
+	return nil
+}
+
+// Locked returns the requirement of the version of the plugin required by pr
+// that is locked, or nil when no version is locked or when the locked version
+// doesn't match the constraints of pr anymore.
+func (l *LockFile) Locked(pr *Requirement) *Requirement {
+	plugin, found := l.Plugins[pr.Identifier.String()]
+	if !found {
+		return nil
+	}
+	v, err := version.NewVersion(plugin.Version)
+	if err != nil || !pr.VersionConstraints.Check(v) {
+		return nil
+	}
+	constraints, err := version.NewConstraint("= " + v.String())
+	if err != nil {
+		return nil
+	}
+	locked := *pr
+	locked.VersionConstraints = constraints
+	return &locked
+}
+
+// CheckInstallation checks that install is the locked version of the plugin
+// required by pr, and that the checksum of its binary is one of the locked
+// hashes.
+func (l *LockFile) CheckInstallation(pr *Requirement, install *Installation, checksummer Checksummer) error {
+	plugin, found := l.Plugins[pr.Identifier.String()]
+	if !found {
+		return nil
+	}
+	if v := strings.TrimPrefix(install.Version, "v"); v != plugin.Version {
+		return fmt.Errorf("version %s is locked, but %s is installed", plugin.Version, v)
+	}
+	if len(plugin.Hashes) == 0 {
+		return nil
+	}
+	cs, err := checksummer.GetCacheChecksumOfFile(install.BinaryPath)
+	if err != nil {
+		return fmt.Errorf("could not get the checksum of %q: %w", install.BinaryPath, err)
+	}
+	hash := checksummer.Type + ":" + Checksum(cs).String()
+	for _, h := range plugin.Hashes {
+		if h == hash {
+			return nil
+		}
+	}
+	return fmt.Errorf("the checksum of %q, %s, is not one of the locked hashes", install.BinaryPath, hash)
+}
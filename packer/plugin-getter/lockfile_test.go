@@ -0,0 +1,97 @@
+package plugingetter
+
+import (
+	"crypto/sha256"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/packer/hcl2template/addrs"
+)
+
+func TestLockFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "packer-lock-file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	lockPath := filepath.Join(dir, LockFileName)
+
+	lock, err := ReadLockFile(lockPath)
+	if err != nil {
+		t.Fatalf("ReadLockFile of a missing file: %s", err)
+	}
+	if len(lock.Plugins) != 0 {
+		t.Fatalf("expected an empty lock file, got %v", lock.Plugins)
+	}
+
+	identifier, diags := addrs.ParsePluginSourceString("github.com/hashicorp/amazon")
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	constraints, _ := version.NewConstraint("~> 1.2")
+	req := &Requirement{
+		Accessor:           "amazon",
+		Identifier:         identifier,
+		VersionConstraints: constraints,
+	}
+	checksummer := Checksummer{Type: "sha256", Hash: sha256.New()}
+	amazonFolder := filepath.Join(pluginFolderOne, "github.com", "hashicorp", "amazon")
+	v124 := &Installation{
+		BinaryPath: filepath.Join(amazonFolder, "packer-plugin-amazon_v1.2.4_x5.0_darwin_amd64"),
+		Version:    "v1.2.4",
+	}
+	v125 := &Installation{
+		BinaryPath: filepath.Join(amazonFolder, "packer-plugin-amazon_v1.2.5_x5.0_darwin_amd64"),
+		Version:    "v1.2.5",
+	}
+
+	if err := lock.Lock(req, v124, checksummer); err != nil {
+		t.Fatalf("Lock: %s", err)
+	}
+	if err := lock.Write(lockPath); err != nil {
+		t.Fatalf("Write: %s", err)
+	}
+	lock, err = ReadLockFile(lockPath)
+	if err != nil {
+		t.Fatalf("ReadLockFile: %s", err)
+	}
+	want := map[string]*LockedPlugin{
+		"github.com/hashicorp/amazon": {
+			Version:     "1.2.4",
+			Constraints: "~> 1.2",
+			Hashes:      []string{"sha256:4b227777d4dd1fc61c6f884f48641d02b4d121d3fd328cb08b5531fcacdabf8a"},
+		},
+	}
+	if diff := cmp.Diff(want, lock.Plugins); diff != "" {
+		t.Fatalf("unexpected lock file: %s", diff)
+	}
+
+	locked := lock.Locked(req)
+	if locked == nil {
+		t.Fatal("expected the plugin to be locked")
+	}
+	if got := locked.VersionConstraints.String(); got != "= 1.2.4" {
+		t.Errorf("unexpected locked constraints %q", got)
+	}
+
+	if err := lock.CheckInstallation(req, v124, checksummer); err != nil {
+		t.Errorf("CheckInstallation of the locked version: %s", err)
+	}
+	if err := lock.CheckInstallation(req, v125, checksummer); err == nil {
+		t.Error("expected CheckInstallation of another version to fail")
+	}
+	lock.Plugins["github.com/hashicorp/amazon"].Hashes = []string{"sha256:0000"}
+	if err := lock.CheckInstallation(req, v124, checksummer); err == nil {
+		t.Error("expected CheckInstallation of another binary to fail")
+	}
+
+	// The locked version doesn't match the config anymore
+	req.VersionConstraints, _ = version.NewConstraint(">= 2.0")
+	if locked := lock.Locked(req); locked != nil {
+		t.Errorf("expected no locked version, got %s", locked.VersionConstraints)
+	}
+}
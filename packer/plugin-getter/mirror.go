@@ -0,0 +1,224 @@
+package plugingetter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+)
+
+// MirrorReleasesFileName is the name of the file listing the releases of a
+// plugin in a mirror, as the json list of Release that getters return.
+const MirrorReleasesFileName = "releases.json"
+
+type MirrorOptions struct {
+	// Different means to get releases, sha256 and binary files.
+	Getters []Getter
+
+	// Folder of the mirror.
+	Folder string
+
+	// Platforms to mirror, like linux_amd64.
+	Platforms []string
+
+	// The OS, ARCH and Ext of these options are ignored, Platforms are used
+	// instead.
+	BinaryInstallationOptions
+}
+
+// Mirror downloads the latest release of the plugin that matches pr, for each
+// platform of opts, in the folder of a mirror. The files keep the names they
+// have in the release, in the folder of the plugin:
+//
+//	github.com/hashicorp/amazon/releases.json
+//	github.com/hashicorp/amazon/packer-plugin-amazon_v1.0.4_SHA256SUMS
+//	github.com/hashicorp/amazon/packer-plugin-amazon_v1.0.4_x5.0_linux_amd64.zip
+//
+// The mirrored version is returned.
+func (pr *Requirement) Mirror(opts MirrorOptions) (*version.Version, error) {
+	versions := pr.matchingVersions(opts.Getters, opts.BinaryInstallationOptions)
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("no release version found for the %s plugin matching the constraint(s): %q", pr.Identifier, pr.VersionConstraints.String())
+	}
+	sort.Sort(versions)
+	v := versions[len(versions)-1]
+	getOpts := GetOptions{
+		PluginRequirement:         pr,
+		BinaryInstallationOptions: opts.BinaryInstallationOptions,
+		version:                   v,
+	}
+
+	outputFolder := filepath.Join(opts.Folder, filepath.Join(pr.Identifier.Parts()...))
+	if err := os.MkdirAll(outputFolder, 0755); err != nil {
+		return nil, fmt.Errorf("could not create mirror folder %q: %w", outputFolder, err)
+	}
+
+	checksummer := opts.Checksummers[0]
+	var entries []ChecksumFileEntry
+	for _, getter := range opts.Getters {
+		checksumFile, err := getter.Get(checksummer.Type, getOpts)
+		if err != nil {
+			log.Printf("[TRACE] could not get %s checksum file for %s version %s: %s", checksummer.Type, pr.Identifier, v, err)
+			continue
+		}
+		entries, err = ParseChecksumFileEntries(checksumFile)
+		_ = checksumFile.Close()
+		if err != nil {
+			log.Printf("[TRACE] could not parse %s checksumfile: %v", checksummer.Type, err)
+			continue
+		}
+		break
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("could not get the %s checksum file of %s version %s", checksummer.Type, pr.Identifier, v)
+	}
+
+	sumsFileName := filepath.Join(outputFolder, pr.FilenamePrefix()+getOpts.Version()+"_"+strings.ToUpper(checksummer.Type)+"SUMS")
+	mirrored := map[string]bool{}
+	// The checksum file lists the releases that are mirrored: the ones of
+	// platforms mirrored before are kept.
+	sums := mirroredChecksums(sumsFileName, outputFolder)
+	for _, entry := range entries {
+		if err := entry.init(pr); err != nil {
+			log.Printf("[TRACE] could not parse checksum filename %s: %s", entry.Filename, err)
+			continue
+		}
+		platform := entry.Os() + "_" + entry.Arch()
+		if !containsString(opts.Platforms, platform) {
+			continue
+		}
+		if err := opts.CheckProtocolVersion(entry.ProtVersion()); err != nil {
+			log.Printf("[TRACE] Ignoring remote binary %s, %s", entry.Filename, err)
+			continue
+		}
+		cs, err := checksummer.ParseChecksum(strings.NewReader(entry.Checksum))
+		if err != nil {
+			return nil, fmt.Errorf("could not parse the checksum of %s: %w", entry.Filename, err)
+		}
+		getOpts.expectedZipFilename = entry.Filename
+		if err := mirrorFile(opts.Getters, getOpts, checksummer, cs, filepath.Join(outputFolder, entry.Filename)); err != nil {
+			return nil, err
+		}
+		mirrored[platform] = true
+		sums[entry.Filename] = entry.Checksum
+	}
+	for _, platform := range opts.Platforms {
+		if !mirrored[platform] {
+			return nil, fmt.Errorf("%s version %s has no release for %s", pr.Identifier, v, platform)
+		}
+	}
+
+	// The checksum file and the list of releases are written last, so that
+	// the mirror never lists a version that is not completely mirrored.
+	filenames := make([]string, 0, len(sums))
+	for filename := range sums {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+	var sumsFile strings.Builder
+	for _, filename := range filenames {
+		fmt.Fprintf(&sumsFile, "%s  %s\n", sums[filename], filename)
+	}
+	if err := ioutil.WriteFile(sumsFileName, []byte(sumsFile.String()), 0644); err != nil {
+		return nil, err
+	}
+
+	releasesFileName := filepath.Join(outputFolder, MirrorReleasesFileName)
+	releases := []Release{}
+	if f, err := os.Open(releasesFileName); err == nil {
+		if releases, err = ParseReleases(f); err != nil {
+			return nil, fmt.Errorf("could not parse %q: %w", releasesFileName, err)
+		}
+	}
+	found := false
+	for _, release := range releases {
+		if release.Version == getOpts.Version() {
+			found = true
+		}
+	}
+	if !found {
+		releases = append(releases, Release{Version: getOpts.Version()})
+	}
+	out, err := json.MarshalIndent(releases, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return v, ioutil.WriteFile(releasesFileName, out, 0644)
+}
+
+// mirroredChecksums returns the checksums of the checksum file of a mirror,
+// by filename, for the files that are in folder.
+func mirroredChecksums(sumsFileName, folder string) map[string]string {
+	sums := map[string]string{}
+	f, err := os.Open(sumsFileName)
+	if err != nil {
+		return sums
+	}
+	transformed, err := TransformChecksumStream(f)
+	if err != nil {
+		log.Printf("[TRACE] could not read %s: %s", sumsFileName, err)
+		return sums
+	}
+	entries, err := ParseChecksumFileEntries(transformed)
+	if err != nil {
+		log.Printf("[TRACE] could not parse %s: %s", sumsFileName, err)
+		return sums
+	}
+	for _, entry := range entries {
+		if _, err := os.Stat(filepath.Join(folder, entry.Filename)); err == nil {
+			sums[entry.Filename] = entry.Checksum
+		}
+	}
+	return sums
+}
+
+// mirrorFile gets the zip file of getOpts into outputFileName, unless it is
+// already there, and checks that its checksum is the expected one.
+func mirrorFile(getters []Getter, getOpts GetOptions, checksummer Checksummer, expected []byte, outputFileName string) error {
+	if err := checksummer.ChecksumFile(expected, outputFileName); err == nil {
+		log.Printf("[INFO] %q is already mirrored", outputFileName)
+		return nil
+	}
+
+	for _, getter := range getters {
+		remoteZipFile, err := getter.Get("zip", getOpts)
+		if err != nil {
+			log.Printf("[TRACE] could not get %s: %s", getOpts.ExpectedZipFilename(), err)
+			continue
+		}
+		outputFile, err := os.Create(outputFileName)
+		if err != nil {
+			_ = remoteZipFile.Close()
+			return fmt.Errorf("failed to create %s: %w", outputFileName, err)
+		}
+		_, err = io.Copy(outputFile, remoteZipFile)
+		_ = remoteZipFile.Close()
+		_ = outputFile.Close()
+		if err != nil {
+			log.Printf("[TRACE] error getting %s: %s, trying another getter", getOpts.ExpectedZipFilename(), err)
+			continue
+		}
+		if err := checksummer.ChecksumFile(expected, outputFileName); err != nil {
+			_ = os.Remove(outputFileName)
+			return fmt.Errorf("%w. Is the checksum file correct ? Is the binary file correct ?", err)
+		}
+		return nil
+	}
+	return fmt.Errorf("could not get %s", getOpts.ExpectedZipFilename())
+}
+
+func containsString(slice []string, s string) bool {
+	for _, elem := range slice {
+		if elem == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package mirror
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	plugingetter "github.com/hashicorp/packer/packer/plugin-getter"
+)
+
+// Getter gets plugins from a mirror instead of their releases, for example on
+// a machine without internet access. The mirror is a folder, or a URL serving
+// a folder, populated by packer plugins mirror: each plugin has its own
+// folder, like github.com/hashicorp/amazon, with a releases.json file listing
+// its versions and the files of these releases.
+type Getter struct {
+	// Mirror is the path of the folder or the http(s) URL of the mirror.
+	Mirror string
+
+	// Client used for http mirrors, nil means http.DefaultClient.
+	Client *http.Client
+}
+
+var _ plugingetter.Getter = &Getter{}
+
+func (g *Getter) Get(what string, opts plugingetter.GetOptions) (io.ReadCloser, error) {
+	var filename string
+	transform := func(in io.ReadCloser) (io.ReadCloser, error) {
+		return in, nil
+	}
+
+	switch what {
+	case "releases":
+		filename = plugingetter.MirrorReleasesFileName
+	case "sha256":
+		filename = opts.PluginRequirement.FilenamePrefix() + opts.Version() + "_SHA256SUMS"
+		transform = plugingetter.TransformChecksumStream
+	case "zip":
+		filename = opts.ExpectedZipFilename()
+	default:
+		return nil, fmt.Errorf("%q not implemented", what)
+	}
+
+	parts := append(opts.PluginRequirement.Identifier.Parts(), filename)
+	in, err := g.open(parts)
+	if err != nil {
+		return nil, err
+	}
+	return transform(in)
+}
+
+func (g *Getter) open(parts []string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(g.Mirror, "http://") && !strings.HasPrefix(g.Mirror, "https://") {
+		p := filepath.Join(append([]string{g.Mirror}, parts...)...)
+		log.Printf("[DEBUG] mirror-getter: opening %q", p)
+		return os.Open(p)
+	}
+
+	client := g.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	u := strings.TrimSuffix(g.Mirror, "/") + "/" + path.Join(parts...)
+	log.Printf("[DEBUG] mirror-getter: getting %q", u)
+	resp, err := client.Get(u)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("getting %q: %s", u, resp.Status)
+	}
+	return resp.Body, nil
+}
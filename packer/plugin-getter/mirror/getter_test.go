@@ -0,0 +1,69 @@
+package mirror
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/packer/hcl2template/addrs"
+	plugingetter "github.com/hashicorp/packer/packer/plugin-getter"
+)
+
+func TestGetter_Get(t *testing.T) {
+	dir, err := ioutil.TempDir("", "packer-plugin-mirror")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	pluginFolder := filepath.Join(dir, "github.com", "hashicorp", "amazon")
+	if err := os.MkdirAll(pluginFolder, 0755); err != nil {
+		t.Fatal(err)
+	}
+	releasesFile := filepath.Join(pluginFolder, plugingetter.MirrorReleasesFileName)
+	if err := ioutil.WriteFile(releasesFile, []byte(`[{"version": "v1.2.4"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	defer server.Close()
+
+	amazon, diags := addrs.ParsePluginSourceString("github.com/hashicorp/amazon")
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	google, diags := addrs.ParsePluginSourceString("github.com/hashicorp/google")
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	for _, mirror := range []string{dir, server.URL} {
+		t.Run(mirror, func(t *testing.T) {
+			g := &Getter{Mirror: mirror}
+
+			f, err := g.Get("releases", plugingetter.GetOptions{
+				PluginRequirement: &plugingetter.Requirement{Identifier: amazon},
+			})
+			if err != nil {
+				t.Fatalf("Get releases: %s", err)
+			}
+			releases, err := plugingetter.ParseReleases(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff([]plugingetter.Release{{Version: "v1.2.4"}}, releases); diff != "" {
+				t.Fatalf("unexpected releases: %s", diff)
+			}
+
+			// plugins that are not mirrored are not found
+			if _, err := g.Get("releases", plugingetter.GetOptions{
+				PluginRequirement: &plugingetter.Requirement{Identifier: google},
+			}); err == nil {
+				t.Fatal("expected an error for a plugin that is not mirrored")
+			}
+		})
+	}
+}
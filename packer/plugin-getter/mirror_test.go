@@ -0,0 +1,173 @@
+package plugingetter
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/packer/hcl2template/addrs"
+)
+
+func TestRequirement_Mirror(t *testing.T) {
+	dir, err := ioutil.TempDir("", "packer-plugin-mirror")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	zips := map[string][]byte{}
+	checksums := []ChecksumFileEntry{}
+	for _, filename := range []string{
+		"packer-plugin-amazon_v1.2.4_x5.0_linux_amd64.zip",
+		"packer-plugin-amazon_v1.2.4_x5.0_darwin_amd64.zip",
+		"packer-plugin-amazon_v1.2.4_x5.0_windows_amd64.zip",
+	} {
+		content, _ := ioutil.ReadAll(zipFile(map[string]string{
+			filename[:len(filename)-len(".zip")]: "v1.2.4",
+		}))
+		zips[filename] = content
+		sum := sha256.Sum256(content)
+		checksums = append(checksums, ChecksumFileEntry{
+			Filename: filename,
+			Checksum: hex.EncodeToString(sum[:]),
+		})
+	}
+	getter := &mockPluginGetter{
+		Releases: []Release{
+			{Version: "v1.2.3"},
+			{Version: "v1.2.4"},
+			{Version: "v2.0.0"},
+		},
+		ChecksumFileEntries: map[string][]ChecksumFileEntry{
+			"1.2.4": checksums,
+		},
+		Zips: map[string]io.ReadCloser{},
+	}
+	for filename, content := range zips {
+		getter.Zips["github.com/hashicorp/packer-plugin-amazon/"+filename] = ioutil.NopCloser(bytes.NewReader(content))
+	}
+
+	identifier, diags := addrs.ParsePluginSourceString("github.com/hashicorp/amazon")
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	constraints, _ := version.NewConstraint("~> 1.2")
+	req := &Requirement{
+		Identifier:         identifier,
+		VersionConstraints: constraints,
+	}
+
+	v, err := req.Mirror(MirrorOptions{
+		Getters:   []Getter{getter},
+		Folder:    dir,
+		Platforms: []string{"linux_amd64", "darwin_amd64"},
+		BinaryInstallationOptions: BinaryInstallationOptions{
+			APIVersionMajor: "5", APIVersionMinor: "0",
+			Checksummers: []Checksummer{
+				{Type: "sha256", Hash: sha256.New()},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Mirror: %s", err)
+	}
+	if v.String() != "1.2.4" {
+		t.Fatalf("unexpected mirrored version %s", v)
+	}
+
+	pluginFolder := filepath.Join(dir, "github.com", "hashicorp", "amazon")
+	files, err := ioutil.ReadDir(pluginFolder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name())
+	}
+	wantNames := []string{
+		"packer-plugin-amazon_v1.2.4_SHA256SUMS",
+		"packer-plugin-amazon_v1.2.4_x5.0_darwin_amd64.zip",
+		"packer-plugin-amazon_v1.2.4_x5.0_linux_amd64.zip",
+		"releases.json",
+	}
+	if diff := cmp.Diff(wantNames, names); diff != "" {
+		t.Fatalf("unexpected mirror files: %s", diff)
+	}
+
+	releases, err := os.Open(filepath.Join(pluginFolder, MirrorReleasesFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotReleases, err := ParseReleases(releases)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]Release{{Version: "v1.2.4"}}, gotReleases); diff != "" {
+		t.Fatalf("unexpected releases: %s", diff)
+	}
+
+	// The checksum file keeps the format of the one of the release, and only
+	// lists the mirrored files
+	readChecksums := func() []ChecksumFileEntry {
+		sums, err := os.Open(filepath.Join(pluginFolder, "packer-plugin-amazon_v1.2.4_SHA256SUMS"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		transformed, err := TransformChecksumStream(sums)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gotChecksums, err := ParseChecksumFileEntries(transformed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return gotChecksums
+	}
+	wantChecksums := []ChecksumFileEntry{checksums[1], checksums[0]}
+	if diff := cmp.Diff(wantChecksums, readChecksums(), cmp.AllowUnexported(ChecksumFileEntry{})); diff != "" {
+		t.Fatalf("unexpected checksums: %s", diff)
+	}
+
+	// Mirroring another platform keeps the checksums of the mirrored ones
+	_, err = req.Mirror(MirrorOptions{
+		Getters:   []Getter{getter},
+		Folder:    dir,
+		Platforms: []string{"windows_amd64"},
+		BinaryInstallationOptions: BinaryInstallationOptions{
+			APIVersionMajor: "5", APIVersionMinor: "0",
+			Checksummers: []Checksummer{
+				{Type: "sha256", Hash: sha256.New()},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Mirror: %s", err)
+	}
+	wantChecksums = []ChecksumFileEntry{checksums[1], checksums[0], checksums[2]}
+	if diff := cmp.Diff(wantChecksums, readChecksums(), cmp.AllowUnexported(ChecksumFileEntry{})); diff != "" {
+		t.Fatalf("unexpected checksums: %s", diff)
+	}
+
+	// A platform without release fails
+	_, err = req.Mirror(MirrorOptions{
+		Getters:   []Getter{getter},
+		Folder:    dir,
+		Platforms: []string{"freebsd_arm"},
+		BinaryInstallationOptions: BinaryInstallationOptions{
+			APIVersionMajor: "5", APIVersionMinor: "0",
+			Checksummers: []Checksummer{
+				{Type: "sha256", Hash: sha256.New()},
+			},
+		},
+	})
+	if err == nil {
+		t.Fatal("expected an error for a platform without release")
+	}
+}
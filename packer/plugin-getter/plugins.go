@@ -2,6 +2,8 @@ package plugingetter
 
 import (
 	"archive/zip"
+	"bufio"
+	"bytes"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -338,23 +340,61 @@ func (e *ChecksumFileEntry) validate(expectedVersion string, installOpts BinaryI
 	return installOpts.CheckProtocolVersion(e.protVersion)
 }
 
+// TransformChecksumStream transforms a checksums file, with a checksum and a
+// filename per line like the SHA256SUMS file of a release, into the json list
+// of ChecksumFileEntry that getters return.
+func TransformChecksumStream(in io.ReadCloser) (io.ReadCloser, error) {
+	defer in.Close()
+	rd := bufio.NewReader(in)
+	buffer := bytes.NewBufferString("[")
+	json := json.NewEncoder(buffer)
+	for i := 0; ; i++ {
+		line, err := rd.ReadString('\n')
+		if err != nil {
+			if err != io.EOF {
+				return nil, fmt.Errorf(
+					"Error reading checksum file: %s", err)
+			}
+			break
+		}
+		parts := strings.Fields(line)
+		switch len(parts) {
+		case 2: // nominal case
+			checksumString, checksumFilename := parts[0], parts[1]
+
+			if i > 0 {
+				_, _ = buffer.WriteString(",")
+			}
+			if err := json.Encode(struct {
+				Checksum string `json:"checksum"`
+				Filename string `json:"filename"`
+			}{
+				Checksum: checksumString,
+				Filename: checksumFilename,
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	_, _ = buffer.WriteString("]")
+	return ioutil.NopCloser(buffer), nil
+}
+
 func ParseChecksumFileEntries(f io.Reader) ([]ChecksumFileEntry, error) {
 	var entries []ChecksumFileEntry
 	return entries, json.NewDecoder(f).Decode(&entries)
 }
 
-func (pr *Requirement) InstallLatest(opts InstallOptions) (*Installation, error) {
-
-	getters := opts.Getters
-	fail := fmt.Errorf("could not find a local nor a remote checksum for plugin %q %q", pr.Identifier, pr.VersionConstraints)
-
+// matchingVersions returns the versions of the releases of the plugin that
+// match the version constraints of pr, from the first getter that has some.
+func (pr *Requirement) matchingVersions(getters []Getter, opts BinaryInstallationOptions) version.Collection {
 	log.Printf("[TRACE] getting available versions for the %s plugin", pr.Identifier)
 	versions := version.Collection{}
 	for _, getter := range getters {
 
 		releasesFile, err := getter.Get("releases", GetOptions{
 			PluginRequirement:         pr,
-			BinaryInstallationOptions: opts.BinaryInstallationOptions,
+			BinaryInstallationOptions: opts,
 		})
 		if err != nil {
 			err := fmt.Errorf("%q getter could not get release: %w", getter, err)
@@ -392,6 +432,15 @@ func (pr *Requirement) InstallLatest(opts InstallOptions) (*Installation, error)
 
 		break
 	}
+	return versions
+}
+
+func (pr *Requirement) InstallLatest(opts InstallOptions) (*Installation, error) {
+
+	getters := opts.Getters
+	fail := fmt.Errorf("could not find a local nor a remote checksum for plugin %q %q", pr.Identifier, pr.VersionConstraints)
+
+	versions := pr.matchingVersions(getters, opts.BinaryInstallationOptions)
 
 	// Here we want to try every relese in order, starting from the highest one
 	// that matches the requirements.
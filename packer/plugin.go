@@ -25,6 +25,15 @@ type PluginConfig struct {
 	PostProcessors     PostProcessorSet
 	DataSources        DatasourceSet
 
+	// PluginMirror is the folder or the URL of a mirror of the plugin
+	// releases, used by packer init instead of the releases of the plugins.
+	PluginMirror string
+
+	// Reattach are the addresses of running plugins to use for some
+	// components instead of the installed plugins, by kind and name of
+	// component, like builder.happycloud. See ParseReattachPlugins.
+	Reattach map[string]ReattachConfig
+
 	// Redirects are only set when a plugin was completely moved out; they allow
 	// telling where a plugin has moved by checking if a known component of this
 	// plugin is used. For example implicitly require the
@@ -118,6 +127,9 @@ func (c *PluginConfig) Discover() error {
 		}
 	}
 
+	// Running plugins to reattach to take precedence over everything.
+	c.setReattachedComponents()
+
 	return nil
 }
 
@@ -328,6 +340,8 @@ func (c *PluginConfig) DiscoverMultiPlugin(pluginName, pluginPath string) error
 		log.Printf("found external %v datasource from %s plugin", desc.Datasources, pluginName)
 	}
 
+	c.setReattachedComponents()
+
 	return nil
 }
 
@@ -66,6 +66,11 @@ type PluginClientConfig struct {
 	// If non-nil, then the stderr of the client will be written to here
 	// (as well as the log).
 	Stderr io.Writer
+
+	// Reattach is the address of a plugin that is already running, for
+	// example in a debugger. When set, Cmd is not started and the client
+	// connects to this address instead.
+	Reattach *ReattachConfig
 }
 
 // This makes sure all the managed subprocesses are killed and properly
@@ -192,7 +197,7 @@ func (c *PluginClient) Datasource() (packersdk.Datasource, error) {
 func (c *PluginClient) Kill() {
 	cmd := c.config.Cmd
 
-	if cmd.Process == nil {
+	if cmd == nil || cmd.Process == nil {
 		return
 	}
 
@@ -216,6 +221,16 @@ func (c *PluginClient) Start() (net.Addr, error) {
 		return c.address, nil
 	}
 
+	if c.config.Reattach != nil {
+		addr, err := c.config.Reattach.addr()
+		if err != nil {
+			return nil, err
+		}
+		c.address = addr
+		log.Printf("Reattaching to the %s plugin at %s", c.config.Reattach.Network, c.address)
+		return c.address, nil
+	}
+
 	c.doneLogging = make(chan struct{})
 
 	env := []string{
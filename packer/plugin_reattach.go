@@ -0,0 +1,109 @@
+package packer
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"sort"
+	"strings"
+
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+)
+
+// ReattachConfig is the address of a plugin started outside of Packer, for
+// example in a debugger, as printed by the plugin when it starts:
+//
+//	5|0|tcp|127.0.0.1:10000
+type ReattachConfig struct {
+	// Network is tcp or unix
+	Network string
+	Addr    string
+}
+
+func (r *ReattachConfig) addr() (net.Addr, error) {
+	switch r.Network {
+	case "tcp":
+		addr, err := net.ResolveTCPAddr("tcp", r.Addr)
+		if err != nil {
+			return nil, err
+		}
+		return addr, nil
+	case "unix":
+		addr, err := net.ResolveUnixAddr("unix", r.Addr)
+		if err != nil {
+			return nil, err
+		}
+		return addr, nil
+	default:
+		return nil, fmt.Errorf("Unknown address type: %s", r.Network)
+	}
+}
+
+// ParseReattachPlugins parses the value of the PACKER_REATTACH_PLUGINS
+// environment variable: a json object of the addresses of running plugins, by
+// kind and name of the component they serve:
+//
+//	{"builder.happycloud": {"Network": "tcp", "Addr": "127.0.0.1:10000"}}
+//
+// The kinds are builder, provisioner, post-processor and datasource.
+func ParseReattachPlugins(s string) (map[string]ReattachConfig, error) {
+	var reattach map[string]ReattachConfig
+	if err := json.Unmarshal([]byte(s), &reattach); err != nil {
+		return nil, fmt.Errorf("could not parse the plugins to reattach to: %w", err)
+	}
+	for key, r := range reattach {
+		kind := strings.SplitN(key, ".", 2)[0]
+		switch kind {
+		case "builder", "provisioner", "post-processor", "datasource":
+		default:
+			return nil, fmt.Errorf("%q: unknown component kind %q, expected "+
+				"builder, provisioner, post-processor or datasource", key, kind)
+		}
+		if !strings.Contains(key, ".") {
+			return nil, fmt.Errorf("%q: expected the kind and the name of a component, like %s.happycloud", key, kind)
+		}
+		if _, err := r.addr(); err != nil {
+			return nil, fmt.Errorf("%q: %w", key, err)
+		}
+	}
+	return reattach, nil
+}
+
+// setReattachedComponents makes the components of c.Reattach use their
+// running plugin, instead of the installed one.
+func (c *PluginConfig) setReattachedComponents() {
+	keys := make([]string, 0, len(c.Reattach))
+	for key := range c.Reattach {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		reattach := c.Reattach[key]
+		client := func() *PluginClient {
+			return NewClient(&PluginClientConfig{Reattach: &reattach})
+		}
+		parts := strings.SplitN(key, ".", 2)
+		kind, name := parts[0], parts[1]
+		switch kind {
+		case "builder":
+			c.Builders.Set(name, func() (packersdk.Builder, error) {
+				return client().Builder()
+			})
+		case "provisioner":
+			c.Provisioners.Set(name, func() (packersdk.Provisioner, error) {
+				return client().Provisioner()
+			})
+		case "post-processor":
+			c.PostProcessors.Set(name, func() (packersdk.PostProcessor, error) {
+				return client().PostProcessor()
+			})
+		case "datasource":
+			c.DataSources.Set(name, func() (packersdk.Datasource, error) {
+				return client().Datasource()
+			})
+		}
+		log.Printf("[INFO] using the running plugin at %s for %s", reattach.Addr, key)
+	}
+}
@@ -0,0 +1,81 @@
+package packer
+
+import (
+	"net"
+	"testing"
+)
+
+func TestParseReattachPlugins(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{"builder", `{"builder.happycloud": {"Network": "tcp", "Addr": "127.0.0.1:10000"}}`, false},
+		{"several kinds", `{
+			"datasource.happycloud-image": {"Network": "tcp", "Addr": "127.0.0.1:10000"},
+			"post-processor.happycloud-import": {"Network": "unix", "Addr": "/tmp/plugin.sock"}
+		}`, false},
+		{"invalid json", `{"builder.happycloud": `, true},
+		{"unknown kind", `{"hook.happycloud": {"Network": "tcp", "Addr": "127.0.0.1:10000"}}`, true},
+		{"no name", `{"builder": {"Network": "tcp", "Addr": "127.0.0.1:10000"}}`, true},
+		{"unknown network", `{"builder.happycloud": {"Network": "udp", "Addr": "127.0.0.1:10000"}}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseReattachPlugins(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseReattachPlugins() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClient_Reattach(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	c := NewClient(&PluginClientConfig{
+		Reattach: &ReattachConfig{Network: "tcp", Addr: l.Addr().String()},
+	})
+	addr, err := c.Start()
+	if err != nil {
+		t.Fatalf("err should be nil, got %s", err)
+	}
+	if addr.String() != l.Addr().String() {
+		t.Fatalf("bad: %#v", addr)
+	}
+
+	// The running plugin is not stopped
+	c.Kill()
+	if c.Exited() {
+		t.Fatal("should not say client has exited")
+	}
+}
+
+func TestPluginConfig_setReattachedComponents(t *testing.T) {
+	c := &PluginConfig{
+		Builders:       MapOfBuilder{},
+		Provisioners:   MapOfProvisioner{},
+		PostProcessors: MapOfPostProcessor{},
+		DataSources:    MapOfDatasource{},
+		Reattach: map[string]ReattachConfig{
+			"builder.happycloud":      {Network: "tcp", Addr: "127.0.0.1:10000"},
+			"datasource.happycloud-a": {Network: "tcp", Addr: "127.0.0.1:10001"},
+		},
+	}
+	c.setReattachedComponents()
+
+	if !c.Builders.Has("happycloud") {
+		t.Error("expected the happycloud builder to be set")
+	}
+	if !c.DataSources.Has("happycloud-a") {
+		t.Error("expected the happycloud-a data source to be set")
+	}
+	if c.Provisioners.Has("happycloud") {
+		t.Error("expected no happycloud provisioner")
+	}
+}
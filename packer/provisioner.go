@@ -24,6 +24,14 @@ type ProvisionHook struct {
 	// The provisioners to run as part of the hook. These should already
 	// be prepared (by calling Prepare) at some earlier stage.
 	Provisioners []*HookedProvisioner
+
+	// BuildName is the name of the build the provisioners are run for, and
+	// EventNotifier notifies hooks when each of them ends.
+	BuildName     string
+	EventNotifier *EventNotifier
+	// Events is the JSON output of the build, if any, where the start and
+	// the end of each provisioner are written.
+	Events *JSONUi
 }
 
 // BuilderDataCommonKeys is the list of common keys that all builder will
@@ -117,11 +125,22 @@ func (h *ProvisionHook) Run(ctx context.Context, name string, ui packersdk.Ui, c
 	}
 	for _, p := range h.Provisioners {
 		ts := CheckpointReporter.AddSpan(p.TypeName, "provisioner", p.Config)
+		if h.Events != nil {
+			h.Events.Event(JSONEvent{Type: "provisioner-start", Build: h.BuildName, Data: []string{p.TypeName}})
+		}
 
 		cast := CastDataToMap(data)
 		err := p.Provisioner.Provision(ctx, ui, comm, cast)
 
 		ts.End(err)
+		event := JSONEvent{Type: "provisioner-end", Build: h.BuildName, Data: []string{p.TypeName}}
+		if err != nil {
+			event.Code, event.Message = "provisioner_failed", err.Error()
+		}
+		if h.Events != nil {
+			h.Events.Event(event)
+		}
+		h.EventNotifier.Notify(event)
 		if err != nil {
 			return err
 		}
@@ -1,12 +1,16 @@
 package packer
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
 )
 
@@ -343,3 +347,51 @@ func TestRetriedProvisionerCancel(t *testing.T) {
 		t.Fatal("should have err")
 	}
 }
+
+func TestProvisionHook_events(t *testing.T) {
+	pA := &packersdk.MockProvisioner{}
+	pB := &packersdk.MockProvisioner{
+		ProvFunc: func(context.Context) error {
+			return errors.New("failed")
+		},
+	}
+
+	ui := testUi()
+	out := new(bytes.Buffer)
+	hook := &ProvisionHook{
+		Provisioners: []*HookedProvisioner{
+			{pA, nil, "shell"},
+			{pB, nil, "file"},
+		},
+		BuildName: "file.chocolate",
+		Events:    NewJSONUi(out),
+	}
+
+	if err := hook.Run(context.Background(), "foo", ui, new(packersdk.MockCommunicator), nil); err == nil {
+		t.Fatal("should error")
+	}
+
+	var events []string
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var event JSONEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("output line %q is not a JSON event: %s", line, err)
+		}
+		events = append(events, fmt.Sprintf("%s %s %s %s", event.Type, event.Build, event.Data, event.Code))
+	}
+	expected := []string{
+		"provisioner-start file.chocolate [shell] ",
+		"provisioner-end file.chocolate [shell] ",
+		"provisioner-start file.chocolate [file] ",
+		"provisioner-end file.chocolate [file] provisioner_failed",
+	}
+	if diff := cmp.Diff(expected, events); diff != "" {
+		t.Fatalf("unexpected events: %s", diff)
+	}
+
+	// The start and the end of provisioners are not written to the other
+	// outputs, like the machine-readable one.
+	if output := readWriter(ui); strings.Contains(output, "provisioner") {
+		t.Fatalf("unexpected output: %s", output)
+	}
+}
@@ -1,6 +1,8 @@
 package packer
 
 import (
+	"time"
+
 	"github.com/hashicorp/hcl/v2"
 	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
 	plugingetter "github.com/hashicorp/packer/packer/plugin-getter"
@@ -12,6 +14,23 @@ type GetBuildsOptions struct {
 	Except, Only []string
 	Debug, Force bool
 	OnError      string
+	// Timeout is the maximum duration of each build, unless its source sets
+	// its own. Zero means no timeout.
+	Timeout time.Duration
+	// Retries is the number of times a failed build is retried, unless its
+	// source sets its own retry policy.
+	Retries int
+}
+
+// Retry returns the retry policy of the builds set by the options.
+func (opts GetBuildsOptions) Retry() BuildRetry {
+	if opts.Retries < 1 {
+		return BuildRetry{}
+	}
+	return BuildRetry{
+		MaxAttempts: opts.Retries + 1,
+		Backoff:     DefaultBuildRetryBackoff,
+	}
 }
 
 type BuildGetter interface {
@@ -73,6 +92,8 @@ type ConfigFixer interface {
 
 type InspectConfigOptions struct {
 	packersdk.Ui
+	// JSON writes the configuration as JSON, see InspectOutput.
+	JSON bool
 }
 
 type ConfigInspector interface {
@@ -2,6 +2,7 @@ package packer
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -9,6 +10,7 @@ import (
 	"os"
 	"runtime"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 	"unicode"
@@ -119,18 +121,34 @@ func (u *TargetedUI) Ask(query string) (string, error) {
 }
 
 func (u *TargetedUI) Say(message string) {
+	if ui, ok := u.Ui.(*JSONUi); ok {
+		ui.Targeted(u.Target).Say(message)
+		return
+	}
 	u.Ui.Say(u.prefixLines(true, message))
 }
 
 func (u *TargetedUI) Message(message string) {
+	if ui, ok := u.Ui.(*JSONUi); ok {
+		ui.Targeted(u.Target).Message(message)
+		return
+	}
 	u.Ui.Message(u.prefixLines(false, message))
 }
 
 func (u *TargetedUI) Error(message string) {
+	if ui, ok := u.Ui.(*JSONUi); ok {
+		ui.Targeted(u.Target).Error(message)
+		return
+	}
 	u.Ui.Error(u.prefixLines(true, message))
 }
 
 func (u *TargetedUI) Machine(t string, args ...string) {
+	if ui, ok := u.Ui.(*JSONUi); ok {
+		ui.Targeted(u.Target).Machine(t, args...)
+		return
+	}
 	// Prefix in the target, then pass through
 	u.Ui.Machine(fmt.Sprintf("%s,%s", u.Target, t), args...)
 }
@@ -216,6 +234,124 @@ func (u *MachineReadableUi) TrackProgress(src string, currentSize, totalSize int
 	return u.PB.TrackProgress(src, currentSize, totalSize, stream)
 }
 
+// JSONEvent is an event of the JSON output of packer, written on its own
+// line.
+type JSONEvent struct {
+	Timestamp string `json:"@timestamp"`
+	// The type of the event: "ui" for the messages of the UI, "build-start",
+	// "build-end", "artifact" or "error" for the events of a build, or the
+	// category of a machine-readable message.
+	Type string `json:"type"`
+	// The name of the build the event is about, if any.
+	Build string `json:"build,omitempty"`
+	// The level of a ui message: "say", "message" or "error".
+	Level   string `json:"level,omitempty"`
+	Message string `json:"message,omitempty"`
+	// A code identifying the kind of an error.
+	Code string `json:"code,omitempty"`
+	// The duration of a build, in seconds.
+	Duration float64       `json:"duration,omitempty"`
+	Artifact *JSONArtifact `json:"artifact,omitempty"`
+	// The arguments of a machine-readable message.
+	Data []string `json:"data,omitempty"`
+}
+
+// JSONArtifact describes an artifact in a JSON event.
+type JSONArtifact struct {
+	BuilderId string   `json:"builder_id"`
+	Id        string   `json:"id"`
+	String    string   `json:"string"`
+	Files     []string `json:"files"`
+}
+
+// JSONUi is a UI that writes every message and event as a line of JSON to the
+// given Writer, so that the output of a build can be parsed, e.g. by CI
+// systems.
+type JSONUi struct {
+	Writer io.Writer
+	PB     packersdk.NoopProgressTracker
+
+	target string
+	l      *sync.Mutex
+}
+
+var _ packersdk.Ui = new(JSONUi)
+
+// NewJSONUi returns a JSONUi writing to w.
+func NewJSONUi(w io.Writer) *JSONUi {
+	return &JSONUi{Writer: w, l: new(sync.Mutex)}
+}
+
+// Targeted returns a UI writing to the same Writer, which events are about
+// the build named target.
+func (u *JSONUi) Targeted(target string) *JSONUi {
+	return &JSONUi{Writer: u.Writer, target: target, l: u.l}
+}
+
+func (u *JSONUi) Ask(query string) (string, error) {
+	return "", errors.New("json UI can't ask")
+}
+
+func (u *JSONUi) Say(message string) {
+	u.Event(JSONEvent{Type: "ui", Level: "say", Message: message})
+}
+
+func (u *JSONUi) Message(message string) {
+	u.Event(JSONEvent{Type: "ui", Level: "message", Message: message})
+}
+
+func (u *JSONUi) Error(message string) {
+	u.Event(JSONEvent{Type: "ui", Level: "error", Message: message})
+}
+
+func (u *JSONUi) Machine(category string, args ...string) {
+	event := JSONEvent{Type: category}
+	if commaIdx := strings.Index(category, ","); commaIdx > -1 {
+		event.Build = category[0:commaIdx]
+		event.Type = category[commaIdx+1:]
+	}
+	event.Data = make([]string, len(args))
+	for i := range args {
+		event.Data[i] = packersdk.LogSecretFilter.FilterString(args[i])
+	}
+	u.Event(event)
+}
+
+// Event writes the event, setting its timestamp and, when it is not set, the
+// build it is about.
+func (u *JSONUi) Event(event JSONEvent) {
+	event.Timestamp = time.Now().UTC().Format(time.RFC3339Nano)
+	if event.Build == "" {
+		event.Build = u.target
+	}
+	event.Message = packersdk.LogSecretFilter.FilterString(event.Message)
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("[ERR] json UI: %s", err)
+		return
+	}
+	line = append(line, '\n')
+
+	if u.l != nil {
+		u.l.Lock()
+		defer u.l.Unlock()
+	}
+	if _, err := u.Writer.Write(line); err != nil {
+		if err == syscall.EPIPE || strings.Contains(err.Error(), "broken pipe") {
+			// Ignore epipe errors because that just means that the file
+			// is probably closed or going to /dev/null or something.
+		} else {
+			panic(err)
+		}
+	}
+	log.Printf("%s", line)
+}
+
+func (u *JSONUi) TrackProgress(src string, currentSize, totalSize int64, stream io.ReadCloser) (body io.ReadCloser) {
+	return u.PB.TrackProgress(src, currentSize, totalSize, stream)
+}
+
 // TimestampedUi is a UI that wraps another UI implementation and
 // prefixes each message with an RFC3339 timestamp
 type TimestampedUi struct {
@@ -2,6 +2,7 @@ package packer
 
 import (
 	"bytes"
+	"encoding/json"
 	"os"
 	"strings"
 	"testing"
@@ -298,3 +299,36 @@ func TestMachineReadableUi(t *testing.T) {
 		t.Fatalf("bad: %#v", data)
 	}
 }
+
+func TestJSONUi(t *testing.T) {
+	buf := new(bytes.Buffer)
+	ui := &TargetedUI{
+		Target: "foo",
+		Ui:     NewJSONUi(buf),
+	}
+
+	ui.Say("hello\nworld")
+	ui.Machine("artifact-count", "1")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected one line per event, got %q", buf.String())
+	}
+
+	var say, machine JSONEvent
+	if err := json.Unmarshal([]byte(lines[0]), &say); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if say.Timestamp == "" || say.Type != "ui" || say.Level != "say" ||
+		say.Build != "foo" || say.Message != "hello\nworld" {
+		t.Fatalf("bad say event: %#v", say)
+	}
+
+	if err := json.Unmarshal([]byte(lines[1]), &machine); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if machine.Type != "artifact-count" || machine.Build != "foo" ||
+		len(machine.Data) != 1 || machine.Data[0] != "1" {
+		t.Fatalf("bad machine event: %#v", machine)
+	}
+}
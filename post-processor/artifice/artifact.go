@@ -11,8 +11,14 @@ const BuilderId = "packer.post-processor.artifice"
 
 type Artifact struct {
 	files []string
+
+	// metadata are the values returned by State, also available as the
+	// generated data of the artifact.
+	metadata map[string]string
 }
 
+// NewArtifact creates an artifact of the files matching the glob patterns of
+// files. Every pattern must match at least one file.
 func NewArtifact(files []string) (*Artifact, error) {
 	artifact := &Artifact{}
 	for _, f := range files {
@@ -20,6 +26,9 @@ func NewArtifact(files []string) (*Artifact, error) {
 		if err != nil {
 			return nil, err
 		}
+		if len(globfiles) == 0 {
+			return nil, fmt.Errorf("No files found matching %q", f)
+		}
 		for _, gf := range globfiles {
 			if _, err := os.Stat(gf); err != nil {
 				return nil, err
@@ -48,6 +57,19 @@ func (a *Artifact) String() string {
 }
 
 func (a *Artifact) State(name string) interface{} {
+	if name == "generated_data" {
+		if len(a.metadata) == 0 {
+			return nil
+		}
+		data := make(map[interface{}]interface{}, len(a.metadata))
+		for k, v := range a.metadata {
+			data[k] = v
+		}
+		return data
+	}
+	if v, ok := a.metadata[name]; ok {
+		return v
+	}
 	return nil
 }
 
@@ -5,6 +5,7 @@ package artifice
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2/hcldec"
@@ -23,8 +24,9 @@ import (
 type Config struct {
 	common.PackerConfig `mapstructure:",squash"`
 
-	Files []string `mapstructure:"files"`
-	Keep  bool     `mapstructure:"keep_input_artifact"`
+	Files    []string          `mapstructure:"files"`
+	Keep     bool              `mapstructure:"keep_input_artifact"`
+	Metadata map[string]string `mapstructure:"metadata"`
 
 	ctx interpolate.Context
 }
@@ -52,6 +54,16 @@ func (p *PostProcessor) Configure(raws ...interface{}) error {
 		return fmt.Errorf("No files specified in artifice configuration")
 	}
 
+	for _, pattern := range p.config.Files {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("Invalid pattern %q in files: %s", pattern, err)
+		}
+	}
+
+	if _, ok := p.config.Metadata["generated_data"]; ok {
+		return fmt.Errorf("generated_data is a reserved metadata key")
+	}
+
 	return nil
 }
 
@@ -60,8 +72,12 @@ func (p *PostProcessor) PostProcess(ctx context.Context, ui packersdk.Ui, artifa
 		ui.Say(fmt.Sprintf("Discarding files from artifact: %s", strings.Join(artifact.Files(), ", ")))
 	}
 
-	artifact, err := NewArtifact(p.config.Files)
-	ui.Say(fmt.Sprintf("Using these artifact files: %s", strings.Join(artifact.Files(), ", ")))
+	newArtifact, err := NewArtifact(p.config.Files)
+	if err != nil {
+		return nil, false, false, err
+	}
+	newArtifact.metadata = p.config.Metadata
+	ui.Say(fmt.Sprintf("Using these artifact files: %s", strings.Join(newArtifact.Files(), ", ")))
 
-	return artifact, true, false, err
+	return newArtifact, true, false, nil
 }
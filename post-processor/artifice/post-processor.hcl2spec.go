@@ -20,6 +20,7 @@ type FlatConfig struct {
 	PackerSensitiveVars []string          `mapstructure:"packer_sensitive_variables" cty:"packer_sensitive_variables" hcl:"packer_sensitive_variables"`
 	Files               []string          `mapstructure:"files" cty:"files" hcl:"files"`
 	Keep                *bool             `mapstructure:"keep_input_artifact" cty:"keep_input_artifact" hcl:"keep_input_artifact"`
+	Metadata            map[string]string `mapstructure:"metadata" cty:"metadata" hcl:"metadata"`
 }
 
 // FlatMapstructure returns a new FlatConfig.
@@ -44,6 +45,7 @@ func (*FlatConfig) HCL2Spec() map[string]hcldec.Spec {
 		"packer_sensitive_variables": &hcldec.AttrSpec{Name: "packer_sensitive_variables", Type: cty.List(cty.String), Required: false},
 		"files":                      &hcldec.AttrSpec{Name: "files", Type: cty.List(cty.String), Required: false},
 		"keep_input_artifact":        &hcldec.AttrSpec{Name: "keep_input_artifact", Type: cty.Bool, Required: false},
+		"metadata":                   &hcldec.AttrSpec{Name: "metadata", Type: cty.Map(cty.String), Required: false},
 	}
 	return s
 }
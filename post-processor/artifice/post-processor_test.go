@@ -0,0 +1,94 @@
+package artifice
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+)
+
+func TestPostProcessor_Configure(t *testing.T) {
+	var p PostProcessor
+	if err := p.Configure(map[string]interface{}{}); err == nil {
+		t.Fatal("should fail without files")
+	}
+
+	p = PostProcessor{}
+	if err := p.Configure(map[string]interface{}{"files": []string{"[a-"}}); err == nil {
+		t.Fatal("should fail with an invalid pattern")
+	}
+
+	p = PostProcessor{}
+	err := p.Configure(map[string]interface{}{
+		"files":    []string{"*.txt"},
+		"metadata": map[string]string{"generated_data": "foo"},
+	})
+	if err == nil {
+		t.Fatal("should fail with a reserved metadata key")
+	}
+}
+
+func TestPostProcessor_PostProcess(t *testing.T) {
+	dir, err := ioutil.TempDir("", "packer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"a.txt", "b.txt", "c.bin"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var p PostProcessor
+	err = p.Configure(map[string]interface{}{
+		"files":    []string{filepath.Join(dir, "*.txt")},
+		"metadata": map[string]string{"version": "1.2.3"},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	artifact, _, _, err := p.PostProcess(context.Background(), packersdk.TestUi(t), &packersdk.MockArtifact{})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	files := artifact.Files()
+	sort.Strings(files)
+	if len(files) != 2 || files[0] != filepath.Join(dir, "a.txt") || files[1] != filepath.Join(dir, "b.txt") {
+		t.Fatalf("bad files: %v", files)
+	}
+	if artifact.State("version") != "1.2.3" {
+		t.Fatalf("bad state: %#v", artifact.State("version"))
+	}
+	generatedData := artifact.State("generated_data").(map[interface{}]interface{})
+	if generatedData["version"] != "1.2.3" {
+		t.Fatalf("bad generated data: %#v", generatedData)
+	}
+}
+
+func TestPostProcessor_PostProcess_missingFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "packer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var p PostProcessor
+	err = p.Configure(map[string]interface{}{
+		"files": []string{filepath.Join(dir, "*.txt")},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if _, _, _, err := p.PostProcess(context.Background(), packersdk.TestUi(t), &packersdk.MockArtifact{}); err == nil {
+		t.Fatal("should fail when no file matches")
+	}
+}
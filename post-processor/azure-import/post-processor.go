@@ -0,0 +1,364 @@
+//go:generate packer-sdc struct-markdown
+//go:generate packer-sdc mapstructure-to-hcl2 -type Config
+
+package azureimport
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-12-01/compute"
+	armstorage "github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2017-10-01/storage"
+	"github.com/Azure/azure-sdk-for-go/storage"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/hashicorp/hcl/v2/hcldec"
+	"github.com/hashicorp/packer-plugin-sdk/common"
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+	"github.com/hashicorp/packer-plugin-sdk/template/config"
+	"github.com/hashicorp/packer-plugin-sdk/template/interpolate"
+	"github.com/hashicorp/packer/builder/azure/chroot"
+	azcommon "github.com/hashicorp/packer/builder/azure/common"
+	"github.com/hashicorp/packer/builder/azure/common/client"
+	"github.com/mitchellh/mapstructure"
+)
+
+const BuilderId = "packer.post-processor.azure-import"
+
+type Config struct {
+	common.PackerConfig `mapstructure:",squash"`
+
+	ClientConfig client.Config `mapstructure:",squash"`
+
+	// The resource group of the storage account the VHD is uploaded to.
+	ResourceGroupName string `mapstructure:"resource_group_name" required:"true"`
+	// The storage account the VHD is uploaded to. It must be in the location
+	// the image is created in.
+	StorageAccount string `mapstructure:"storage_account" required:"true"`
+	// The container the VHD is uploaded to. It is created if it does not
+	// exist. Defaults to `packer-import`.
+	StorageContainer string `mapstructure:"storage_container" required:"false"`
+	// The name of the page blob the VHD is uploaded to. This is a [template
+	// engine](/docs/templates/legacy_json_templates/engine). Defaults to
+	// `packer-import-{{timestamp}}.vhd`.
+	BlobName string `mapstructure:"blob_name" required:"false"`
+	// Whether to keep the uploaded VHD once the image is created. Defaults to
+	// `false`.
+	SkipClean bool `mapstructure:"skip_clean" required:"false"`
+
+	// The name of the Managed Image to create from the VHD.
+	ManagedImageName string `mapstructure:"managed_image_name" required:"true"`
+	// The resource group of the Managed Image. Defaults to
+	// `resource_group_name`.
+	ManagedImageResourceGroupName string `mapstructure:"managed_image_resource_group_name" required:"false"`
+	// The storage account type of the OS disk of the Managed Image, such as
+	// `Standard_LRS` or `Premium_LRS`. Defaults to `Standard_LRS`.
+	ManagedImageStorageAccountType string `mapstructure:"managed_image_storage_account_type" required:"false"`
+	// The operating system of the VHD, `Linux` or `Windows`.
+	OSType string `mapstructure:"os_type" required:"true"`
+	// The Hyper-V generation of the image, `V1` or `V2`. Defaults to `V1`.
+	HyperVGeneration string `mapstructure:"image_hyperv_generation" required:"false"`
+
+	// A Shared Image Gallery image version to publish the Managed Image to.
+	// The gallery image definition must exist already.
+	SharedImageGalleryDestination chroot.SharedImageGalleryDestination `mapstructure:"shared_image_gallery_destination" required:"false"`
+
+	ctx interpolate.Context
+}
+
+type PostProcessor struct {
+	config  Config
+	withSIG bool
+}
+
+func (p *PostProcessor) ConfigSpec() hcldec.ObjectSpec { return p.config.FlatMapstructure().HCL2Spec() }
+
+func (p *PostProcessor) Configure(raws ...interface{}) error {
+	var md mapstructure.Metadata
+	err := config.Decode(&p.config, &config.DecodeOpts{
+		Metadata:           &md,
+		PluginType:         BuilderId,
+		Interpolate:        true,
+		InterpolateContext: &p.config.ctx,
+		InterpolateFilter: &interpolate.RenderFilter{
+			Exclude: []string{"blob_name"},
+		},
+	}, raws...)
+	if err != nil {
+		return err
+	}
+
+	if err := p.config.ClientConfig.SetDefaultValues(); err != nil {
+		return err
+	}
+
+	if p.config.StorageContainer == "" {
+		p.config.StorageContainer = "packer-import"
+	}
+	if p.config.BlobName == "" {
+		p.config.BlobName = "packer-import-{{timestamp}}.vhd"
+	}
+	if p.config.ManagedImageResourceGroupName == "" {
+		p.config.ManagedImageResourceGroupName = p.config.ResourceGroupName
+	}
+	if p.config.ManagedImageStorageAccountType == "" {
+		p.config.ManagedImageStorageAccountType = string(compute.StorageAccountTypesStandardLRS)
+	}
+	if p.config.HyperVGeneration == "" {
+		p.config.HyperVGeneration = string(compute.HyperVGenerationTypesV1)
+	}
+
+	errs := new(packersdk.MultiError)
+	p.config.ClientConfig.Validate(errs)
+
+	if err = interpolate.Validate(p.config.BlobName, &p.config.ctx); err != nil {
+		errs = packersdk.MultiErrorAppend(
+			errs, fmt.Errorf("Error parsing blob_name template: %s", err))
+	}
+
+	requiredArgs := map[string]*string{
+		"resource_group_name": &p.config.ResourceGroupName,
+		"storage_account":     &p.config.StorageAccount,
+		"managed_image_name":  &p.config.ManagedImageName,
+		"os_type":             &p.config.OSType,
+	}
+	for key, ptr := range requiredArgs {
+		if *ptr == "" {
+			errs = packersdk.MultiErrorAppend(
+				errs, fmt.Errorf("%s must be set", key))
+		}
+	}
+
+	switch compute.OperatingSystemTypes(p.config.OSType) {
+	case "", compute.Linux, compute.Windows:
+	default:
+		errs = packersdk.MultiErrorAppend(
+			errs, fmt.Errorf("os_type must be one of %q or %q, got %q", compute.Linux, compute.Windows, p.config.OSType))
+	}
+
+	switch compute.HyperVGenerationTypes(p.config.HyperVGeneration) {
+	case compute.HyperVGenerationTypesV1, compute.HyperVGenerationTypesV2:
+	default:
+		errs = packersdk.MultiErrorAppend(
+			errs, fmt.Errorf("image_hyperv_generation must be one of %q or %q, got %q",
+				compute.HyperVGenerationTypesV1, compute.HyperVGenerationTypesV2, p.config.HyperVGeneration))
+	}
+
+	if azcommon.StringsContains(md.Keys, "shared_image_gallery_destination") {
+		p.withSIG = true
+		e, w := p.config.SharedImageGalleryDestination.Validate("shared_image_gallery_destination")
+		errs = packersdk.MultiErrorAppend(errs, e...)
+		for _, warn := range w {
+			log.Printf("[WARN] %s", warn)
+		}
+	}
+
+	if len(errs.Errors) > 0 {
+		return errs
+	}
+
+	packersdk.LogSecretFilter.Set(p.config.ClientConfig.ClientSecret, p.config.ClientConfig.ClientJWT)
+	return nil
+}
+
+func (p *PostProcessor) PostProcess(ctx context.Context, ui packersdk.Ui, artifact packersdk.Artifact) (packersdk.Artifact, bool, bool, error) {
+	generatedData := artifact.State("generated_data")
+	if generatedData == nil {
+		// Make sure it's not a nil map so we can assign to it later.
+		generatedData = make(map[string]interface{})
+	}
+	p.config.ctx.Data = generatedData
+
+	blobName, err := interpolate.Render(p.config.BlobName, &p.config.ctx)
+	if err != nil {
+		return nil, false, false, fmt.Errorf("Error rendering blob_name template: %s", err)
+	}
+	log.Printf("Rendered blob_name as %s", blobName)
+
+	source, err := extractVHDArtifact(artifact.Files())
+	if err != nil {
+		return nil, false, false, err
+	}
+
+	f, err := os.Open(source)
+	if err != nil {
+		return nil, false, false, fmt.Errorf("Failed to open %s: %s", source, err)
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, false, false, fmt.Errorf("Failed to stat %s: %s", source, err)
+	}
+	if err := checkFixedVHD(f, fi.Size()); err != nil {
+		return nil, false, false, fmt.Errorf("%s can not be imported: %s", source, err)
+	}
+
+	if err := p.config.ClientConfig.FillParameters(); err != nil {
+		return nil, false, false, fmt.Errorf("error setting Azure client defaults: %v", err)
+	}
+	azcli, err := client.New(p.config.ClientConfig, ui.Say)
+	if err != nil {
+		return nil, false, false, fmt.Errorf("error creating Azure client: %v", err)
+	}
+
+	location, blobService, err := p.storageAccount(ctx, ui)
+	if err != nil {
+		return nil, false, false, err
+	}
+
+	container := blobService.GetContainerReference(p.config.StorageContainer)
+	if _, err := container.CreateIfNotExists(&storage.CreateContainerOptions{Access: storage.ContainerAccessTypePrivate}); err != nil {
+		return nil, false, false, fmt.Errorf("error creating container %s: %s", p.config.StorageContainer, err)
+	}
+	blob := container.GetBlobReference(blobName)
+
+	ui.Say(fmt.Sprintf("Uploading %s to %s", source, blob.GetURL()))
+	progress := ui.TrackProgress(source, 0, fi.Size(), f)
+	err = uploadPageBlob(ctx, blob, progress, fi.Size())
+	progress.Close()
+	if err != nil {
+		return nil, false, false, fmt.Errorf("Failed to upload %s: %s", source, err)
+	}
+
+	if !p.config.SkipClean {
+		defer func() {
+			ui.Say(fmt.Sprintf("Deleting import source %s", blob.GetURL()))
+			if err := blob.Delete(nil); err != nil {
+				ui.Error(fmt.Sprintf("Failed to delete %s: %s", blob.GetURL(), err))
+			}
+		}()
+	}
+
+	imageID := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/images/%s",
+		azcli.SubscriptionID(), p.config.ManagedImageResourceGroupName, p.config.ManagedImageName)
+	ui.Say(fmt.Sprintf("Creating image %s", imageID))
+	imageFuture, err := azcli.ImagesClient().CreateOrUpdate(ctx,
+		p.config.ManagedImageResourceGroupName, p.config.ManagedImageName,
+		buildImage(p.config, location, blob.GetURL()))
+	if err == nil {
+		err = imageFuture.WaitForCompletionRef(ctx, azcli.PollClient())
+	}
+	if err != nil {
+		return nil, false, false, fmt.Errorf("error creating image '%s': %v", imageID, err)
+	}
+	resources := []string{imageID}
+
+	if p.withSIG {
+		dest := p.config.SharedImageGalleryDestination
+		versionID := dest.ResourceID(azcli.SubscriptionID())
+		ui.Say(fmt.Sprintf("Creating image version %s", versionID))
+		versionFuture, err := azcli.GalleryImageVersionsClient().CreateOrUpdate(ctx,
+			dest.ResourceGroup, dest.GalleryName, dest.ImageName, dest.ImageVersion,
+			buildImageVersion(dest, location, imageID))
+		if err == nil {
+			err = versionFuture.WaitForCompletionRef(ctx, azcli.PollClient())
+		}
+		if err != nil {
+			return nil, false, false, fmt.Errorf("error creating shared image version '%s': %v", versionID, err)
+		}
+		resources = append(resources, versionID)
+	}
+
+	return &azcommon.Artifact{
+		Resources:      resources,
+		BuilderIdValue: BuilderId,
+		AzureClientSet: azcli,
+		StateData:      map[string]interface{}{"generated_data": generatedData},
+	}, false, false, nil
+}
+
+// storageAccount looks up the location and the keys of the storage account
+// and returns the location and a blob client for it.
+func (p *PostProcessor) storageAccount(ctx context.Context, ui packersdk.Ui) (string, storage.BlobStorageClient, error) {
+	cloud := p.config.ClientConfig.CloudEnvironment()
+	token, err := p.config.ClientConfig.GetServicePrincipalToken(ui.Say, cloud.ResourceManagerEndpoint)
+	if err != nil {
+		return "", storage.BlobStorageClient{}, err
+	}
+
+	accounts := armstorage.NewAccountsClientWithBaseURI(cloud.ResourceManagerEndpoint, p.config.ClientConfig.SubscriptionID)
+	accounts.Authorizer = autorest.NewBearerAuthorizer(token)
+
+	account, err := accounts.GetProperties(ctx, p.config.ResourceGroupName, p.config.StorageAccount)
+	if err != nil {
+		return "", storage.BlobStorageClient{}, fmt.Errorf("error looking up storage account %s: %s", p.config.StorageAccount, err)
+	}
+
+	keys, err := accounts.ListKeys(ctx, p.config.ResourceGroupName, p.config.StorageAccount)
+	if err != nil {
+		return "", storage.BlobStorageClient{}, fmt.Errorf("error listing the keys of storage account %s: %s", p.config.StorageAccount, err)
+	}
+	if keys.Keys == nil || len(*keys.Keys) == 0 {
+		return "", storage.BlobStorageClient{}, fmt.Errorf("storage account %s has no keys", p.config.StorageAccount)
+	}
+
+	storageClient, err := storage.NewClient(
+		p.config.StorageAccount,
+		*(*keys.Keys)[0].Value,
+		cloud.StorageEndpointSuffix,
+		storage.DefaultAPIVersion,
+		true /*useHttps*/)
+	if err != nil {
+		return "", storage.BlobStorageClient{}, err
+	}
+
+	return to.String(account.Location), storageClient.GetBlobService(), nil
+}
+
+func buildImage(c Config, location, blobURI string) compute.Image {
+	return compute.Image{
+		Location: to.StringPtr(location),
+		ImageProperties: &compute.ImageProperties{
+			StorageProfile: &compute.ImageStorageProfile{
+				OsDisk: &compute.ImageOSDisk{
+					OsType:             compute.OperatingSystemTypes(c.OSType),
+					OsState:            compute.Generalized,
+					BlobURI:            to.StringPtr(blobURI),
+					StorageAccountType: compute.StorageAccountTypes(c.ManagedImageStorageAccountType),
+				},
+			},
+			HyperVGeneration: compute.HyperVGenerationTypes(c.HyperVGeneration),
+		},
+	}
+}
+
+func buildImageVersion(dest chroot.SharedImageGalleryDestination, location, imageID string) compute.GalleryImageVersion {
+	var targetRegions []compute.TargetRegion
+	for _, tr := range dest.TargetRegions {
+		targetRegions = append(targetRegions, compute.TargetRegion{
+			Name:                 to.StringPtr(tr.Name),
+			RegionalReplicaCount: to.Int32Ptr(tr.ReplicaCount),
+			StorageAccountType:   compute.StorageAccountType(tr.StorageAccountType),
+		})
+	}
+
+	return compute.GalleryImageVersion{
+		Location: to.StringPtr(location),
+		GalleryImageVersionProperties: &compute.GalleryImageVersionProperties{
+			StorageProfile: &compute.GalleryImageVersionStorageProfile{
+				Source: &compute.GalleryArtifactVersionSource{ID: to.StringPtr(imageID)},
+			},
+			PublishingProfile: &compute.GalleryImageVersionPublishingProfile{
+				TargetRegions:     &targetRegions,
+				ExcludeFromLatest: to.BoolPtr(dest.ExcludeFromLatest),
+			},
+		},
+	}
+}
+
+func extractVHDArtifact(artifacts []string) (string, error) {
+	if len(artifacts) == 0 {
+		return "", fmt.Errorf("no artifacts were provided")
+	}
+
+	for _, path := range artifacts {
+		if strings.HasSuffix(strings.ToLower(path), ".vhd") {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("no VHD file found in the artifact")
+}
@@ -0,0 +1,113 @@
+package azureimport
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-12-01/compute"
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+	"github.com/hashicorp/packer/builder/azure/chroot"
+)
+
+func TestPostProcessor_ImplementsPostProcessor(t *testing.T) {
+	var _ packersdk.PostProcessor = new(PostProcessor)
+}
+
+func testConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"use_azure_cli_auth":  true,
+		"resource_group_name": "rg",
+		"storage_account":     "account",
+		"managed_image_name":  "image",
+		"os_type":             "Linux",
+	}
+}
+
+func TestPostProcessor_Configure(t *testing.T) {
+	var p PostProcessor
+	if err := p.Configure(testConfig()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if p.config.ManagedImageResourceGroupName != "rg" {
+		t.Errorf("expected managed_image_resource_group_name to default to rg, got %q", p.config.ManagedImageResourceGroupName)
+	}
+	if p.config.HyperVGeneration != "V1" {
+		t.Errorf("expected image_hyperv_generation to default to V1, got %q", p.config.HyperVGeneration)
+	}
+	if p.withSIG {
+		t.Error("expected no shared image gallery destination")
+	}
+}
+
+func TestPostProcessor_Configure_errors(t *testing.T) {
+	tt := map[string]struct {
+		key   string
+		value interface{}
+	}{
+		"os_type must be one of":                  {"os_type", "Plan9"},
+		"os_type must be set":                     {"os_type", ""},
+		"image_hyperv_generation must be one of":  {"image_hyperv_generation", "V3"},
+		"storage_account must be set":             {"storage_account", ""},
+		"shared_image_gallery_destination.galler": {"shared_image_gallery_destination", map[string]interface{}{"resource_group": "rg"}},
+	}
+
+	for expected, tc := range tt {
+		config := testConfig()
+		config[tc.key] = tc.value
+
+		var p PostProcessor
+		err := p.Configure(config)
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error containing %q, got %v", expected, err)
+		}
+	}
+}
+
+func TestPostProcessor_buildImage(t *testing.T) {
+	c := Config{
+		OSType:                         "Windows",
+		ManagedImageStorageAccountType: "Premium_LRS",
+		HyperVGeneration:               "V2",
+	}
+
+	image := buildImage(c, "westeurope", "https://account.blob.core.windows.net/packer-import/disk.vhd")
+	osDisk := image.ImageProperties.StorageProfile.OsDisk
+
+	if *image.Location != "westeurope" {
+		t.Errorf("unexpected location %q", *image.Location)
+	}
+	if osDisk.OsType != compute.Windows || osDisk.OsState != compute.Generalized {
+		t.Errorf("unexpected os disk %#v", osDisk)
+	}
+	if *osDisk.BlobURI != "https://account.blob.core.windows.net/packer-import/disk.vhd" {
+		t.Errorf("unexpected blob uri %q", *osDisk.BlobURI)
+	}
+	if osDisk.StorageAccountType != compute.StorageAccountTypesPremiumLRS {
+		t.Errorf("unexpected storage account type %q", osDisk.StorageAccountType)
+	}
+	if image.ImageProperties.HyperVGeneration != compute.HyperVGenerationTypesV2 {
+		t.Errorf("unexpected hyper-v generation %q", image.ImageProperties.HyperVGeneration)
+	}
+}
+
+func TestPostProcessor_buildImageVersion(t *testing.T) {
+	dest := chroot.SharedImageGalleryDestination{
+		ResourceGroup: "rg",
+		GalleryName:   "gallery",
+		ImageName:     "image",
+		ImageVersion:  "1.0.0",
+		TargetRegions: []chroot.TargetRegion{{Name: "northeurope", ReplicaCount: 2}},
+	}
+
+	version := buildImageVersion(dest, "westeurope", "/subscriptions/1/resourceGroups/rg/providers/Microsoft.Compute/images/image")
+	props := version.GalleryImageVersionProperties
+
+	if *props.StorageProfile.Source.ID != "/subscriptions/1/resourceGroups/rg/providers/Microsoft.Compute/images/image" {
+		t.Errorf("unexpected source %q", *props.StorageProfile.Source.ID)
+	}
+	regions := *props.PublishingProfile.TargetRegions
+	if len(regions) != 1 || *regions[0].Name != "northeurope" || *regions[0].RegionalReplicaCount != 2 {
+		t.Errorf("unexpected target regions %#v", regions)
+	}
+}
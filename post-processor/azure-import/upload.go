@@ -0,0 +1,93 @@
+package azureimport
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"io"
+	"log"
+
+	"github.com/Azure/azure-sdk-for-go/storage"
+)
+
+// pageRangeSize is the largest range that can be written to a page blob in a
+// single request.
+const pageRangeSize = 4 * 1024 * 1024
+
+// pageBlob is the part of storage.Blob used to upload a VHD.
+type pageBlob interface {
+	PutPageBlob(options *storage.PutBlobOptions) error
+	WriteRange(blobRange storage.BlobRange, bytes io.Reader, options *storage.PutPageOptions) error
+	SetProperties(options *storage.SetBlobPropertiesOptions) error
+}
+
+// uploadPageBlob creates a page blob of the given size and copies r to it.
+// Ranges that only contain zeros are skipped, as pages of a new page blob are
+// zeroed already, which makes uploading sparse disks much faster. The MD5 of
+// the whole content is set as the Content-MD5 of the blob once done, so it can
+// be verified when the blob is downloaded.
+func uploadPageBlob(ctx context.Context, blob *storage.Blob, r io.Reader, size int64) error {
+	blob.Properties.ContentLength = size
+	if err := blob.PutPageBlob(nil); err != nil {
+		return fmt.Errorf("error creating page blob: %s", err)
+	}
+
+	sum, err := writePages(ctx, blob, r, size)
+	if err != nil {
+		return err
+	}
+
+	blob.Properties.ContentMD5 = base64.StdEncoding.EncodeToString(sum.Sum(nil))
+	if err := blob.SetProperties(nil); err != nil {
+		return fmt.Errorf("error setting the Content-MD5 of the blob: %s", err)
+	}
+	return nil
+}
+
+func writePages(ctx context.Context, blob pageBlob, r io.Reader, size int64) (hash.Hash, error) {
+	sum := md5.New()
+	buf := make([]byte, pageRangeSize)
+	skipped := 0
+
+	for offset := int64(0); offset < size; {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		n, err := io.ReadFull(r, buf)
+		if err == io.ErrUnexpectedEOF || err == io.EOF {
+			if offset+int64(n) != size {
+				return nil, fmt.Errorf("unexpected end of file at offset %d", offset+int64(n))
+			}
+		} else if err != nil {
+			return nil, err
+		}
+		chunk := buf[:n]
+		sum.Write(chunk)
+
+		if isZero(chunk) {
+			skipped++
+		} else {
+			blobRange := storage.BlobRange{Start: uint64(offset), End: uint64(offset) + uint64(n) - 1}
+			if err := blob.WriteRange(blobRange, bytes.NewReader(chunk), nil); err != nil {
+				return nil, fmt.Errorf("error writing range %d-%d: %s", blobRange.Start, blobRange.End, err)
+			}
+		}
+		offset += int64(n)
+	}
+
+	log.Printf("Skipped %d empty ranges of %d bytes", skipped, pageRangeSize)
+	return sum, nil
+}
+
+func isZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
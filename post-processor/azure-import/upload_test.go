@@ -0,0 +1,75 @@
+package azureimport
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/storage"
+)
+
+type fakePageBlob struct {
+	content []byte
+	writes  []storage.BlobRange
+}
+
+func (b *fakePageBlob) PutPageBlob(*storage.PutBlobOptions) error { return nil }
+
+func (b *fakePageBlob) WriteRange(r storage.BlobRange, data io.Reader, _ *storage.PutPageOptions) error {
+	chunk, err := ioutil.ReadAll(data)
+	if err != nil {
+		return err
+	}
+	copy(b.content[r.Start:r.End+1], chunk)
+	b.writes = append(b.writes, r)
+	return nil
+}
+
+func (b *fakePageBlob) SetProperties(*storage.SetBlobPropertiesOptions) error { return nil }
+
+func TestWritePages(t *testing.T) {
+	// One full range of data, one empty range and a partial range of data.
+	size := 2*pageRangeSize + 1024
+	content := make([]byte, size)
+	content[0] = 1
+	content[size-1] = 2
+
+	blob := &fakePageBlob{content: make([]byte, size)}
+	sum, err := writePages(context.Background(), blob, bytes.NewReader(content), int64(size))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expectedWrites := []storage.BlobRange{
+		{Start: 0, End: pageRangeSize - 1},
+		{Start: 2 * pageRangeSize, End: uint64(size) - 1},
+	}
+	if len(blob.writes) != len(expectedWrites) {
+		t.Fatalf("expected writes %v, got %v", expectedWrites, blob.writes)
+	}
+	for i, w := range expectedWrites {
+		if blob.writes[i] != w {
+			t.Errorf("expected write %d to be %v, got %v", i, w, blob.writes[i])
+		}
+	}
+
+	if !bytes.Equal(blob.content, content) {
+		t.Error("uploaded content differs from the source")
+	}
+
+	expectedSum := md5.Sum(content)
+	if !bytes.Equal(sum.Sum(nil), expectedSum[:]) {
+		t.Errorf("expected MD5 %x, got %x", expectedSum, sum.Sum(nil))
+	}
+}
+
+func TestWritePages_shortRead(t *testing.T) {
+	blob := &fakePageBlob{content: make([]byte, 2048)}
+	_, err := writePages(context.Background(), blob, bytes.NewReader(make([]byte, 1024)), 2048)
+	if err == nil {
+		t.Fatal("expected an error when the source is shorter than its size")
+	}
+}
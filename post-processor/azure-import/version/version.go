@@ -0,0 +1,13 @@
+package version
+
+import (
+	"github.com/hashicorp/packer-plugin-sdk/version"
+	packerVersion "github.com/hashicorp/packer/version"
+)
+
+var AzureImportPluginVersion *version.PluginVersion
+
+func init() {
+	AzureImportPluginVersion = version.InitializePluginVersion(
+		packerVersion.Version, packerVersion.VersionPrerelease)
+}
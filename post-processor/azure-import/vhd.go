@@ -0,0 +1,53 @@
+package azureimport
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+const (
+	// vhdFooterSize is the size of the footer at the end of every VHD.
+	vhdFooterSize = 512
+
+	// vhdDiskTypeFixed is the footer disk type of a fixed size VHD. Azure only
+	// accepts fixed size VHDs, dynamic and differencing ones must be converted.
+	vhdDiskTypeFixed = 2
+
+	// Azure requires the virtual size of a VHD to be a whole number of MiB.
+	vhdSizeAlignment = 1024 * 1024
+)
+
+var vhdCookie = []byte("conectix")
+
+// checkFixedVHD validates that the file of the given size is a fixed VHD that
+// Azure can create an image from, by looking at its footer.
+func checkFixedVHD(r io.ReaderAt, size int64) error {
+	if size < vhdFooterSize || size%vhdFooterSize != 0 {
+		return fmt.Errorf("size %d is not a multiple of %d bytes", size, vhdFooterSize)
+	}
+
+	footer := make([]byte, vhdFooterSize)
+	if _, err := r.ReadAt(footer, size-vhdFooterSize); err != nil {
+		return fmt.Errorf("error reading VHD footer: %s", err)
+	}
+
+	if !bytes.Equal(footer[0:8], vhdCookie) {
+		return fmt.Errorf("no VHD footer found, the file is not a VHD")
+	}
+
+	if diskType := binary.BigEndian.Uint32(footer[60:64]); diskType != vhdDiskTypeFixed {
+		return fmt.Errorf("VHD disk type is %d, only fixed VHDs (disk type %d) are supported", diskType, vhdDiskTypeFixed)
+	}
+
+	virtualSize := int64(binary.BigEndian.Uint64(footer[48:56]))
+	if virtualSize != size-vhdFooterSize {
+		return fmt.Errorf("VHD virtual size %d does not match the file size %d", virtualSize, size-vhdFooterSize)
+	}
+	if virtualSize%vhdSizeAlignment != 0 {
+		return fmt.Errorf("VHD virtual size %d is not a whole number of MiB", virtualSize)
+	}
+
+	return nil
+}
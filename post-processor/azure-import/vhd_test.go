@@ -0,0 +1,56 @@
+package azureimport
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+func testVHD(virtualSize int64, diskType uint32) []byte {
+	b := make([]byte, virtualSize+vhdFooterSize)
+	footer := b[virtualSize:]
+	copy(footer, vhdCookie)
+	binary.BigEndian.PutUint64(footer[48:56], uint64(virtualSize))
+	binary.BigEndian.PutUint32(footer[60:64], diskType)
+	return b
+}
+
+func TestCheckFixedVHD(t *testing.T) {
+	tt := []struct {
+		Name          string
+		File          []byte
+		ExpectedError string
+	}{
+		{Name: "Fixed", File: testVHD(vhdSizeAlignment, vhdDiskTypeFixed)},
+		{Name: "Dynamic", File: testVHD(vhdSizeAlignment, 3), ExpectedError: "only fixed VHDs"},
+		{Name: "Unaligned", File: testVHD(vhdSizeAlignment+vhdFooterSize, vhdDiskTypeFixed), ExpectedError: "whole number of MiB"},
+		{Name: "Raw", File: make([]byte, vhdSizeAlignment), ExpectedError: "not a VHD"},
+		{Name: "Truncated", File: make([]byte, 100), ExpectedError: "not a multiple"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := checkFixedVHD(bytes.NewReader(tc.File), int64(len(tc.File)))
+			if tc.ExpectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.ExpectedError) {
+				t.Fatalf("expected error containing %q, got %v", tc.ExpectedError, err)
+			}
+		})
+	}
+}
+
+func TestCheckFixedVHD_sizeMismatch(t *testing.T) {
+	b := testVHD(vhdSizeAlignment, vhdDiskTypeFixed)
+	b = append(make([]byte, vhdSizeAlignment), b...)
+
+	err := checkFixedVHD(bytes.NewReader(b), int64(len(b)))
+	if err == nil || !strings.Contains(err.Error(), "does not match the file size") {
+		t.Fatalf("expected a size mismatch error, got %v", err)
+	}
+}
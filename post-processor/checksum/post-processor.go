@@ -26,7 +26,14 @@ type Config struct {
 
 	ChecksumTypes []string `mapstructure:"checksum_types"`
 	OutputPath    string   `mapstructure:"output"`
-	ctx           interpolate.Context
+
+	GPGKeyFile       string `mapstructure:"gpg_key_file"`
+	GPGKeyID         string `mapstructure:"gpg_key_id"`
+	GPGPassphrase    string `mapstructure:"gpg_passphrase"`
+	MinisignKeyFile  string `mapstructure:"minisign_key_file"`
+	MinisignPassword string `mapstructure:"minisign_password"`
+
+	ctx interpolate.Context
 }
 
 type PostProcessor struct {
@@ -83,6 +90,21 @@ func (p *PostProcessor) Configure(raws ...interface{}) error {
 		p.config.OutputPath = "packer_{{.BuildName}}_{{.BuilderType}}_{{.ChecksumType}}.checksum"
 	}
 
+	for key, path := range map[string]string{
+		"gpg_key_file":      p.config.GPGKeyFile,
+		"minisign_key_file": p.config.MinisignKeyFile,
+	} {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			errs = packersdk.MultiErrorAppend(errs,
+				fmt.Errorf("%s is invalid: %s", key, err))
+		}
+	}
+
+	packersdk.LogSecretFilter.Set(p.config.GPGPassphrase, p.config.MinisignPassword)
+
 	if err = interpolate.Validate(p.config.OutputPath, &p.config.ctx); err != nil {
 		errs = packersdk.MultiErrorAppend(
 			errs, fmt.Errorf("Error parsing target template: %s", err))
@@ -115,6 +137,8 @@ func (p *PostProcessor) PostProcess(ctx context.Context, ui packersdk.Ui, artifa
 
 	newartifact := NewArtifact(artifact.Files())
 
+	// The checksum files to sign, in the order they are written
+	var checksumFiles []string
 	for _, ct := range p.config.ChecksumTypes {
 		h = getHash(ct)
 		generatedData["ChecksumType"] = ct
@@ -129,6 +153,9 @@ func (p *PostProcessor) PostProcess(ctx context.Context, ui packersdk.Ui, artifa
 			if _, err := os.Stat(checksumFile); err != nil {
 				newartifact.files = append(newartifact.files, checksumFile)
 			}
+			if len(checksumFiles) == 0 || checksumFiles[len(checksumFiles)-1] != checksumFile {
+				checksumFiles = append(checksumFiles, checksumFile)
+			}
 			if err := os.MkdirAll(filepath.Dir(checksumFile), os.FileMode(0755)); err != nil {
 				return nil, false, true, fmt.Errorf("unable to create dir: %s", err.Error())
 			}
@@ -154,7 +181,47 @@ func (p *PostProcessor) PostProcess(ctx context.Context, ui packersdk.Ui, artifa
 		}
 	}
 
+	signatures, err := p.sign(ui, checksumFiles)
+	if err != nil {
+		return nil, false, true, err
+	}
+	newartifact.files = append(newartifact.files, signatures...)
+
 	// sets keep and forceOverride to true because we don't want to accidentally
 	// delete the very artifact we're checksumming.
 	return newartifact, true, true, nil
 }
+
+// sign signs the checksum files with the configured keys and returns the
+// paths of the signatures.
+func (p *PostProcessor) sign(ui packersdk.Ui, checksumFiles []string) ([]string, error) {
+	var signatures []string
+
+	if p.config.GPGKeyFile != "" {
+		for _, checksumFile := range checksumFiles {
+			ui.Message(fmt.Sprintf("Signing %s with GPG", checksumFile))
+			signature, err := gpgSign(p.config.GPGKeyFile, p.config.GPGKeyID, p.config.GPGPassphrase, checksumFile)
+			if err != nil {
+				return nil, err
+			}
+			signatures = append(signatures, signature)
+		}
+	}
+
+	if p.config.MinisignKeyFile != "" {
+		key, err := readMinisignKey(p.config.MinisignKeyFile, p.config.MinisignPassword)
+		if err != nil {
+			return nil, err
+		}
+		for _, checksumFile := range checksumFiles {
+			ui.Message(fmt.Sprintf("Signing %s with minisign", checksumFile))
+			signature, err := minisignSign(key, checksumFile)
+			if err != nil {
+				return nil, err
+			}
+			signatures = append(signatures, signature)
+		}
+	}
+
+	return signatures, nil
+}
@@ -20,6 +20,11 @@ type FlatConfig struct {
 	PackerSensitiveVars []string          `mapstructure:"packer_sensitive_variables" cty:"packer_sensitive_variables" hcl:"packer_sensitive_variables"`
 	ChecksumTypes       []string          `mapstructure:"checksum_types" cty:"checksum_types" hcl:"checksum_types"`
 	OutputPath          *string           `mapstructure:"output" cty:"output" hcl:"output"`
+	GPGKeyFile          *string           `mapstructure:"gpg_key_file" cty:"gpg_key_file" hcl:"gpg_key_file"`
+	GPGKeyID            *string           `mapstructure:"gpg_key_id" cty:"gpg_key_id" hcl:"gpg_key_id"`
+	GPGPassphrase       *string           `mapstructure:"gpg_passphrase" cty:"gpg_passphrase" hcl:"gpg_passphrase"`
+	MinisignKeyFile     *string           `mapstructure:"minisign_key_file" cty:"minisign_key_file" hcl:"minisign_key_file"`
+	MinisignPassword    *string           `mapstructure:"minisign_password" cty:"minisign_password" hcl:"minisign_password"`
 }
 
 // FlatMapstructure returns a new FlatConfig.
@@ -44,6 +49,11 @@ func (*FlatConfig) HCL2Spec() map[string]hcldec.Spec {
 		"packer_sensitive_variables": &hcldec.AttrSpec{Name: "packer_sensitive_variables", Type: cty.List(cty.String), Required: false},
 		"checksum_types":             &hcldec.AttrSpec{Name: "checksum_types", Type: cty.List(cty.String), Required: false},
 		"output":                     &hcldec.AttrSpec{Name: "output", Type: cty.String, Required: false},
+		"gpg_key_file":               &hcldec.AttrSpec{Name: "gpg_key_file", Type: cty.String, Required: false},
+		"gpg_key_id":                 &hcldec.AttrSpec{Name: "gpg_key_id", Type: cty.String, Required: false},
+		"gpg_passphrase":             &hcldec.AttrSpec{Name: "gpg_passphrase", Type: cty.String, Required: false},
+		"minisign_key_file":          &hcldec.AttrSpec{Name: "minisign_key_file", Type: cty.String, Required: false},
+		"minisign_password":          &hcldec.AttrSpec{Name: "minisign_password", Type: cty.String, Required: false},
 	}
 	return s
 }
@@ -0,0 +1,231 @@
+package checksum
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/scrypt"
+)
+
+// gpgSign writes an armored detached signature of path, path.asc, with the
+// secret key of keyFile whose ID ends with keyID, or the first one if keyID
+// is empty.
+func gpgSign(keyFile, keyID, passphrase, path string) (string, error) {
+	entity, err := readGPGKey(keyFile, keyID)
+	if err != nil {
+		return "", err
+	}
+
+	if entity.PrivateKey.Encrypted {
+		if err := entity.PrivateKey.Decrypt([]byte(passphrase)); err != nil {
+			return "", fmt.Errorf("unable to decrypt GPG key: %s", err)
+		}
+	}
+
+	return writeSignature(path, ".asc", func(message io.Reader, signature io.Writer) error {
+		return openpgp.ArmoredDetachSign(signature, entity, message, nil)
+	})
+}
+
+func readGPGKey(keyFile, keyID string) (*openpgp.Entity, error) {
+	data, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read GPG key: %s", err)
+	}
+
+	// Keys can be exported armored or binary
+	entities, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(data))
+	if err != nil {
+		entities, err = openpgp.ReadKeyRing(bytes.NewReader(data))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse GPG key %s: %s", keyFile, err)
+	}
+
+	keyID = strings.ToUpper(strings.TrimPrefix(keyID, "0x"))
+	for _, entity := range entities {
+		if entity.PrivateKey == nil {
+			continue
+		}
+		if keyID == "" || strings.HasSuffix(entity.PrivateKey.KeyIdString(), keyID) {
+			return entity, nil
+		}
+	}
+	if keyID != "" {
+		return nil, fmt.Errorf("no GPG secret key with ID %s found in %s", keyID, keyFile)
+	}
+	return nil, fmt.Errorf("no GPG secret key found in %s", keyFile)
+}
+
+// minisignKey is a secret key of minisign, see
+// https://jedisct1.github.io/minisign/ for the formats of keys and
+// signatures.
+type minisignKey struct {
+	id  [8]byte
+	key ed25519.PrivateKey
+}
+
+const (
+	minisignKeyLen   = 2 + 2 + 2 + 32 + 8 + 8 + 104
+	minisignSigAlg   = "Ed"
+	minisignHashAlg  = "ED"
+	minisignKDFNone  = "\x00\x00"
+	minisignKDFAlg   = "Sc"
+	minisignChkAlg   = "B2"
+	minisignComment  = "untrusted comment: "
+	minisignTComment = "trusted comment: "
+)
+
+// readMinisignKey reads and decrypts the minisign secret key of keyFile.
+func readMinisignKey(keyFile, password string) (*minisignKey, error) {
+	f, err := os.Open(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read minisign key: %s", err)
+	}
+	defer f.Close()
+
+	// The key is the base64 encoded line following the untrusted comment
+	var encoded string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, minisignComment) {
+			continue
+		}
+		encoded = line
+		break
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read minisign key: %s", err)
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(raw) != minisignKeyLen {
+		return nil, fmt.Errorf("%s is not a minisign secret key", keyFile)
+	}
+	if string(raw[0:2]) != minisignSigAlg || string(raw[4:6]) != minisignChkAlg {
+		return nil, fmt.Errorf("unsupported minisign key algorithms in %s", keyFile)
+	}
+
+	salt := raw[6:38]
+	opsLimit := binary.LittleEndian.Uint64(raw[38:46])
+	memLimit := binary.LittleEndian.Uint64(raw[46:54])
+	keynum := append([]byte{}, raw[54:]...)
+
+	switch string(raw[2:4]) {
+	case minisignKDFNone:
+	case minisignKDFAlg:
+		n, r, p := scryptParams(opsLimit, memLimit)
+		stream, err := scrypt.Key([]byte(password), salt, n, r, p, len(keynum))
+		if err != nil {
+			return nil, fmt.Errorf("unable to decrypt minisign key: %s", err)
+		}
+		for i := range keynum {
+			keynum[i] ^= stream[i]
+		}
+	default:
+		return nil, fmt.Errorf("unsupported minisign key derivation in %s", keyFile)
+	}
+
+	// keynum is the key ID, the secret key and a checksum of both
+	k := &minisignKey{key: ed25519.PrivateKey(keynum[8:72])}
+	copy(k.id[:], keynum[0:8])
+
+	checksum := blake2b.Sum256(append(append([]byte(minisignSigAlg), keynum[0:8]...), keynum[8:72]...))
+	if !bytes.Equal(checksum[:], keynum[72:104]) {
+		return nil, fmt.Errorf("unable to decrypt minisign key: wrong password")
+	}
+	return k, nil
+}
+
+// scryptParams derives the scrypt parameters from the limits stored in a
+// minisign key, the same way as crypto_pwhash_scryptsalsa208sha256 of
+// libsodium does.
+func scryptParams(opsLimit, memLimit uint64) (n, r, p int) {
+	if opsLimit < 32768 {
+		opsLimit = 32768
+	}
+	r = 8
+
+	var maxN uint64
+	if opsLimit < memLimit/32 {
+		p = 1
+		maxN = opsLimit / uint64(r*4)
+	} else {
+		maxN = memLimit / uint64(r*128)
+	}
+
+	logN := uint(1)
+	for ; logN < 63; logN++ {
+		if uint64(1)<<logN > maxN/2 {
+			break
+		}
+	}
+
+	if opsLimit >= memLimit/32 {
+		maxRP := (opsLimit / 4) / (uint64(1) << logN)
+		if maxRP > 0x3fffffff {
+			maxRP = 0x3fffffff
+		}
+		p = int(maxRP) / r
+	}
+	return 1 << logN, r, p
+}
+
+// minisignSign writes a prehashed minisign signature of path, path.minisig.
+func minisignSign(k *minisignKey, path string) (string, error) {
+	return writeSignature(path, ".minisig", func(message io.Reader, signature io.Writer) error {
+		h, _ := blake2b.New512(nil)
+		if _, err := io.Copy(h, message); err != nil {
+			return err
+		}
+
+		sig := append(append([]byte(minisignHashAlg), k.id[:]...), ed25519.Sign(k.key, h.Sum(nil))...)
+		trusted := fmt.Sprintf("timestamp:%d\tfile:%s", time.Now().Unix(), filepath.Base(path))
+		globalSig := ed25519.Sign(k.key, append(append([]byte{}, sig[10:]...), trusted...))
+
+		_, err := fmt.Fprintf(signature, "%ssignature from packer secret key\n%s\n%s%s\n%s\n",
+			minisignComment,
+			base64.StdEncoding.EncodeToString(sig),
+			minisignTComment, trusted,
+			base64.StdEncoding.EncodeToString(globalSig))
+		return err
+	})
+}
+
+// writeSignature signs path and writes the signature next to it, with the
+// given extension.
+func writeSignature(path, ext string, sign func(message io.Reader, signature io.Writer) error) (string, error) {
+	message, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer message.Close()
+
+	signaturePath := path + ext
+	signature, err := os.Create(signaturePath)
+	if err != nil {
+		return "", fmt.Errorf("unable to create signature %s: %s", signaturePath, err)
+	}
+
+	if err := sign(message, signature); err != nil {
+		signature.Close()
+		return "", fmt.Errorf("unable to sign %s: %s", path, err)
+	}
+	if err := signature.Close(); err != nil {
+		return "", fmt.Errorf("unable to write signature %s: %s", signaturePath, err)
+	}
+	return signaturePath, nil
+}
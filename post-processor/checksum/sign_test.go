@@ -0,0 +1,199 @@
+package checksum
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+	"golang.org/x/crypto/scrypt"
+)
+
+func TestScryptParams(t *testing.T) {
+	// The limits minisign uses when creating keys
+	n, r, p := scryptParams(33554432, 1073741824)
+	if n != 1<<20 || r != 8 || p != 1 {
+		t.Fatalf("bad: N=%d r=%d p=%d", n, r, p)
+	}
+
+	n, r, p = scryptParams(32768, 16*1024*1024)
+	if n != 1024 || r != 8 || p != 1 {
+		t.Fatalf("bad: N=%d r=%d p=%d", n, r, p)
+	}
+}
+
+// writeMinisignKey writes a minisign secret key, encrypted with password
+// unless it is empty, with cheap scrypt limits.
+func writeMinisignKey(t *testing.T, path, password string) (ed25519.PublicKey, []byte) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := []byte("keyid123")
+
+	keynum := append(append([]byte{}, id...), priv...)
+	checksum := blake2b.Sum256(append([]byte("Ed"), keynum...))
+	keynum = append(keynum, checksum[:]...)
+
+	raw := []byte("Ed")
+	salt := make([]byte, 32)
+	rand.Read(salt)
+	limits := make([]byte, 16)
+	binary.LittleEndian.PutUint64(limits[0:8], 32768)
+	binary.LittleEndian.PutUint64(limits[8:16], 16*1024*1024)
+	if password == "" {
+		raw = append(raw, 0, 0)
+	} else {
+		raw = append(raw, "Sc"...)
+		stream, err := scrypt.Key([]byte(password), salt, 1024, 8, 1, len(keynum))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range keynum {
+			keynum[i] ^= stream[i]
+		}
+	}
+	raw = append(raw, "B2"...)
+	raw = append(raw, salt...)
+	raw = append(raw, limits...)
+	raw = append(raw, keynum...)
+
+	content := "untrusted comment: minisign encrypted secret key\n" + base64.StdEncoding.EncodeToString(raw) + "\n"
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return pub, id
+}
+
+func TestMinisignSign(t *testing.T) {
+	dir, err := ioutil.TempDir("", "packer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, password := range []string{"", "secret"} {
+		keyFile := filepath.Join(dir, "minisign.key")
+		pub, id := writeMinisignKey(t, keyFile, password)
+
+		sums := filepath.Join(dir, "sha256sums")
+		if err := ioutil.WriteFile(sums, []byte("abc\tpackage.txt\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		key, err := readMinisignKey(keyFile, password)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		signature, err := minisignSign(key, sums)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if signature != sums+".minisig" {
+			t.Fatalf("bad signature path: %s", signature)
+		}
+
+		content, err := ioutil.ReadFile(signature)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+		if len(lines) != 4 || !strings.HasPrefix(lines[0], "untrusted comment: ") || !strings.HasPrefix(lines[2], "trusted comment: ") {
+			t.Fatalf("bad signature file:\n%s", content)
+		}
+
+		sig, _ := base64.StdEncoding.DecodeString(lines[1])
+		if len(sig) != 74 || string(sig[0:2]) != "ED" || !bytes.Equal(sig[2:10], id) {
+			t.Fatalf("bad signature: %x", sig)
+		}
+		hash := blake2b.Sum512([]byte("abc\tpackage.txt\n"))
+		if !ed25519.Verify(pub, hash[:], sig[10:]) {
+			t.Fatal("signature does not verify")
+		}
+
+		trusted := strings.TrimPrefix(lines[2], "trusted comment: ")
+		if !strings.HasSuffix(trusted, "\tfile:sha256sums") {
+			t.Fatalf("bad trusted comment: %s", trusted)
+		}
+		globalSig, _ := base64.StdEncoding.DecodeString(lines[3])
+		if !ed25519.Verify(pub, append(sig[10:], trusted...), globalSig) {
+			t.Fatal("global signature does not verify")
+		}
+	}
+}
+
+func TestMinisignSign_wrongPassword(t *testing.T) {
+	dir, err := ioutil.TempDir("", "packer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	keyFile := filepath.Join(dir, "minisign.key")
+	writeMinisignKey(t, keyFile, "secret")
+
+	if _, err := readMinisignKey(keyFile, "wrong"); err == nil || !strings.Contains(err.Error(), "wrong password") {
+		t.Fatalf("expected a wrong password error, got %v", err)
+	}
+}
+
+func TestGPGSign(t *testing.T) {
+	dir, err := ioutil.TempDir("", "packer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	entity, err := openpgp.NewEntity("packer", "", "packer@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFile := filepath.Join(dir, "secret.asc")
+	f, err := os.Create(keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := armor.Encode(f, openpgp.PrivateKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entity.SerializePrivate(w, nil); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	f.Close()
+
+	sums := filepath.Join(dir, "sha256sums")
+	if err := ioutil.WriteFile(sums, []byte("abc\tpackage.txt\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := gpgSign(keyFile, "0xDEADBEEF", "", sums); err == nil {
+		t.Fatal("should fail with an unknown key ID")
+	}
+
+	signature, err := gpgSign(keyFile, entity.PrivateKey.KeyIdShortString(), "", sums)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if signature != sums+".asc" {
+		t.Fatalf("bad signature path: %s", signature)
+	}
+
+	message, _ := os.Open(sums)
+	defer message.Close()
+	sig, _ := os.Open(signature)
+	defer sig.Close()
+	if _, err := openpgp.CheckArmoredDetachedSignature(openpgp.EntityList{entity}, message, sig); err != nil {
+		t.Fatalf("signature does not verify: %s", err)
+	}
+}
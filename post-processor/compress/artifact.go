@@ -3,12 +3,18 @@ package compress
 import (
 	"fmt"
 	"os"
+	"strings"
 )
 
 const BuilderId = "packer.post-processor.compress"
 
 type Artifact struct {
 	Path string
+
+	// Parts are the files the archive is split in when split_size is set,
+	// and Manifest the file listing their checksums.
+	Parts    []string
+	Manifest string
 }
 
 func (a *Artifact) BuilderId() string {
@@ -20,10 +26,16 @@ func (*Artifact) Id() string {
 }
 
 func (a *Artifact) Files() []string {
+	if len(a.Parts) > 0 {
+		return append(append([]string{}, a.Parts...), a.Manifest)
+	}
 	return []string{a.Path}
 }
 
 func (a *Artifact) String() string {
+	if len(a.Parts) > 0 {
+		return fmt.Sprintf("compressed artifacts in %d parts: %s", len(a.Parts), strings.Join(a.Files(), ", "))
+	}
 	return fmt.Sprintf("compressed artifacts in: %s", a.Path)
 }
 
@@ -32,5 +44,10 @@ func (*Artifact) State(name string) interface{} {
 }
 
 func (a *Artifact) Destroy() error {
-	return os.Remove(a.Path)
+	for _, path := range a.Files() {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -0,0 +1,130 @@
+package compress
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// parallelBlockSize is the size of the blocks compressed concurrently by
+// parallelWriter. Larger blocks compress better but use more memory, as up to
+// one block per thread is held in memory.
+const parallelBlockSize = 16 * 1024 * 1024
+
+type parallelResult struct {
+	data []byte
+	err  error
+}
+
+// parallelWriter compresses its input with multiple goroutines for the
+// formats whose library can only use one. The input is split in blocks that
+// are compressed concurrently, each one as an independent stream created with
+// newWriter. The streams are written in order, and the resulting concatenation
+// of streams is read back as a single one by xz, bzip2 and lz4.
+type parallelWriter struct {
+	newWriter func(io.Writer) (io.WriteCloser, error)
+	blockSize int
+	block     []byte
+	blocks    int
+
+	// results holds, in order, the channels the compressed blocks are sent
+	// to. Its capacity limits the number of blocks compressed concurrently.
+	results chan chan parallelResult
+	done    chan struct{}
+
+	l   sync.Mutex
+	err error
+}
+
+func newParallelWriter(output io.Writer, threads, blockSize int, newWriter func(io.Writer) (io.WriteCloser, error)) *parallelWriter {
+	w := &parallelWriter{
+		newWriter: newWriter,
+		blockSize: blockSize,
+		block:     make([]byte, 0, blockSize),
+		results:   make(chan chan parallelResult, threads),
+		done:      make(chan struct{}),
+	}
+	go w.writeResults(output)
+	return w
+}
+
+func (w *parallelWriter) Write(p []byte) (int, error) {
+	if err := w.getErr(); err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for len(p) > 0 {
+		c := copy(w.block[len(w.block):cap(w.block)], p)
+		w.block = w.block[:len(w.block)+c]
+		p = p[c:]
+		n += c
+
+		if len(w.block) == cap(w.block) {
+			w.flush()
+		}
+	}
+	return n, nil
+}
+
+// Close compresses the last block and waits for all blocks to be written.
+func (w *parallelWriter) Close() error {
+	// An empty input is still written as an empty stream, so that the
+	// output is valid.
+	if len(w.block) > 0 || w.blocks == 0 {
+		w.flush()
+	}
+	close(w.results)
+	<-w.done
+	return w.getErr()
+}
+
+func (w *parallelWriter) flush() {
+	block := w.block
+	w.block = make([]byte, 0, w.blockSize)
+	w.blocks++
+
+	result := make(chan parallelResult, 1)
+	w.results <- result
+	go func() {
+		var buf bytes.Buffer
+		zw, err := w.newWriter(&buf)
+		if err == nil {
+			_, err = zw.Write(block)
+			if closeErr := zw.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		result <- parallelResult{data: buf.Bytes(), err: err}
+	}()
+}
+
+func (w *parallelWriter) writeResults(output io.Writer) {
+	defer close(w.done)
+
+	// Keep receiving after an error so that flush never blocks.
+	for result := range w.results {
+		r := <-result
+		err := r.err
+		if err == nil && w.getErr() == nil {
+			_, err = output.Write(r.data)
+		}
+		if err != nil {
+			w.setErr(err)
+		}
+	}
+}
+
+func (w *parallelWriter) getErr() error {
+	w.l.Lock()
+	defer w.l.Unlock()
+	return w.err
+}
+
+func (w *parallelWriter) setErr(err error) {
+	w.l.Lock()
+	defer w.l.Unlock()
+	if w.err == nil {
+		w.err = err
+	}
+}
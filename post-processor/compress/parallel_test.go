@@ -0,0 +1,81 @@
+package compress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+func newGzipWriter(w io.Writer) (io.WriteCloser, error) {
+	return gzip.NewWriter(w), nil
+}
+
+func TestParallelWriter(t *testing.T) {
+	tc := map[string]int{
+		"empty":           0,
+		"partial block":   5,
+		"one block":       16,
+		"several blocks":  16*7 + 3,
+		"exact multiples": 16 * 4,
+	}
+
+	for name, size := range tc {
+		t.Run(name, func(t *testing.T) {
+			input := make([]byte, size)
+			for i := range input {
+				input[i] = byte(i % 251)
+			}
+
+			var output bytes.Buffer
+			w := newParallelWriter(&output, 3, 16, newGzipWriter)
+			// Write in chunks that don't match the block size
+			for data := input; len(data) > 0; {
+				n := 7
+				if n > len(data) {
+					n = len(data)
+				}
+				if _, err := w.Write(data[:n]); err != nil {
+					t.Fatalf("err: %s", err)
+				}
+				data = data[n:]
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			// gzip reads concatenated streams as one
+			r, err := gzip.NewReader(&output)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			found, err := ioutil.ReadAll(r)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if !bytes.Equal(found, input) {
+				t.Fatalf("expected %v, found %v", input, found)
+			}
+		})
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestParallelWriter_outputError(t *testing.T) {
+	w := newParallelWriter(failingWriter{}, 2, 4, newGzipWriter)
+	for i := 0; i < 10; i++ {
+		if _, err := w.Write([]byte("data")); err != nil {
+			break
+		}
+	}
+	if err := w.Close(); err == nil || err.Error() != "disk full" {
+		t.Fatalf("expected the output error, got %v", err)
+	}
+}
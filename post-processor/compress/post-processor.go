@@ -20,6 +20,7 @@ import (
 	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
 	"github.com/hashicorp/packer-plugin-sdk/template/config"
 	"github.com/hashicorp/packer-plugin-sdk/template/interpolate"
+	"github.com/klauspost/compress/zstd"
 	"github.com/klauspost/pgzip"
 	"github.com/pierrec/lz4"
 	"github.com/ulikunitz/xz"
@@ -41,15 +42,18 @@ type Config struct {
 	common.PackerConfig `mapstructure:",squash"`
 
 	// Fields from config file
-	OutputPath       string `mapstructure:"output"`
-	Format           string `mapstructure:"format"`
-	CompressionLevel int    `mapstructure:"compression_level"`
+	OutputPath         string `mapstructure:"output"`
+	Format             string `mapstructure:"format"`
+	CompressionLevel   int    `mapstructure:"compression_level"`
+	CompressionThreads int    `mapstructure:"compression_threads"`
+	SplitSize          string `mapstructure:"split_size"`
 
 	// Derived fields
 	Archive   string
 	Algorithm string
 
-	ctx interpolate.Context
+	splitBytes int64
+	ctx        interpolate.Context
 }
 
 type PostProcessor struct {
@@ -82,6 +86,14 @@ func (p *PostProcessor) Configure(raws ...interface{}) error {
 		p.config.OutputPath = "packer_{{.BuildName}}_{{.BuilderType}}"
 	}
 
+	if p.config.CompressionThreads == 0 {
+		p.config.CompressionThreads = runtime.GOMAXPROCS(-1)
+	}
+	if p.config.CompressionThreads < 0 {
+		errs = packersdk.MultiErrorAppend(
+			errs, fmt.Errorf("compression_threads must be greater than 0"))
+	}
+
 	if p.config.CompressionLevel > pgzip.BestCompression {
 		p.config.CompressionLevel = pgzip.BestCompression
 	}
@@ -92,6 +104,14 @@ func (p *PostProcessor) Configure(raws ...interface{}) error {
 		p.config.CompressionLevel = pgzip.DefaultCompression
 	}
 
+	if p.config.SplitSize != "" {
+		p.config.splitBytes, err = parseSplitSize(p.config.SplitSize)
+		if err != nil {
+			errs = packersdk.MultiErrorAppend(
+				errs, fmt.Errorf("Error parsing split_size: %s", err))
+		}
+	}
+
 	if err = interpolate.Validate(p.config.OutputPath, &p.config.ctx); err != nil {
 		errs = packersdk.MultiErrorAppend(
 			errs, fmt.Errorf("Error parsing target template: %s", err))
@@ -135,65 +155,81 @@ func (p *PostProcessor) PostProcess(
 		fmt.Println(target)
 	}
 
-	newArtifact := &Artifact{Path: target}
-
 	if err = os.MkdirAll(filepath.Dir(target), os.FileMode(0755)); err != nil {
 		return nil, false, false, fmt.Errorf(
 			"Unable to create dir for archive %s: %s", target, err)
 	}
-	outputFile, err := os.Create(target)
-	if err != nil {
-		return nil, false, false, fmt.Errorf(
-			"Unable to create archive %s: %s", target, err)
+	var outputFile io.WriteCloser
+	var splitter *splitWriter
+	if p.config.splitBytes > 0 {
+		splitter = newSplitWriter(target, p.config.splitBytes)
+		outputFile = splitter
+	} else {
+		outputFile, err = os.Create(target)
+		if err != nil {
+			return nil, false, false, fmt.Errorf(
+				"Unable to create archive %s: %s", target, err)
+		}
 	}
-	defer outputFile.Close()
+
+	// The archive is closed explicitly once complete, so that errors
+	// flushing it are reported; this only closes it when failing earlier.
+	var output io.WriteCloser
+	closed := false
+	defer func() {
+		if !closed {
+			closeArchive(output, outputFile)
+		}
+	}()
 
 	// Setup output interface. If we're using compression, output is a
 	// compression writer. Otherwise it's just a file.
-	var output io.WriteCloser
 	errTmpl := "error creating %s writer: %s"
+	threads := p.config.CompressionThreads
 	switch p.config.Algorithm {
 	case "bgzf":
 		ui.Say(fmt.Sprintf("Using bgzf compression with %d cores for %s",
-			runtime.GOMAXPROCS(-1), target))
-		output, err = makeBGZFWriter(outputFile, p.config.CompressionLevel)
+			threads, target))
+		output, err = makeBGZFWriter(outputFile, p.config.CompressionLevel, threads)
 		if err != nil {
 			return nil, false, false, fmt.Errorf(errTmpl, p.config.Algorithm, err)
 		}
-		defer output.Close()
 	case "bzip2":
-		ui.Say(fmt.Sprintf("Using bzip2 compression with 1 core for %s (library does not support MT)",
-			target))
-		output, err = makeBZIP2Writer(outputFile, p.config.CompressionLevel)
+		ui.Say(fmt.Sprintf("Using bzip2 compression with %d cores for %s",
+			threads, target))
+		output, err = makeBZIP2Writer(outputFile, p.config.CompressionLevel, threads)
 		if err != nil {
 			return nil, false, false, fmt.Errorf(errTmpl, p.config.Algorithm, err)
 		}
-		defer output.Close()
 	case "lz4":
 		ui.Say(fmt.Sprintf("Using lz4 compression with %d cores for %s",
-			runtime.GOMAXPROCS(-1), target))
-		output, err = makeLZ4Writer(outputFile, p.config.CompressionLevel)
+			threads, target))
+		output, err = makeLZ4Writer(outputFile, p.config.CompressionLevel, threads)
 		if err != nil {
 			return nil, false, false, fmt.Errorf(errTmpl, p.config.Algorithm, err)
 		}
-		defer output.Close()
 	case "xz":
-		ui.Say(fmt.Sprintf("Using xz compression with 1 core for %s (library does not support MT)",
-			target))
-		output, err = makeXZWriter(outputFile)
+		ui.Say(fmt.Sprintf("Using xz compression with %d cores for %s",
+			threads, target))
+		output, err = makeXZWriter(outputFile, threads)
+		if err != nil {
+			return nil, false, false, fmt.Errorf(errTmpl, p.config.Algorithm, err)
+		}
+	case "zstd":
+		ui.Say(fmt.Sprintf("Using zstd compression with %d cores for %s",
+			threads, target))
+		output, err = makeZstdWriter(outputFile, p.config.CompressionLevel, threads)
 		if err != nil {
 			return nil, false, false, fmt.Errorf(errTmpl, p.config.Algorithm, err)
 		}
-		defer output.Close()
 	case "pgzip":
 		ui.Say(fmt.Sprintf("Using pgzip compression with %d cores for %s",
-			runtime.GOMAXPROCS(-1), target))
-		output, err = makePgzipWriter(outputFile, p.config.CompressionLevel)
+			threads, target))
+		output, err = makePgzipWriter(outputFile, p.config.CompressionLevel, threads)
 		if err != nil {
 			return nil, false, false,
 				fmt.Errorf(errTmpl, p.config.Algorithm, err)
 		}
-		defer output.Close()
 	default:
 		output = outputFile
 	}
@@ -242,11 +278,36 @@ func (p *PostProcessor) PostProcess(
 		}
 	}
 
-	ui.Say(fmt.Sprintf("Archive %s completed", target))
+	closed = true
+	if err := closeArchive(output, outputFile); err != nil {
+		return nil, false, false, fmt.Errorf("Failed to complete archive %s: %s", target, err)
+	}
+
+	newArtifact := &Artifact{Path: target}
+	if splitter != nil {
+		newArtifact.Parts = splitter.parts
+		newArtifact.Manifest = splitter.manifest
+		ui.Say(fmt.Sprintf("Archive %s completed in %d parts", target, len(splitter.parts)))
+	} else {
+		ui.Say(fmt.Sprintf("Archive %s completed", target))
+	}
 
 	return newArtifact, false, false, nil
 }
 
+// closeArchive closes the compression writer, if any, and then the file it
+// writes to.
+func closeArchive(output io.WriteCloser, file io.WriteCloser) error {
+	var err error
+	if output != nil && output != file {
+		err = output.Close()
+	}
+	if fileErr := file.Close(); err == nil {
+		err = fileErr
+	}
+	return err
+}
+
 func (config *Config) detectFromFilename() {
 	var result [][]string
 
@@ -258,6 +319,8 @@ func (config *Config) detectFromFilename() {
 		"bgzf":  "bgzf",
 		"xz":    "xz",
 		"bzip2": "bzip2",
+		"zst":   "zstd",
+		"zstd":  "zstd",
 	}
 
 	if config.Format == "" {
@@ -306,50 +369,82 @@ func (config *Config) detectFromFilename() {
 	return
 }
 
-func makeBGZFWriter(output io.WriteCloser, compressionLevel int) (io.WriteCloser, error) {
-	bgzfWriter, err := bgzf.NewWriterLevel(output, compressionLevel, runtime.GOMAXPROCS(-1))
+func makeBGZFWriter(output io.WriteCloser, compressionLevel int, threads int) (io.WriteCloser, error) {
+	bgzfWriter, err := bgzf.NewWriterLevel(output, compressionLevel, threads)
 	if err != nil {
 		return nil, ErrInvalidCompressionLevel
 	}
 	return bgzfWriter, nil
 }
 
-func makeBZIP2Writer(output io.Writer, compressionLevel int) (io.WriteCloser, error) {
+func makeBZIP2Writer(output io.Writer, compressionLevel int, threads int) (io.WriteCloser, error) {
 	// Set the default to highest level compression
 	bzipCFG := &bzip2.WriterConfig{Level: 9}
 	// Override our set defaults
 	if compressionLevel > 0 {
 		bzipCFG.Level = compressionLevel
 	}
-	bzipWriter, err := bzip2.NewWriter(output, bzipCFG)
-	if err != nil {
-		return nil, err
+	newWriter := func(w io.Writer) (io.WriteCloser, error) {
+		bzipWriter, err := bzip2.NewWriter(w, bzipCFG)
+		if err != nil {
+			return nil, err
+		}
+		return bzipWriter, nil
 	}
-	return bzipWriter, nil
+	if threads > 1 {
+		return newParallelWriter(output, threads, parallelBlockSize, newWriter), nil
+	}
+	return newWriter(output)
 }
 
-func makeLZ4Writer(output io.WriteCloser, compressionLevel int) (io.WriteCloser, error) {
-	lzwriter := lz4.NewWriter(output)
-	if compressionLevel > 0 {
-		lzwriter.Header.CompressionLevel = compressionLevel
+func makeLZ4Writer(output io.WriteCloser, compressionLevel int, threads int) (io.WriteCloser, error) {
+	newWriter := func(w io.Writer) (io.WriteCloser, error) {
+		lzwriter := lz4.NewWriter(w)
+		if compressionLevel > 0 {
+			lzwriter.Header.CompressionLevel = compressionLevel
+		}
+		return lzwriter, nil
+	}
+	if threads > 1 {
+		return newParallelWriter(output, threads, parallelBlockSize, newWriter), nil
+	}
+	return newWriter(output)
+}
+
+func makeXZWriter(output io.WriteCloser, threads int) (io.WriteCloser, error) {
+	newWriter := func(w io.Writer) (io.WriteCloser, error) {
+		xzwriter, err := xz.NewWriter(w)
+		if err != nil {
+			return nil, err
+		}
+		return xzwriter, nil
 	}
-	return lzwriter, nil
+	if threads > 1 {
+		return newParallelWriter(output, threads, parallelBlockSize, newWriter), nil
+	}
+	return newWriter(output)
 }
 
-func makeXZWriter(output io.WriteCloser) (io.WriteCloser, error) {
-	xzwriter, err := xz.NewWriter(output)
+func makeZstdWriter(output io.WriteCloser, compressionLevel int, threads int) (io.WriteCloser, error) {
+	level := zstd.SpeedDefault
+	if compressionLevel > 0 {
+		level = zstd.EncoderLevelFromZstd(compressionLevel)
+	}
+	zstdWriter, err := zstd.NewWriter(output,
+		zstd.WithEncoderLevel(level),
+		zstd.WithEncoderConcurrency(threads))
 	if err != nil {
 		return nil, err
 	}
-	return xzwriter, nil
+	return zstdWriter, nil
 }
 
-func makePgzipWriter(output io.WriteCloser, compressionLevel int) (io.WriteCloser, error) {
+func makePgzipWriter(output io.WriteCloser, compressionLevel int, threads int) (io.WriteCloser, error) {
 	gzipWriter, err := pgzip.NewWriterLevel(output, compressionLevel)
 	if err != nil {
 		return nil, ErrInvalidCompressionLevel
 	}
-	gzipWriter.SetConcurrency(500000, runtime.GOMAXPROCS(-1))
+	gzipWriter.SetConcurrency(500000, threads)
 	return gzipWriter, nil
 }
 
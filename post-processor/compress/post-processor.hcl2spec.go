@@ -21,6 +21,8 @@ type FlatConfig struct {
 	OutputPath          *string           `mapstructure:"output" cty:"output" hcl:"output"`
 	Format              *string           `mapstructure:"format" cty:"format" hcl:"format"`
 	CompressionLevel    *int              `mapstructure:"compression_level" cty:"compression_level" hcl:"compression_level"`
+	CompressionThreads  *int              `mapstructure:"compression_threads" cty:"compression_threads" hcl:"compression_threads"`
+	SplitSize           *string           `mapstructure:"split_size" cty:"split_size" hcl:"split_size"`
 	Archive             *string           `cty:"archive" hcl:"archive"`
 	Algorithm           *string           `cty:"algorithm" hcl:"algorithm"`
 }
@@ -48,6 +50,8 @@ func (*FlatConfig) HCL2Spec() map[string]hcldec.Spec {
 		"output":                     &hcldec.AttrSpec{Name: "output", Type: cty.String, Required: false},
 		"format":                     &hcldec.AttrSpec{Name: "format", Type: cty.String, Required: false},
 		"compression_level":          &hcldec.AttrSpec{Name: "compression_level", Type: cty.Number, Required: false},
+		"compression_threads":        &hcldec.AttrSpec{Name: "compression_threads", Type: cty.Number, Required: false},
+		"split_size":                 &hcldec.AttrSpec{Name: "split_size", Type: cty.String, Required: false},
 		"archive":                    &hcldec.AttrSpec{Name: "archive", Type: cty.String, Required: false},
 		"algorithm":                  &hcldec.AttrSpec{Name: "algorithm", Type: cty.String, Required: false},
 	}
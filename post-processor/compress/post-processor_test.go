@@ -3,6 +3,7 @@ package compress
 import (
 	"archive/tar"
 	"archive/zip"
+	"bytes"
 	"compress/gzip"
 	"context"
 	"fmt"
@@ -15,7 +16,9 @@ import (
 	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
 	"github.com/hashicorp/packer-plugin-sdk/template"
 	"github.com/hashicorp/packer/builder/file"
+	"github.com/klauspost/compress/zstd"
 	"github.com/pierrec/lz4"
+	"github.com/ulikunitz/xz"
 )
 
 func TestDetectFilename(t *testing.T) {
@@ -119,6 +122,52 @@ func TestCompressInterpolation(t *testing.T) {
 	}
 }
 
+func TestCompressSplit(t *testing.T) {
+	const config = `
+	{
+	    "post-processors": [
+	        {
+	            "type": "compress",
+	            "output": "package.gz",
+	            "split_size": "10"
+	        }
+	    ]
+	}
+	`
+
+	artifact := testArchive(t, config)
+	defer artifact.Destroy()
+
+	files := artifact.Files()
+	if len(files) < 3 {
+		t.Fatalf("Expected several parts and a manifest, found %v", files)
+	}
+	if manifest := files[len(files)-1]; manifest != "package.gz.sha256" {
+		t.Fatalf("Expected the manifest last, found %s", manifest)
+	}
+
+	var archive bytes.Buffer
+	for i, part := range files[:len(files)-1] {
+		if expected := fmt.Sprintf("package.gz.%03d", i); part != expected {
+			t.Fatalf("Expected part %s, found %s", expected, part)
+		}
+		data, err := ioutil.ReadFile(part)
+		if err != nil {
+			t.Fatalf("Unable to read %s: %s", part, err)
+		}
+		archive.Write(data)
+	}
+
+	gzipReader, err := gzip.NewReader(&archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := ioutil.ReadAll(gzipReader)
+	if string(data) != expectedFileContents {
+		t.Errorf("Expected:\n%s\nFound:\n%s\n", expectedFileContents, data)
+	}
+}
+
 // Test Helpers
 
 func setup(t *testing.T) (packersdk.Ui, packersdk.Artifact, error) {
@@ -233,6 +282,34 @@ func TestArchive(t *testing.T) {
 			lz4Reader := lz4.NewReader(archive)
 			return ioutil.ReadAll(lz4Reader)
 		},
+		"xz": func(archive *os.File) ([]byte, error) {
+			xzReader, err := xz.NewReader(archive)
+			if err != nil {
+				return nil, err
+			}
+			return ioutil.ReadAll(xzReader)
+		},
+		"zst": func(archive *os.File) ([]byte, error) {
+			zstdReader, err := zstd.NewReader(archive)
+			if err != nil {
+				return nil, err
+			}
+			defer zstdReader.Close()
+			return ioutil.ReadAll(zstdReader)
+		},
+		"tar.zst": func(archive *os.File) ([]byte, error) {
+			zstdReader, err := zstd.NewReader(archive)
+			if err != nil {
+				return nil, err
+			}
+			defer zstdReader.Close()
+			tarReader := tar.NewReader(zstdReader)
+			_, err = tarReader.Next()
+			if err != nil {
+				return nil, err
+			}
+			return ioutil.ReadAll(tarReader)
+		},
 	}
 
 	for format, unzip := range tc {
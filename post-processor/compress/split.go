@@ -0,0 +1,153 @@
+package compress
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// parseSplitSize parses a size in bytes, with an optional K, M, G or T unit
+// in powers of 1024, e.g. "100M" or "2GB".
+func parseSplitSize(size string) (int64, error) {
+	s := strings.TrimSuffix(strings.ToUpper(strings.TrimSpace(size)), "B")
+
+	multiplier := int64(1)
+	if s != "" {
+		switch s[len(s)-1] {
+		case 'K':
+			multiplier = 1 << 10
+		case 'M':
+			multiplier = 1 << 20
+		case 'G':
+			multiplier = 1 << 30
+		case 'T':
+			multiplier = 1 << 40
+		}
+		if multiplier > 1 {
+			s = s[:len(s)-1]
+		}
+	}
+
+	value, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("invalid size %q", size)
+	}
+	return value * multiplier, nil
+}
+
+// splitWriter writes to numbered parts of at most size bytes: path.000,
+// path.001 and so on. Once closed, the SHA256 of every part is written to a
+// manifest, path.sha256, in the format of sha256sum.
+type splitWriter struct {
+	path string
+	size int64
+
+	file    *os.File
+	written int64
+	hash    hash.Hash
+
+	parts    []string
+	sums     []string
+	manifest string
+	closed   bool
+}
+
+func newSplitWriter(path string, size int64) *splitWriter {
+	return &splitWriter{
+		path: path,
+		size: size,
+		hash: sha256.New(),
+	}
+}
+
+func (w *splitWriter) Write(p []byte) (int, error) {
+	n := 0
+	for len(p) > 0 {
+		if w.file == nil || w.written == w.size {
+			if err := w.nextPart(); err != nil {
+				return n, err
+			}
+		}
+
+		chunk := p
+		if left := w.size - w.written; int64(len(chunk)) > left {
+			chunk = chunk[:left]
+		}
+		m, err := w.file.Write(chunk)
+		w.hash.Write(chunk[:m])
+		w.written += int64(m)
+		n += m
+		p = p[m:]
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// Close closes the last part and writes the manifest of the parts.
+func (w *splitWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	// An empty archive is still written as one empty part
+	if w.file == nil {
+		if err := w.nextPart(); err != nil {
+			return err
+		}
+	}
+	if err := w.closePart(); err != nil {
+		return err
+	}
+
+	manifest := w.path + ".sha256"
+	f, err := os.Create(manifest)
+	if err != nil {
+		return fmt.Errorf("Unable to create manifest %s: %s", manifest, err)
+	}
+	for i, part := range w.parts {
+		if _, err := fmt.Fprintf(f, "%s  %s\n", w.sums[i], filepath.Base(part)); err != nil {
+			f.Close()
+			return fmt.Errorf("Failed to write manifest %s: %s", manifest, err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("Failed to write manifest %s: %s", manifest, err)
+	}
+	w.manifest = manifest
+	return nil
+}
+
+func (w *splitWriter) nextPart() error {
+	if err := w.closePart(); err != nil {
+		return err
+	}
+
+	part := fmt.Sprintf("%s.%03d", w.path, len(w.parts))
+	f, err := os.Create(part)
+	if err != nil {
+		return fmt.Errorf("Unable to create archive part %s: %s", part, err)
+	}
+	w.file = f
+	w.written = 0
+	w.hash.Reset()
+	w.parts = append(w.parts, part)
+	return nil
+}
+
+func (w *splitWriter) closePart() error {
+	if w.file == nil {
+		return nil
+	}
+	w.sums = append(w.sums, hex.EncodeToString(w.hash.Sum(nil)))
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
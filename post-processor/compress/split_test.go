@@ -0,0 +1,108 @@
+package compress
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseSplitSize(t *testing.T) {
+	tc := map[string]int64{
+		"512":   512,
+		"100k":  100 * 1024,
+		"100KB": 100 * 1024,
+		"4M":    4 * 1024 * 1024,
+		"2G":    2 * 1024 * 1024 * 1024,
+		"2gb":   2 * 1024 * 1024 * 1024,
+		"1T":    1024 * 1024 * 1024 * 1024,
+	}
+	for size, expected := range tc {
+		found, err := parseSplitSize(size)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", size, err)
+		}
+		if found != expected {
+			t.Errorf("%s: expected %d, found %d", size, expected, found)
+		}
+	}
+
+	for _, size := range []string{"", "M", "-1M", "0", "1.5G", "10X"} {
+		if _, err := parseSplitSize(size); err == nil {
+			t.Errorf("%q: expected an error", size)
+		}
+	}
+}
+
+func TestSplitWriter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "packer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	target := filepath.Join(dir, "package.tar.gz")
+	w := newSplitWriter(target, 10)
+	for _, chunk := range []string{"Hello", " world! This is split", " in parts"} {
+		if _, err := w.Write([]byte(chunk)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedParts := []string{"Hello worl", "d! This is", " split in ", "parts"}
+	if len(w.parts) != len(expectedParts) {
+		t.Fatalf("expected %d parts, found %v", len(expectedParts), w.parts)
+	}
+
+	var manifest strings.Builder
+	for i, expected := range expectedParts {
+		part := fmt.Sprintf("%s.%03d", target, i)
+		if w.parts[i] != part {
+			t.Errorf("expected part %s, found %s", part, w.parts[i])
+		}
+		found, err := ioutil.ReadFile(part)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(found) != expected {
+			t.Errorf("expected %q in %s, found %q", expected, part, found)
+		}
+		fmt.Fprintf(&manifest, "%x  %s\n", sha256.Sum256([]byte(expected)), filepath.Base(part))
+	}
+
+	if w.manifest != target+".sha256" {
+		t.Fatalf("bad manifest path: %s", w.manifest)
+	}
+	found, err := ioutil.ReadFile(w.manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(found) != manifest.String() {
+		t.Errorf("expected manifest:\n%s\nfound:\n%s", manifest.String(), found)
+	}
+}
+
+func TestSplitWriter_empty(t *testing.T) {
+	dir, err := ioutil.TempDir("", "packer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	w := newSplitWriter(filepath.Join(dir, "package.zip"), 10)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(w.parts) != 1 {
+		t.Fatalf("expected one empty part, found %v", w.parts)
+	}
+	if _, err := os.Stat(w.manifest); err != nil {
+		t.Fatal(err)
+	}
+}
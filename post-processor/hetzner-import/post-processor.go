@@ -0,0 +1,478 @@
+//go:generate packer-sdc mapstructure-to-hcl2 -type Config
+
+package hetznerimport
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/hcl/v2/hcldec"
+	"github.com/hashicorp/packer-plugin-sdk/common"
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+	"github.com/hashicorp/packer-plugin-sdk/retry"
+	"github.com/hashicorp/packer-plugin-sdk/template/config"
+	"github.com/hashicorp/packer-plugin-sdk/template/interpolate"
+	"github.com/hashicorp/packer-plugin-sdk/tmp"
+	"github.com/hashicorp/packer-plugin-sdk/uuid"
+	"github.com/hashicorp/packer/builder/hcloud"
+	"github.com/hashicorp/packer/post-processor/hetzner-import/version"
+	hcloudgo "github.com/hetznercloud/hcloud-go/hcloud"
+	"golang.org/x/crypto/ssh"
+)
+
+const BuilderId = "packer.post-processor.hetzner-import"
+
+const (
+	// The image the temporary server is created from. It is never booted
+	// for the import, as the server is reset into the rescue system first.
+	defaultServerImage = "ubuntu-20.04"
+
+	// The disk of the temporary server the image is written to.
+	serverDisk = "/dev/sda"
+)
+
+type Config struct {
+	common.PackerConfig `mapstructure:",squash"`
+
+	HCloudToken  string        `mapstructure:"token"`
+	Endpoint     string        `mapstructure:"endpoint"`
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+
+	Location   string `mapstructure:"location"`
+	ServerType string `mapstructure:"server_type"`
+	Format     string `mapstructure:"format"`
+
+	SnapshotName   string            `mapstructure:"snapshot_name"`
+	SnapshotLabels map[string]string `mapstructure:"snapshot_labels"`
+
+	SSHTimeout time.Duration `mapstructure:"ssh_timeout"`
+
+	ctx interpolate.Context
+}
+
+type PostProcessor struct {
+	config Config
+}
+
+func (p *PostProcessor) ConfigSpec() hcldec.ObjectSpec { return p.config.FlatMapstructure().HCL2Spec() }
+
+func (p *PostProcessor) Configure(raws ...interface{}) error {
+	err := config.Decode(&p.config, &config.DecodeOpts{
+		PluginType:         BuilderId,
+		Interpolate:        true,
+		InterpolateContext: &p.config.ctx,
+		InterpolateFilter: &interpolate.RenderFilter{
+			Exclude: []string{"snapshot_name"},
+		},
+	}, raws...)
+	if err != nil {
+		return err
+	}
+
+	if p.config.HCloudToken == "" {
+		p.config.HCloudToken = os.Getenv("HCLOUD_TOKEN")
+	}
+	if p.config.Endpoint == "" {
+		if os.Getenv("HCLOUD_ENDPOINT") != "" {
+			p.config.Endpoint = os.Getenv("HCLOUD_ENDPOINT")
+		} else {
+			p.config.Endpoint = hcloudgo.Endpoint
+		}
+	}
+	if p.config.PollInterval == 0 {
+		p.config.PollInterval = 500 * time.Millisecond
+	}
+
+	if p.config.ServerType == "" {
+		p.config.ServerType = "cx11"
+	}
+
+	if p.config.SnapshotName == "" {
+		p.config.SnapshotName = "packer-import-{{timestamp}}"
+	}
+
+	if p.config.SSHTimeout == 0 {
+		p.config.SSHTimeout = 5 * time.Minute
+	}
+
+	errs := new(packersdk.MultiError)
+
+	if err = interpolate.Validate(p.config.SnapshotName, &p.config.ctx); err != nil {
+		errs = packersdk.MultiErrorAppend(
+			errs, fmt.Errorf("Error parsing snapshot_name template: %s", err))
+	}
+
+	requiredArgs := map[string]*string{
+		"token":    &p.config.HCloudToken,
+		"location": &p.config.Location,
+	}
+	for key, ptr := range requiredArgs {
+		if *ptr == "" {
+			errs = packersdk.MultiErrorAppend(
+				errs, fmt.Errorf("%s must be set", key))
+		}
+	}
+
+	switch p.config.Format {
+	case "", "raw", "qcow2":
+	default:
+		errs = packersdk.MultiErrorAppend(
+			errs, fmt.Errorf("format must be one of \"raw\" or \"qcow2\", got %q", p.config.Format))
+	}
+
+	if len(errs.Errors) > 0 {
+		return errs
+	}
+
+	packersdk.LogSecretFilter.Set(p.config.HCloudToken)
+	log.Println(p.config)
+	return nil
+}
+
+func (p *PostProcessor) PostProcess(ctx context.Context, ui packersdk.Ui, artifact packersdk.Artifact) (packersdk.Artifact, bool, bool, error) {
+	var err error
+
+	generatedData := artifact.State("generated_data")
+	if generatedData == nil {
+		// Make sure it's not a nil map so we can assign to it later.
+		generatedData = make(map[string]interface{})
+	}
+	p.config.ctx.Data = generatedData
+
+	snapshotName, err := interpolate.Render(p.config.SnapshotName, &p.config.ctx)
+	if err != nil {
+		return nil, false, false, fmt.Errorf("Error rendering snapshot_name template: %s", err)
+	}
+	log.Printf("Rendered snapshot_name as %s", snapshotName)
+
+	log.Println("Looking for image in artifact")
+	source, err := extractImageArtifact(artifact.Files())
+	if err != nil {
+		return nil, false, false, err
+	}
+
+	format := p.config.Format
+	if format == "" {
+		format, err = detectFormat(artifact, source)
+		if err != nil {
+			return nil, false, false, err
+		}
+	}
+
+	if format == "qcow2" {
+		// The rescue system runs from memory, which can't hold the image:
+		// it is converted locally and streamed as raw instead.
+		dir, err := tmp.Dir("packer-hetzner-import")
+		if err != nil {
+			return nil, false, false, fmt.Errorf("Error creating temporary directory: %s", err)
+		}
+		defer os.RemoveAll(dir)
+
+		ui.Say(fmt.Sprintf("Converting qcow2 image %s to raw...", source))
+		source, err = convertImage(ctx, source, filepath.Join(dir, "packer-import.raw"))
+		if err != nil {
+			return nil, false, false, err
+		}
+	}
+
+	client := hcloudgo.NewClient(
+		hcloudgo.WithToken(p.config.HCloudToken),
+		hcloudgo.WithEndpoint(p.config.Endpoint),
+		hcloudgo.WithPollInterval(p.config.PollInterval),
+		hcloudgo.WithApplication("hcloud-packer", version.HetznerImportPluginVersion.FormattedVersion()),
+	)
+
+	ui.Say("Creating temporary ssh key for import server...")
+	signer, sshKey, err := createSSHKey(ctx, client)
+	if err != nil {
+		return nil, false, false, err
+	}
+	defer func() {
+		ui.Say("Deleting temporary ssh key...")
+		if _, err := client.SSHKey.Delete(context.TODO(), sshKey); err != nil {
+			ui.Error(fmt.Sprintf(
+				"Error cleaning up ssh key. Please delete the key manually: %s", err))
+		}
+	}()
+
+	ui.Say("Creating temporary import server...")
+	server, err := createServer(ctx, client, p.config, sshKey)
+	if server != nil {
+		defer func() {
+			ui.Say("Destroying import server...")
+			if _, err := client.Server.Delete(context.TODO(), server); err != nil {
+				ui.Error(fmt.Sprintf(
+					"Error destroying server. Please destroy it manually: %s", err))
+			}
+		}()
+	}
+	if err != nil {
+		return nil, false, false, err
+	}
+
+	ui.Say("Booting import server into the rescue system...")
+	if err := bootRescue(ctx, client, server, sshKey); err != nil {
+		return nil, false, false, fmt.Errorf("Error enabling rescue mode: %s", err)
+	}
+
+	address := net.JoinHostPort(server.PublicNet.IPv4.IP.String(), "22")
+	sshClient, err := dialSSH(ctx, address, signer, p.config.SSHTimeout)
+	if err != nil {
+		return nil, false, false, fmt.Errorf("Error connecting to import server: %s", err)
+	}
+	defer sshClient.Close()
+
+	ui.Say(fmt.Sprintf("Writing image %s to the import server disk...", source))
+	if err := writeImage(ui, sshClient, source); err != nil {
+		return nil, false, false, err
+	}
+
+	ui.Say("Shutting down import server...")
+	action, _, err := client.Server.Poweroff(ctx, server)
+	if err != nil {
+		return nil, false, false, fmt.Errorf("Error stopping server: %s", err)
+	}
+	if err := waitForAction(ctx, client, action); err != nil {
+		return nil, false, false, fmt.Errorf("Error stopping server: %s", err)
+	}
+
+	ui.Say(fmt.Sprintf("Creating snapshot %s...", snapshotName))
+	ui.Message("This can take some time")
+	result, _, err := client.Server.CreateImage(ctx, server, &hcloudgo.ServerCreateImageOpts{
+		Type:        hcloudgo.ImageTypeSnapshot,
+		Labels:      p.config.SnapshotLabels,
+		Description: hcloudgo.String(snapshotName),
+	})
+	if err != nil {
+		return nil, false, false, fmt.Errorf("Error creating snapshot: %s", err)
+	}
+	if err := waitForAction(ctx, client, result.Action); err != nil {
+		return nil, false, false, fmt.Errorf("Error creating snapshot: %s", err)
+	}
+	ui.Message(fmt.Sprintf("Snapshot %s created with ID %d", snapshotName, result.Image.ID))
+
+	log.Printf("Adding created snapshot ID %v to output artifacts", result.Image.ID)
+	artifact = &hcloud.Artifact{
+		SnapshotName: snapshotName,
+		SnapshotId:   result.Image.ID,
+		HcloudClient: client,
+		StateData:    map[string]interface{}{"generated_data": generatedData},
+	}
+
+	return artifact, false, false, nil
+}
+
+func extractImageArtifact(artifacts []string) (string, error) {
+	artifactCount := len(artifacts)
+
+	if artifactCount == 0 {
+		return "", fmt.Errorf("no artifacts were provided")
+	}
+
+	if artifactCount == 1 {
+		return artifacts[0], nil
+	}
+
+	validSuffix := []string{"raw", "img", "qcow2"}
+	for _, path := range artifacts {
+		for _, suffix := range validSuffix {
+			if strings.HasSuffix(path, suffix) {
+				return path, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("no valid image file found")
+}
+
+var qcow2Magic = []byte{'Q', 'F', 'I', 0xfb}
+
+// detectFormat returns the format of the image at source: the disk type of
+// artifacts that describe it, like the ones of the QEMU builder, or else
+// qcow2 for images starting with the qcow2 magic, falling back to raw.
+func detectFormat(artifact packersdk.Artifact, source string) (string, error) {
+	if diskType, _ := artifact.State("diskType").(string); diskType != "" {
+		switch diskType {
+		case "raw", "qcow2":
+			return diskType, nil
+		default:
+			return "", fmt.Errorf("Unsupported disk type %q, expected raw or qcow2", diskType)
+		}
+	}
+
+	f, err := os.Open(source)
+	if err != nil {
+		return "", fmt.Errorf("Failed to open %s: %s", source, err)
+	}
+	defer f.Close()
+
+	header := make([]byte, len(qcow2Magic))
+	if _, err := io.ReadFull(f, header); err == nil && bytes.Equal(header, qcow2Magic) {
+		return "qcow2", nil
+	}
+	log.Printf("Image %s is not qcow2, assuming raw", source)
+	return "raw", nil
+}
+
+// convertImageArgs returns the arguments of the qemu-img command converting
+// the qcow2 image at source to a raw image at target.
+func convertImageArgs(source, target string) []string {
+	return []string{"convert", "-f", "qcow2", "-O", "raw", source, target}
+}
+
+// convertImage converts the qcow2 image at source to a raw image at target
+// with the local qemu-img, and returns target.
+func convertImage(ctx context.Context, source, target string) (string, error) {
+	if _, err := exec.LookPath("qemu-img"); err != nil {
+		return "", fmt.Errorf("qemu-img is required to import qcow2 images: %s", err)
+	}
+
+	args := convertImageArgs(source, target)
+	log.Printf("Running qemu-img %s", strings.Join(args, " "))
+	if out, err := exec.CommandContext(ctx, "qemu-img", args...).CombinedOutput(); err != nil {
+		return "", fmt.Errorf("Failed to convert %s to raw: %s: %s", source, err, out)
+	}
+	return target, nil
+}
+
+// writeCommand is the command run in the rescue system to write a raw image
+// read from stdin to the server disk.
+var writeCommand = fmt.Sprintf("dd of=%s bs=4M conv=fsync", serverDisk)
+
+func createSSHKey(ctx context.Context, client *hcloudgo.Client) (ssh.Signer, *hcloudgo.SSHKey, error) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error generating RSA key: %s", err)
+	}
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error generating public key: %s", err)
+	}
+
+	key, _, err := client.SSHKey.Create(ctx, hcloudgo.SSHKeyCreateOpts{
+		Name:      fmt.Sprintf("packer-import-%s", uuid.TimeOrderedUUID()),
+		PublicKey: string(ssh.MarshalAuthorizedKey(signer.PublicKey())),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error creating temporary SSH key: %s", err)
+	}
+	return signer, key, nil
+}
+
+// createServer creates the temporary server the image is written to. The
+// server is returned as soon as it exists, so that it can be cleaned up even
+// if waiting for it fails.
+func createServer(ctx context.Context, client *hcloudgo.Client, c Config, sshKey *hcloudgo.SSHKey) (*hcloudgo.Server, error) {
+	result, _, err := client.Server.Create(ctx, hcloudgo.ServerCreateOpts{
+		Name:       fmt.Sprintf("packer-import-%s", uuid.TimeOrderedUUID()),
+		ServerType: &hcloudgo.ServerType{Name: c.ServerType},
+		Image:      &hcloudgo.Image{Name: defaultServerImage},
+		SSHKeys:    []*hcloudgo.SSHKey{sshKey},
+		Location:   &hcloudgo.Location{Name: c.Location},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error creating server: %s", err)
+	}
+
+	if err := waitForAction(ctx, client, result.Action); err != nil {
+		return result.Server, fmt.Errorf("Error creating server: %s", err)
+	}
+	for _, nextAction := range result.NextActions {
+		if err := waitForAction(ctx, client, nextAction); err != nil {
+			return result.Server, fmt.Errorf("Error creating server: %s", err)
+		}
+	}
+	return result.Server, nil
+}
+
+func bootRescue(ctx context.Context, client *hcloudgo.Client, server *hcloudgo.Server, sshKey *hcloudgo.SSHKey) error {
+	res, _, err := client.Server.EnableRescue(ctx, server, hcloudgo.ServerEnableRescueOpts{
+		Type:    hcloudgo.ServerRescueTypeLinux64,
+		SSHKeys: []*hcloudgo.SSHKey{sshKey},
+	})
+	if err != nil {
+		return err
+	}
+	if err := waitForAction(ctx, client, res.Action); err != nil {
+		return err
+	}
+
+	action, _, err := client.Server.Reset(ctx, server)
+	if err != nil {
+		return err
+	}
+	return waitForAction(ctx, client, action)
+}
+
+func dialSSH(ctx context.Context, address string, signer ssh.Signer, timeout time.Duration) (*ssh.Client, error) {
+	sshConfig := &ssh.ClientConfig{
+		User: "root",
+		Auth: []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		// The rescue system generates new host keys on every boot.
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         30 * time.Second,
+	}
+
+	var client *ssh.Client
+	err := retry.Config{
+		StartTimeout: timeout,
+		RetryDelay:   func() time.Duration { return 5 * time.Second },
+	}.Run(ctx, func(ctx context.Context) error {
+		var err error
+		client, err = ssh.Dial("tcp", address, sshConfig)
+		if err != nil {
+			log.Printf("Waiting for SSH on %s: %s", address, err)
+		}
+		return err
+	})
+	return client, err
+}
+
+func writeImage(ui packersdk.Ui, client *ssh.Client, source string) error {
+	f, err := os.Open(source)
+	if err != nil {
+		return fmt.Errorf("Failed to open %s: %s", source, err)
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("Failed to stat %s: %s", source, err)
+	}
+
+	session, err := client.NewSession()
+	if err != nil {
+		return fmt.Errorf("Error opening SSH session: %s", err)
+	}
+	defer session.Close()
+
+	stdin := ui.TrackProgress(filepath.Base(source), 0, fi.Size(), f)
+	defer stdin.Close()
+	session.Stdin = stdin
+
+	output := new(strings.Builder)
+	session.Stdout = output
+	session.Stderr = output
+
+	log.Printf("Running %q on the import server", writeCommand)
+	if err := session.Run(writeCommand); err != nil {
+		return fmt.Errorf("Failed to write %s to the server disk: %s: %s", source, err, output.String())
+	}
+	return nil
+}
+
+func waitForAction(ctx context.Context, client *hcloudgo.Client, action *hcloudgo.Action) error {
+	_, errCh := client.Action.WatchProgress(ctx, action)
+	return <-errCh
+}
@@ -0,0 +1,131 @@
+package hetznerimport
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+)
+
+func TestPostProcessor_ImplementsPostProcessor(t *testing.T) {
+	var _ packersdk.PostProcessor = new(PostProcessor)
+}
+
+func TestPostProcessor_Configure(t *testing.T) {
+	tt := []struct {
+		Name          string
+		Config        map[string]interface{}
+		ExpectedError string
+	}{
+		{Name: "Defaults", Config: map[string]interface{}{"token": "secret", "location": "fsn1"}},
+		{Name: "MissingToken", Config: map[string]interface{}{"location": "fsn1"}, ExpectedError: "token must be set"},
+		{Name: "MissingLocation", Config: map[string]interface{}{"token": "secret"}, ExpectedError: "location must be set"},
+		{Name: "BadFormat", Config: map[string]interface{}{"token": "secret", "location": "fsn1", "format": "vmdk"}, ExpectedError: "format must be one of"},
+	}
+
+	defer os.Setenv("HCLOUD_TOKEN", os.Getenv("HCLOUD_TOKEN"))
+	os.Unsetenv("HCLOUD_TOKEN")
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			var p PostProcessor
+			err := p.Configure(tc.Config)
+			if tc.ExpectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				if p.config.ServerType != "cx11" {
+					t.Errorf("expected default server_type cx11, got %q", p.config.ServerType)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.ExpectedError) {
+				t.Fatalf("expected error containing %q, got %v", tc.ExpectedError, err)
+			}
+		})
+	}
+}
+
+func TestPostProcessor_ImageArtifactExtraction(t *testing.T) {
+	tt := []struct {
+		Name          string
+		Source        string
+		Artifacts     []string
+		ExpectedError string
+	}{
+		{Name: "EmptyArtifacts", ExpectedError: "no artifacts were provided"},
+		{Name: "SingleArtifact", Source: "Sample.img", Artifacts: []string{"Sample.img"}},
+		{Name: "SupportedArtifact", Source: "disk.qcow2", Artifacts: []string{"Sample", "disk.qcow2"}},
+		{Name: "NonSupportedArtifact", Artifacts: []string{"Sample", "disk.vmdk"}, ExpectedError: "no valid image file found"},
+	}
+
+	for _, tc := range tt {
+		source, err := extractImageArtifact(tc.Artifacts)
+
+		if tc.Source != source {
+			t.Errorf("expected the source to be %q, but got %q", tc.Source, source)
+		}
+
+		if err != nil && (tc.ExpectedError != err.Error()) {
+			t.Errorf("unexpected error received; expected %q, but got %q", tc.ExpectedError, err.Error())
+		}
+	}
+}
+
+func TestPostProcessor_detectFormat(t *testing.T) {
+	dir, err := ioutil.TempDir("", "packer-hetzner-import")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	qcow2 := filepath.Join(dir, "disk")
+	if err := ioutil.WriteFile(qcow2, []byte("QFI\xfb\x00\x00\x00\x03"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	raw := filepath.Join(dir, "disk.qcow2")
+	if err := ioutil.WriteFile(raw, make([]byte, 512), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tt := []struct {
+		Name          string
+		DiskType      string
+		Source        string
+		Expected      string
+		ExpectedError bool
+	}{
+		{Name: "DiskTypeQcow2", DiskType: "qcow2", Source: raw, Expected: "qcow2"},
+		{Name: "DiskTypeRaw", DiskType: "raw", Source: qcow2, Expected: "raw"},
+		{Name: "DiskTypeUnsupported", DiskType: "vmdk", Source: raw, ExpectedError: true},
+		{Name: "Qcow2Magic", Source: qcow2, Expected: "qcow2"},
+		{Name: "Raw", Source: raw, Expected: "raw"},
+		{Name: "MissingImage", Source: filepath.Join(dir, "missing"), ExpectedError: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			artifact := &packersdk.MockArtifact{StateValues: map[string]interface{}{}}
+			if tc.DiskType != "" {
+				artifact.StateValues["diskType"] = tc.DiskType
+			}
+			format, err := detectFormat(artifact, tc.Source)
+			if (err != nil) != tc.ExpectedError {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if format != tc.Expected {
+				t.Errorf("expected %q, got %q", tc.Expected, format)
+			}
+		})
+	}
+}
+
+func TestPostProcessor_convertImageArgs(t *testing.T) {
+	expected := "convert -f qcow2 -O raw disk.qcow2 /tmp/packer-import.raw"
+	if args := strings.Join(convertImageArgs("disk.qcow2", "/tmp/packer-import.raw"), " "); args != expected {
+		t.Errorf("expected %q, got %q", expected, args)
+	}
+}
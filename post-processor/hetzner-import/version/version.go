@@ -0,0 +1,13 @@
+package version
+
+import (
+	"github.com/hashicorp/packer-plugin-sdk/version"
+	packerVersion "github.com/hashicorp/packer/version"
+)
+
+var HetznerImportPluginVersion *version.PluginVersion
+
+func init() {
+	HetznerImportPluginVersion = version.InitializePluginVersion(
+		packerVersion.Version, packerVersion.VersionPrerelease)
+}
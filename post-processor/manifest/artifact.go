@@ -10,13 +10,18 @@ type ArtifactFile struct {
 }
 
 type Artifact struct {
-	BuildName     string            `json:"name"`
-	BuilderType   string            `json:"builder_type"`
-	BuildTime     int64             `json:"build_time,omitempty"`
-	ArtifactFiles []ArtifactFile    `json:"files"`
-	ArtifactId    string            `json:"artifact_id"`
-	PackerRunUUID string            `json:"packer_run_uuid"`
-	CustomData    map[string]string `json:"custom_data"`
+	BuildName      string                 `json:"name"`
+	BuilderType    string                 `json:"builder_type"`
+	BuildTime      int64                  `json:"build_time,omitempty"`
+	BuildStartTime int64                  `json:"build_start_time,omitempty"`
+	BuildEndTime   int64                  `json:"build_end_time,omitempty"`
+	BuildDuration  float64                `json:"build_duration,omitempty"`
+	ArtifactFiles  []ArtifactFile         `json:"files"`
+	ArtifactId     string                 `json:"artifact_id"`
+	GeneratedData  map[string]interface{} `json:"generated_data,omitempty"`
+	ArtifactState  map[string]interface{} `json:"state,omitempty"`
+	PackerRunUUID  string                 `json:"packer_run_uuid"`
+	CustomData     map[string]string      `json:"custom_data"`
 }
 
 func (a *Artifact) BuilderId() string {
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"math"
 	"os"
 	"path/filepath"
 	"time"
@@ -29,22 +30,53 @@ type Config struct {
 	// Write only filename without the path to the manifest file. This defaults
 	// to false.
 	StripPath bool `mapstructure:"strip_path"`
-	// Don't write the `build_time` field from the output.
+	// Don't write the `build_time`, `build_start_time`, `build_end_time` and
+	// `build_duration` fields from the output.
 	StripTime bool `mapstructure:"strip_time"`
 	// Arbitrary data to add to the manifest. This is a [template
 	// engine](https://packer.io/docs/templates/legacy_json_templates/engine.html). Therefore, you
 	// may use user variables and template functions in this field.
 	CustomData map[string]string `mapstructure:"custom_data"`
-	ctx        interpolate.Context
+	// The format of the manifest file, either `json` or `jsonl`. This
+	// defaults to `json`, where the manifest is a JSON document that is
+	// rewritten to add each build. With `jsonl`, one JSON record is appended
+	// per build, as a line of the manifest file. Appending never rewrites
+	// previous records, so several concurrent builds can share a manifest
+	// and it can be tailed by CI systems. A `jsonl` manifest is never
+	// truncated, even with `-force`.
+	OutputFormat string `mapstructure:"output_format"`
+	// The keys of the state of the artifact to record in the `state` field of
+	// the manifest, such as `atlas.artifact.metadata`. Artifacts can not list the
+	// keys of their state, so the keys to record have to be named. Keys with no
+	// value in the state of the artifact are not recorded.
+	StateKeys []string `mapstructure:"state_keys"`
+	ctx       interpolate.Context
 }
 
 type PostProcessor struct {
 	config Config
 }
 
+const (
+	// SchemaVersion is the version of the format of the manifest. It is
+	// incremented with any change to the fields of a manifest that is not
+	// backwards compatible.
+	SchemaVersion = 1
+
+	outputFormatJSON  = "json"
+	outputFormatJSONL = "jsonl"
+)
+
 type ManifestFile struct {
-	Builds      []Artifact `json:"builds"`
-	LastRunUUID string     `json:"last_run_uuid"`
+	SchemaVersion int        `json:"schema_version"`
+	Builds        []Artifact `json:"builds"`
+	LastRunUUID   string     `json:"last_run_uuid"`
+}
+
+// ManifestRecord is a line of a manifest in the jsonl format.
+type ManifestRecord struct {
+	SchemaVersion int `json:"schema_version"`
+	Artifact
 }
 
 func (p *PostProcessor) ConfigSpec() hcldec.ObjectSpec { return p.config.FlatMapstructure().HCL2Spec() }
@@ -70,6 +102,15 @@ func (p *PostProcessor) Configure(raws ...interface{}) error {
 		return fmt.Errorf("Error parsing target template: %s", err)
 	}
 
+	switch p.config.OutputFormat {
+	case "":
+		p.config.OutputFormat = outputFormatJSON
+	case outputFormatJSON, outputFormatJSONL:
+	default:
+		return fmt.Errorf("output_format must be one of %q or %q, got %q",
+			outputFormatJSON, outputFormatJSONL, p.config.OutputFormat)
+	}
+
 	return nil
 }
 
@@ -111,9 +152,24 @@ func (p *PostProcessor) PostProcess(ctx context.Context, ui packersdk.Ui, source
 	artifact.CustomData = p.config.CustomData
 	artifact.BuilderType = p.config.PackerBuilderType
 	artifact.BuildName = p.config.PackerBuildName
-	artifact.BuildTime = time.Now().Unix()
+	artifact.GeneratedData = manifestGeneratedData(generatedData)
+	artifact.ArtifactState = manifestState(source, p.config.StateKeys)
+	endTime := time.Now()
+	artifact.BuildTime = endTime.Unix()
+	// The core sets the time the build started, the build ends once its
+	// artifact is recorded.
+	if started, ok := source.State("build_start_time").(string); ok {
+		if startTime, err := time.Parse(time.RFC3339Nano, started); err == nil {
+			artifact.BuildStartTime = startTime.Unix()
+			artifact.BuildEndTime = endTime.Unix()
+			artifact.BuildDuration = math.Round(endTime.Sub(startTime).Seconds()*1000) / 1000
+		}
+	}
 	if p.config.StripTime {
 		artifact.BuildTime = 0
+		artifact.BuildStartTime = 0
+		artifact.BuildEndTime = 0
+		artifact.BuildDuration = 0
 	}
 	// Since each post-processor runs in a different process we need a way to
 	// coordinate between various post-processors in a single packer run. We do
@@ -124,6 +180,13 @@ func (p *PostProcessor) PostProcess(ctx context.Context, ui packersdk.Ui, source
 	// the file before we proceed.
 	artifact.PackerRunUUID = os.Getenv("PACKER_RUN_UUID")
 
+	if p.config.OutputFormat == outputFormatJSONL {
+		if err := p.appendRecord(artifact); err != nil {
+			return source, true, true, err
+		}
+		return source, true, true, nil
+	}
+
 	// Create a lock file with exclusive access. If this fails we will retry
 	// after a delay.
 	lockFilename := p.config.OutputPath + ".lock"
@@ -159,6 +222,7 @@ func (p *PostProcessor) PostProcess(ctx context.Context, ui packersdk.Ui, source
 	}
 
 	// Add the current artifact to the manifest file
+	manifestFile.SchemaVersion = SchemaVersion
 	manifestFile.Builds = append(manifestFile.Builds, *artifact)
 	manifestFile.LastRunUUID = os.Getenv("PACKER_RUN_UUID")
 
@@ -176,6 +240,82 @@ func (p *PostProcessor) PostProcess(ctx context.Context, ui packersdk.Ui, source
 	return source, true, true, nil
 }
 
+// appendRecord appends the artifact as one line to the jsonl manifest. The
+// line is written with a single write to a file opened in append mode, so
+// concurrent builds don't need to lock the manifest.
+func (p *PostProcessor) appendRecord(artifact *Artifact) error {
+	record, err := json.Marshal(ManifestRecord{
+		SchemaVersion: SchemaVersion,
+		Artifact:      *artifact,
+	})
+	if err != nil {
+		return fmt.Errorf("Unable to marshal JSON %s", err)
+	}
+
+	f, err := os.OpenFile(p.config.OutputPath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0664)
+	if err != nil {
+		return fmt.Errorf("Unable to open %s for writing: %s", p.config.OutputPath, err)
+	}
+	if _, err := f.Write(append(record, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("Unable to write %s: %s", p.config.OutputPath, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("Unable to write %s: %s", p.config.OutputPath, err)
+	}
+	return nil
+}
+
+// sensitiveGeneratedData are the keys of the data generated by builders that
+// hold secrets and must not be written to the manifest.
+var sensitiveGeneratedData = []string{
+	"Password",
+	"SSHPrivateKey",
+	"WinRMPassword",
+}
+
+// manifestGeneratedData returns the data generated by the builder, as found in
+// the state of its artifact, without its secrets.
+func manifestGeneratedData(data interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	// The generated data of artifacts sent through RPC has interface keys
+	switch generatedData := data.(type) {
+	case map[string]interface{}:
+		for key, value := range generatedData {
+			result[key] = value
+		}
+	case map[interface{}]interface{}:
+		for key, value := range generatedData {
+			if key, ok := key.(string); ok {
+				result[key] = value
+			}
+		}
+	}
+	for _, key := range sensitiveGeneratedData {
+		delete(result, key)
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// manifestState returns the values of the keys of the state of the artifact.
+func manifestState(source packersdk.Artifact, keys []string) map[string]interface{} {
+	var state map[string]interface{}
+	for _, key := range keys {
+		value := source.State(key)
+		if value == nil {
+			continue
+		}
+		if state == nil {
+			state = make(map[string]interface{}, len(keys))
+		}
+		state[key] = value
+	}
+	return state
+}
+
 func createInterpolatedCustomData(config *Config, customData string) (string, error) {
 	interpolatedCmd, err := interpolate.Render(customData, &config.ctx)
 	if err != nil {
@@ -22,6 +22,8 @@ type FlatConfig struct {
 	StripPath           *bool             `mapstructure:"strip_path" cty:"strip_path" hcl:"strip_path"`
 	StripTime           *bool             `mapstructure:"strip_time" cty:"strip_time" hcl:"strip_time"`
 	CustomData          map[string]string `mapstructure:"custom_data" cty:"custom_data" hcl:"custom_data"`
+	OutputFormat        *string           `mapstructure:"output_format" cty:"output_format" hcl:"output_format"`
+	StateKeys           []string          `mapstructure:"state_keys" cty:"state_keys" hcl:"state_keys"`
 }
 
 // FlatMapstructure returns a new FlatConfig.
@@ -48,6 +50,8 @@ func (*FlatConfig) HCL2Spec() map[string]hcldec.Spec {
 		"strip_path":                 &hcldec.AttrSpec{Name: "strip_path", Type: cty.Bool, Required: false},
 		"strip_time":                 &hcldec.AttrSpec{Name: "strip_time", Type: cty.Bool, Required: false},
 		"custom_data":                &hcldec.AttrSpec{Name: "custom_data", Type: cty.Map(cty.String), Required: false},
+		"output_format":              &hcldec.AttrSpec{Name: "output_format", Type: cty.String, Required: false},
+		"state_keys":                 &hcldec.AttrSpec{Name: "state_keys", Type: cty.List(cty.String), Required: false},
 	}
 	return s
 }
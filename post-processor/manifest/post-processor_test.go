@@ -0,0 +1,179 @@
+package manifest
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+)
+
+func TestPostProcessor_Configure_outputFormat(t *testing.T) {
+	var p PostProcessor
+	if err := p.Configure(map[string]interface{}{}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if p.config.OutputFormat != "json" {
+		t.Fatalf("bad default output_format: %s", p.config.OutputFormat)
+	}
+
+	p = PostProcessor{}
+	if err := p.Configure(map[string]interface{}{"output_format": "yaml"}); err == nil {
+		t.Fatal("should fail with an unknown output_format")
+	}
+}
+
+func TestPostProcessor_PostProcess_json(t *testing.T) {
+	dir, err := ioutil.TempDir("", "packer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	output := filepath.Join(dir, "manifest.json")
+
+	for i := 0; i < 2; i++ {
+		var p PostProcessor
+		if err := p.Configure(map[string]interface{}{"output": output}); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if _, _, _, err := p.PostProcess(context.Background(), packersdk.TestUi(t), &packersdk.MockArtifact{}); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	contents, err := ioutil.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var manifest ManifestFile
+	if err := json.Unmarshal(contents, &manifest); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if manifest.SchemaVersion != SchemaVersion {
+		t.Fatalf("bad schema_version: %d", manifest.SchemaVersion)
+	}
+	if len(manifest.Builds) != 2 {
+		t.Fatalf("expected 2 builds, got %d", len(manifest.Builds))
+	}
+}
+
+func TestPostProcessor_PostProcess_jsonl(t *testing.T) {
+	dir, err := ioutil.TempDir("", "packer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	output := filepath.Join(dir, "manifest.jsonl")
+
+	for _, id := range []string{"first", "second"} {
+		var p PostProcessor
+		if err := p.Configure(map[string]interface{}{
+			"output":        output,
+			"output_format": "jsonl",
+			"packer_force":  true,
+		}); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		artifact := &packersdk.MockArtifact{IdValue: id}
+		if _, _, _, err := p.PostProcess(context.Background(), packersdk.TestUi(t), artifact); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	f, err := os.Open(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var records []ManifestRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record ManifestRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("bad record %q: %s", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+	for i, id := range []string{"first", "second"} {
+		if records[i].SchemaVersion != SchemaVersion {
+			t.Fatalf("bad schema_version: %d", records[i].SchemaVersion)
+		}
+		if records[i].ArtifactId != id {
+			t.Fatalf("bad artifact_id: %s", records[i].ArtifactId)
+		}
+	}
+}
+
+func TestPostProcessor_PostProcess_buildMetadata(t *testing.T) {
+	dir, err := ioutil.TempDir("", "packer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	output := filepath.Join(dir, "manifest.json")
+
+	var p PostProcessor
+	if err := p.Configure(map[string]interface{}{
+		"output":              output,
+		"packer_builder_type": "null",
+		"state_keys":          []string{"atlas.artifact.metadata", "missing"},
+	}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	start := time.Now().Add(-90 * time.Second)
+	artifact := &packersdk.MockArtifact{
+		StateValues: map[string]interface{}{
+			"build_start_time": start.Format(time.RFC3339Nano),
+			"generated_data": map[interface{}]interface{}{
+				"Host":          "127.0.0.1",
+				"SSHPrivateKey": "secret",
+			},
+			"atlas.artifact.metadata": map[string]interface{}{"region": "cn-bj2"},
+		},
+	}
+	if _, _, _, err := p.PostProcess(context.Background(), packersdk.TestUi(t), artifact); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	contents, err := ioutil.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var manifest ManifestFile
+	if err := json.Unmarshal(contents, &manifest); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	build := manifest.Builds[0]
+	if build.BuilderType != "null" {
+		t.Fatalf("bad builder_type: %s", build.BuilderType)
+	}
+	if build.BuildStartTime != start.Unix() {
+		t.Fatalf("bad build_start_time: %d", build.BuildStartTime)
+	}
+	if build.BuildEndTime < build.BuildStartTime+90 {
+		t.Fatalf("bad build_end_time: %d", build.BuildEndTime)
+	}
+	if build.BuildDuration < 90 || build.BuildDuration > 100 {
+		t.Fatalf("bad build_duration: %f", build.BuildDuration)
+	}
+	expected := map[string]interface{}{"Host": "127.0.0.1"}
+	if !reflect.DeepEqual(build.GeneratedData, expected) {
+		t.Fatalf("bad generated_data: %#v", build.GeneratedData)
+	}
+	expected = map[string]interface{}{
+		"atlas.artifact.metadata": map[string]interface{}{"region": "cn-bj2"},
+	}
+	if !reflect.DeepEqual(build.ArtifactState, expected) {
+		t.Fatalf("bad state: %#v", build.ArtifactState)
+	}
+}
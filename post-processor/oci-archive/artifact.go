@@ -0,0 +1,39 @@
+package ociarchive
+
+import (
+	"fmt"
+	"os"
+)
+
+const BuilderId = "packer.post-processor.oci-archive"
+
+type Artifact struct {
+	Path string
+
+	// RefNames are the names of the images of the layout.
+	RefNames []string
+}
+
+func (a *Artifact) BuilderId() string {
+	return BuilderId
+}
+
+func (*Artifact) Id() string {
+	return ""
+}
+
+func (a *Artifact) Files() []string {
+	return []string{a.Path}
+}
+
+func (a *Artifact) String() string {
+	return fmt.Sprintf("OCI archive: %s", a.Path)
+}
+
+func (*Artifact) State(name string) interface{} {
+	return nil
+}
+
+func (a *Artifact) Destroy() error {
+	return os.Remove(a.Path)
+}
@@ -0,0 +1,329 @@
+package ociarchive
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+)
+
+const (
+	mediaTypeIndex      = "application/vnd.oci.image.index.v1+json"
+	mediaTypeManifest   = "application/vnd.oci.image.manifest.v1+json"
+	mediaTypeConfig     = "application/vnd.oci.image.config.v1+json"
+	mediaTypeLayer      = "application/vnd.oci.image.layer.v1.tar"
+	mediaTypeLayerGzip  = "application/vnd.oci.image.layer.v1.tar+gzip"
+	annotationRefName   = "org.opencontainers.image.ref.name"
+	imageLayoutVersion  = "1.0.0"
+	dockerManifestsPath = "manifest.json"
+)
+
+// dockerManifest describes an image of a docker save archive, as listed in
+// its manifest.json.
+type dockerManifest struct {
+	Config   string
+	RepoTags []string
+	Layers   []string
+}
+
+type descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type imageManifest struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	MediaType     string       `json:"mediaType"`
+	Config        descriptor   `json:"config"`
+	Layers        []descriptor `json:"layers"`
+}
+
+type imageIndex struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	MediaType     string       `json:"mediaType"`
+	Manifests     []descriptor `json:"manifests"`
+}
+
+// archiveFile is a file of a docker save archive.
+type archiveFile struct {
+	digest string
+	size   int64
+	gzip   bool
+}
+
+// dockerArchive is the content of a docker save archive, read without
+// keeping the layers in memory.
+type dockerArchive struct {
+	path      string
+	files     map[string]archiveFile
+	links     map[string]string
+	manifests []dockerManifest
+}
+
+func readDockerArchive(archivePath string) (*dockerArchive, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	archive := &dockerArchive{
+		path:  archivePath,
+		files: make(map[string]archiveFile),
+		links: make(map[string]string),
+	}
+	var manifests []byte
+
+	tr := tar.NewReader(f)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Unable to read %s: %s", archivePath, err)
+		}
+
+		name := path.Clean(header.Name)
+		switch header.Typeflag {
+		case tar.TypeReg, tar.TypeRegA:
+		case tar.TypeSymlink:
+			// docker save links layers shared by several images
+			archive.links[name] = path.Join(path.Dir(name), header.Linkname)
+			continue
+		case tar.TypeLink:
+			archive.links[name] = path.Clean(header.Linkname)
+			continue
+		default:
+			continue
+		}
+
+		if name == dockerManifestsPath {
+			if manifests, err = ioutil.ReadAll(tr); err != nil {
+				return nil, fmt.Errorf("Unable to read %s: %s", archivePath, err)
+			}
+			continue
+		}
+
+		h := sha256.New()
+		magic := make([]byte, 2)
+		n, err := io.ReadFull(tr, magic)
+		if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+			return nil, fmt.Errorf("Unable to read %s: %s", archivePath, err)
+		}
+		h.Write(magic[:n])
+		size, err := io.Copy(h, tr)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to read %s: %s", archivePath, err)
+		}
+		archive.files[name] = archiveFile{
+			digest: "sha256:" + hex.EncodeToString(h.Sum(nil)),
+			size:   size + int64(n),
+			gzip:   n == 2 && magic[0] == 0x1f && magic[1] == 0x8b,
+		}
+	}
+
+	if manifests == nil {
+		return nil, fmt.Errorf("%s is not a docker archive: %s not found", archivePath, dockerManifestsPath)
+	}
+	if err := json.Unmarshal(manifests, &archive.manifests); err != nil {
+		return nil, fmt.Errorf("Unable to parse %s of %s: %s", dockerManifestsPath, archivePath, err)
+	}
+	if len(archive.manifests) == 0 {
+		return nil, fmt.Errorf("%s does not contain any image", archivePath)
+	}
+	return archive, nil
+}
+
+// file returns the file of the archive at name, following links.
+func (a *dockerArchive) file(name string) (string, archiveFile, error) {
+	name = path.Clean(name)
+	for i := 0; i < 16; i++ {
+		target, ok := a.links[name]
+		if !ok {
+			break
+		}
+		name = target
+	}
+	f, ok := a.files[name]
+	if !ok {
+		return "", f, fmt.Errorf("%s not found in %s", name, a.path)
+	}
+	return name, f, nil
+}
+
+// writeOCIArchive converts the docker archive to an OCI image layout, written
+// as a tar archive to output. Every image of the docker archive is named after
+// its tags, but the first one is named refName when set. The names of the
+// images are returned.
+func (a *dockerArchive) writeOCIArchive(output io.Writer, refName string) ([]string, error) {
+	tw := tar.NewWriter(output)
+	written := make(map[string]bool)
+	// blobs are the files of the archive to copy, by digest
+	blobs := make(map[string]string)
+
+	index := imageIndex{
+		SchemaVersion: 2,
+		MediaType:     mediaTypeIndex,
+	}
+	var manifests [][]byte
+	var refNames []string
+
+	for i, image := range a.manifests {
+		configName, config, err := a.file(image.Config)
+		if err != nil {
+			return nil, err
+		}
+		blobs[config.digest] = configName
+
+		manifest := imageManifest{
+			SchemaVersion: 2,
+			MediaType:     mediaTypeManifest,
+			Config: descriptor{
+				MediaType: mediaTypeConfig,
+				Digest:    config.digest,
+				Size:      config.size,
+			},
+			Layers: []descriptor{},
+		}
+		for _, layer := range image.Layers {
+			layerName, f, err := a.file(layer)
+			if err != nil {
+				return nil, err
+			}
+			blobs[f.digest] = layerName
+
+			mediaType := mediaTypeLayer
+			if f.gzip {
+				mediaType = mediaTypeLayerGzip
+			}
+			manifest.Layers = append(manifest.Layers, descriptor{
+				MediaType: mediaType,
+				Digest:    f.digest,
+				Size:      f.size,
+			})
+		}
+
+		data, err := json.Marshal(manifest)
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, data)
+		desc := descriptor{
+			MediaType: mediaTypeManifest,
+			Digest:    digest(data),
+			Size:      int64(len(data)),
+		}
+
+		names := image.RepoTags
+		if refName != "" && i == 0 {
+			names = []string{refName}
+		}
+		if len(names) == 0 {
+			index.Manifests = append(index.Manifests, desc)
+		}
+		for _, name := range names {
+			named := desc
+			named.Annotations = map[string]string{annotationRefName: name}
+			index.Manifests = append(index.Manifests, named)
+			refNames = append(refNames, name)
+		}
+	}
+
+	layout, _ := json.Marshal(map[string]string{"imageLayoutVersion": imageLayoutVersion})
+	if err := writeTarFile(tw, "oci-layout", bytes.NewReader(layout), int64(len(layout))); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(index)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeTarFile(tw, "index.json", bytes.NewReader(data), int64(len(data))); err != nil {
+		return nil, err
+	}
+	for _, manifest := range manifests {
+		d := digest(manifest)
+		if written[d] {
+			continue
+		}
+		written[d] = true
+		if err := writeTarFile(tw, blobPath(d), bytes.NewReader(manifest), int64(len(manifest))); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := a.copyBlobs(tw, blobs, written); err != nil {
+		return nil, err
+	}
+	return refNames, tw.Close()
+}
+
+// copyBlobs copies the files of the archive named in blobs to the blobs
+// directory of the layout.
+func (a *dockerArchive) copyBlobs(tw *tar.Writer, blobs map[string]string, written map[string]bool) error {
+	names := make(map[string]string, len(blobs))
+	for d, name := range blobs {
+		names[name] = d
+	}
+
+	f, err := os.Open(a.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	tr := tar.NewReader(f)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("Unable to read %s: %s", a.path, err)
+		}
+
+		d, ok := names[path.Clean(header.Name)]
+		if !ok || written[d] {
+			continue
+		}
+		written[d] = true
+		if err := writeTarFile(tw, blobPath(d), tr, a.files[path.Clean(header.Name)].size); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeTarFile(tw *tar.Writer, name string, r io.Reader, size int64) error {
+	err := tw.WriteHeader(&tar.Header{
+		Name:     name,
+		Mode:     0644,
+		Size:     size,
+		Typeflag: tar.TypeReg,
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to write %s: %s", name, err)
+	}
+	if _, err := io.Copy(tw, r); err != nil {
+		return fmt.Errorf("Failed to write %s: %s", name, err)
+	}
+	return nil
+}
+
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func blobPath(digest string) string {
+	return path.Join("blobs", strings.Replace(digest, ":", "/", 1))
+}
@@ -0,0 +1,192 @@
+package ociarchive
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeDockerArchive writes a docker save archive of two images sharing a
+// layer, linked like docker does.
+func writeDockerArchive(t *testing.T, path string) {
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	tw := tar.NewWriter(f)
+
+	files := []struct {
+		name, content, link string
+	}{
+		{name: "aaa/layer.tar", content: "base layer"},
+		{name: "bbb/layer.tar", content: "app layer"},
+		{name: "ccc/layer.tar", link: "../aaa/layer.tar"},
+		{name: "config1.json", content: `{"architecture":"amd64"}`},
+		{name: "config2.json", content: `{"architecture":"arm64"}`},
+		{name: "manifest.json", content: `[
+			{"Config":"config1.json","RepoTags":["example/app:1.0","example/app:latest"],"Layers":["aaa/layer.tar","bbb/layer.tar"]},
+			{"Config":"config2.json","RepoTags":null,"Layers":["ccc/layer.tar"]}
+		]`},
+	}
+	for _, file := range files {
+		header := &tar.Header{Name: file.name, Mode: 0644, Size: int64(len(file.content))}
+		if file.link != "" {
+			header.Typeflag = tar.TypeSymlink
+			header.Linkname = file.link
+			header.Size = 0
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(file.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readTar(t *testing.T, r io.Reader) map[string][]byte {
+	files := make(map[string][]byte)
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := files[header.Name]; ok {
+			t.Fatalf("%s is written twice", header.Name)
+		}
+		files[header.Name] = data
+	}
+}
+
+func TestWriteOCIArchive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "packer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	source := filepath.Join(dir, "image.tar")
+	writeDockerArchive(t, source)
+
+	archive, err := readDockerArchive(source)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	var buf bytes.Buffer
+	refNames, err := archive.writeOCIArchive(&buf, "")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(refNames) != 2 || refNames[0] != "example/app:1.0" || refNames[1] != "example/app:latest" {
+		t.Fatalf("bad ref names: %v", refNames)
+	}
+
+	files := readTar(t, &buf)
+	if string(files["oci-layout"]) != `{"imageLayoutVersion":"1.0.0"}` {
+		t.Fatalf("bad oci-layout: %s", files["oci-layout"])
+	}
+
+	var index imageIndex
+	if err := json.Unmarshal(files["index.json"], &index); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(index.Manifests) != 3 {
+		t.Fatalf("expected 3 manifests, got %#v", index.Manifests)
+	}
+	if index.Manifests[0].Digest != index.Manifests[1].Digest ||
+		index.Manifests[0].Annotations[annotationRefName] != "example/app:1.0" ||
+		index.Manifests[1].Annotations[annotationRefName] != "example/app:latest" ||
+		index.Manifests[2].Annotations != nil {
+		t.Fatalf("bad index: %#v", index.Manifests)
+	}
+
+	// Every blob is named after its digest
+	blobs := 0
+	for name, data := range files {
+		if filepath.Dir(name) != "blobs/sha256" {
+			continue
+		}
+		blobs++
+		if blobPath(digest(data)) != name {
+			t.Fatalf("bad digest of %s", name)
+		}
+	}
+	// 2 manifests, 2 configs and 2 layers
+	if blobs != 6 {
+		t.Fatalf("expected 6 blobs, got %d", blobs)
+	}
+
+	var manifest imageManifest
+	if err := json.Unmarshal(files[blobPath(index.Manifests[2].Digest)], &manifest); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if string(files[blobPath(manifest.Config.Digest)]) != `{"architecture":"arm64"}` {
+		t.Fatalf("bad config: %#v", manifest.Config)
+	}
+	if len(manifest.Layers) != 1 || manifest.Layers[0].MediaType != mediaTypeLayer ||
+		string(files[blobPath(manifest.Layers[0].Digest)]) != "base layer" {
+		t.Fatalf("bad layers: %#v", manifest.Layers)
+	}
+}
+
+func TestWriteOCIArchive_refName(t *testing.T) {
+	dir, err := ioutil.TempDir("", "packer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	source := filepath.Join(dir, "image.tar")
+	writeDockerArchive(t, source)
+
+	archive, err := readDockerArchive(source)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	refNames, err := archive.writeOCIArchive(ioutil.Discard, "app:2.0")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(refNames) != 1 || refNames[0] != "app:2.0" {
+		t.Fatalf("bad ref names: %v", refNames)
+	}
+}
+
+func TestReadDockerArchive_notDocker(t *testing.T) {
+	dir, err := ioutil.TempDir("", "packer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	source := filepath.Join(dir, "files.tar")
+	f, err := os.Create(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tw := tar.NewWriter(f)
+	tw.WriteHeader(&tar.Header{Name: "file.txt", Mode: 0644})
+	tw.Close()
+	f.Close()
+
+	if _, err := readDockerArchive(source); err == nil {
+		t.Fatal("should fail without manifest.json")
+	}
+}
@@ -0,0 +1,124 @@
+//go:generate packer-sdc mapstructure-to-hcl2 -type Config
+//go:generate packer-sdc struct-markdown
+
+package ociarchive
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hcldec"
+	"github.com/hashicorp/packer-plugin-sdk/common"
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+	"github.com/hashicorp/packer-plugin-sdk/template/config"
+	"github.com/hashicorp/packer-plugin-sdk/template/interpolate"
+)
+
+type Config struct {
+	common.PackerConfig `mapstructure:",squash"`
+
+	// The path of the OCI archive to write. This is treated as a [template
+	// engine](/docs/templates/legacy_json_templates/engine), with the
+	// `BuildName` and `BuilderType` variables available. This defaults to
+	// `packer_{{.BuildName}}_{{.BuilderType}}.oci.tar`.
+	OutputPath string `mapstructure:"output"`
+	// The name of the image in the OCI layout, e.g. `example/app:1.0`. This
+	// defaults to the tags of the image in the docker archive. When the
+	// archive holds several images, only the first one is renamed. The image
+	// can be copied with `skopeo copy oci-archive:<output>:<ref_name> ...`.
+	RefName string `mapstructure:"ref_name"`
+
+	ctx interpolate.Context
+}
+
+type PostProcessor struct {
+	config Config
+}
+
+func (p *PostProcessor) ConfigSpec() hcldec.ObjectSpec { return p.config.FlatMapstructure().HCL2Spec() }
+
+func (p *PostProcessor) Configure(raws ...interface{}) error {
+	err := config.Decode(&p.config, &config.DecodeOpts{
+		PluginType:         "oci-archive",
+		Interpolate:        true,
+		InterpolateContext: &p.config.ctx,
+		InterpolateFilter: &interpolate.RenderFilter{
+			Exclude: []string{"output"},
+		},
+	}, raws...)
+	if err != nil {
+		return err
+	}
+
+	if p.config.OutputPath == "" {
+		p.config.OutputPath = "packer_{{.BuildName}}_{{.BuilderType}}.oci.tar"
+	}
+
+	if err = interpolate.Validate(p.config.OutputPath, &p.config.ctx); err != nil {
+		return fmt.Errorf("Error parsing target template: %s", err)
+	}
+
+	return nil
+}
+
+func (p *PostProcessor) PostProcess(ctx context.Context, ui packersdk.Ui, artifact packersdk.Artifact) (packersdk.Artifact, bool, bool, error) {
+	source, err := dockerArchivePath(artifact.Files())
+	if err != nil {
+		return nil, false, false, err
+	}
+
+	p.config.ctx.Data = map[string]interface{}{
+		"BuildName":   p.config.PackerBuildName,
+		"BuilderType": p.config.PackerBuilderType,
+	}
+	target, err := interpolate.Render(p.config.OutputPath, &p.config.ctx)
+	if err != nil {
+		return nil, false, false, fmt.Errorf("Error interpolating output value: %s", err)
+	}
+	if err = os.MkdirAll(filepath.Dir(target), os.FileMode(0755)); err != nil {
+		return nil, false, false, fmt.Errorf("Unable to create dir for archive %s: %s", target, err)
+	}
+
+	ui.Say(fmt.Sprintf("Converting docker archive %s to OCI archive %s", source, target))
+	archive, err := readDockerArchive(source)
+	if err != nil {
+		return nil, false, false, err
+	}
+
+	output, err := os.Create(target)
+	if err != nil {
+		return nil, false, false, fmt.Errorf("Unable to create archive %s: %s", target, err)
+	}
+	refNames, err := archive.writeOCIArchive(output, p.config.RefName)
+	if closeErr := output.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("Failed to write %s: %s", target, closeErr)
+	}
+	if err != nil {
+		os.Remove(target)
+		return nil, false, false, err
+	}
+
+	if len(refNames) > 0 {
+		ui.Message(fmt.Sprintf("Images in the archive: %s", strings.Join(refNames, ", ")))
+	}
+
+	return &Artifact{Path: target, RefNames: refNames}, false, false, nil
+}
+
+// dockerArchivePath returns the docker archive of the files of an artifact,
+// e.g. the archive written by the docker-save post-processor.
+func dockerArchivePath(files []string) (string, error) {
+	if len(files) == 1 {
+		return files[0], nil
+	}
+	for _, file := range files {
+		if strings.HasSuffix(file, ".tar") {
+			return file, nil
+		}
+	}
+	return "", fmt.Errorf("oci-archive requires an artifact with a docker archive, " +
+		"e.g. from the docker-save post-processor")
+}
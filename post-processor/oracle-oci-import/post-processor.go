@@ -0,0 +1,327 @@
+//go:generate packer-sdc mapstructure-to-hcl2 -type Config
+
+package ociimport
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/hcl/v2/hcldec"
+	"github.com/hashicorp/packer-plugin-sdk/common"
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+	"github.com/hashicorp/packer-plugin-sdk/template/config"
+	"github.com/hashicorp/packer-plugin-sdk/template/interpolate"
+	"github.com/hashicorp/packer/builder/oracle/oci"
+	"github.com/oracle/oci-go-sdk/v36/core"
+	"github.com/oracle/oci-go-sdk/v36/objectstorage"
+	"github.com/oracle/oci-go-sdk/v36/objectstorage/transfer"
+)
+
+const (
+	imageTypeQCOW2 = "QCOW2"
+	imageTypeVMDK  = "VMDK"
+)
+
+type Config struct {
+	common.PackerConfig `mapstructure:",squash"`
+	oci.AccessConfig    `mapstructure:",squash"`
+
+	CompartmentID string `mapstructure:"compartment_ocid"`
+
+	Namespace  string `mapstructure:"namespace"`
+	BucketName string `mapstructure:"bucket_name"`
+	ObjectName string `mapstructure:"object_name"`
+	SkipClean  bool   `mapstructure:"skip_clean"`
+
+	ImageName              string                            `mapstructure:"image_name"`
+	ImageType              string                            `mapstructure:"image_type"`
+	LaunchMode             string                            `mapstructure:"image_launch_mode"`
+	OperatingSystem        string                            `mapstructure:"operating_system"`
+	OperatingSystemVersion string                            `mapstructure:"operating_system_version"`
+	Tags                   map[string]string                 `mapstructure:"tags"`
+	DefinedTags            map[string]map[string]interface{} `mapstructure:"defined_tags"`
+
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	ctx interpolate.Context
+}
+
+type PostProcessor struct {
+	config Config
+}
+
+func (p *PostProcessor) ConfigSpec() hcldec.ObjectSpec { return p.config.FlatMapstructure().HCL2Spec() }
+
+func (p *PostProcessor) Configure(raws ...interface{}) error {
+	err := config.Decode(&p.config, &config.DecodeOpts{
+		PluginType:         BuilderId,
+		Interpolate:        true,
+		InterpolateContext: &p.config.ctx,
+		InterpolateFilter: &interpolate.RenderFilter{
+			Exclude: []string{"object_name", "image_name"},
+		},
+	}, raws...)
+	if err != nil {
+		return err
+	}
+
+	var errs *packersdk.MultiError
+	errs, err = p.config.AccessConfig.Prepare(errs)
+	if err != nil {
+		return err
+	}
+
+	if p.config.CompartmentID == "" && p.config.ConfigProvider() != nil {
+		p.config.CompartmentID, _ = p.config.ConfigProvider().TenancyOCID()
+	}
+
+	if p.config.ObjectName == "" {
+		p.config.ObjectName = "packer-import-{{timestamp}}"
+	}
+
+	if p.config.ImageName == "" {
+		p.config.ImageName = "packer-import-{{timestamp}}"
+	}
+
+	if p.config.LaunchMode == "" {
+		p.config.LaunchMode = string(core.CreateImageDetailsLaunchModeParavirtualized)
+	}
+
+	if p.config.Timeout == 0 {
+		p.config.Timeout = 60 * time.Minute
+	}
+
+	for key, tmpl := range map[string]string{
+		"object_name": p.config.ObjectName,
+		"image_name":  p.config.ImageName,
+	} {
+		if err = interpolate.Validate(tmpl, &p.config.ctx); err != nil {
+			errs = packersdk.MultiErrorAppend(
+				errs, fmt.Errorf("Error parsing %s template: %s", key, err))
+		}
+	}
+
+	if p.config.BucketName == "" {
+		errs = packersdk.MultiErrorAppend(
+			errs, fmt.Errorf("bucket_name must be set"))
+	}
+
+	if p.config.CompartmentID == "" {
+		errs = packersdk.MultiErrorAppend(
+			errs, fmt.Errorf("compartment_ocid must be set"))
+	}
+
+	switch p.config.ImageType {
+	case "", imageTypeQCOW2, imageTypeVMDK:
+	default:
+		errs = packersdk.MultiErrorAppend(
+			errs, fmt.Errorf("image_type must be one of %q or %q, got %q", imageTypeQCOW2, imageTypeVMDK, p.config.ImageType))
+	}
+
+	switch core.CreateImageDetailsLaunchModeEnum(p.config.LaunchMode) {
+	case core.CreateImageDetailsLaunchModeNative,
+		core.CreateImageDetailsLaunchModeEmulated,
+		core.CreateImageDetailsLaunchModeParavirtualized,
+		core.CreateImageDetailsLaunchModeCustom:
+	default:
+		errs = packersdk.MultiErrorAppend(
+			errs, fmt.Errorf("image_launch_mode must be one of NATIVE, EMULATED, PARAVIRTUALIZED or CUSTOM, got %q", p.config.LaunchMode))
+	}
+
+	if errs != nil && len(errs.Errors) > 0 {
+		return errs
+	}
+
+	log.Println(p.config)
+	return nil
+}
+
+func (p *PostProcessor) PostProcess(ctx context.Context, ui packersdk.Ui, artifact packersdk.Artifact) (packersdk.Artifact, bool, bool, error) {
+	generatedData := artifact.State("generated_data")
+	if generatedData == nil {
+		// Make sure it's not a nil map so we can assign to it later.
+		generatedData = make(map[string]interface{})
+	}
+	p.config.ctx.Data = generatedData
+
+	objectName, err := interpolate.Render(p.config.ObjectName, &p.config.ctx)
+	if err != nil {
+		return nil, false, false, fmt.Errorf("Error rendering object_name template: %s", err)
+	}
+	imageName, err := interpolate.Render(p.config.ImageName, &p.config.ctx)
+	if err != nil {
+		return nil, false, false, fmt.Errorf("Error rendering image_name template: %s", err)
+	}
+
+	log.Println("Looking for image in artifact")
+	source, err := extractImageArtifact(artifact.Files())
+	if err != nil {
+		return nil, false, false, err
+	}
+
+	imageType := p.config.ImageType
+	if imageType == "" {
+		imageType = detectImageType(source)
+	}
+
+	provider := p.config.ConfigProvider()
+	region, err := provider.Region()
+	if err != nil {
+		return nil, false, false, err
+	}
+
+	storageClient, err := objectstorage.NewObjectStorageClientWithConfigurationProvider(provider)
+	if err != nil {
+		return nil, false, false, fmt.Errorf("Error creating object storage client: %s", err)
+	}
+	computeClient, err := core.NewComputeClientWithConfigurationProvider(provider)
+	if err != nil {
+		return nil, false, false, fmt.Errorf("Error creating compute client: %s", err)
+	}
+
+	namespace := p.config.Namespace
+	if namespace == "" {
+		resp, err := storageClient.GetNamespace(ctx, objectstorage.GetNamespaceRequest{})
+		if err != nil {
+			return nil, false, false, fmt.Errorf("Error looking up object storage namespace: %s", err)
+		}
+		namespace = *resp.Value
+	}
+
+	ui.Say(fmt.Sprintf("Uploading %s to oci://%s/%s/%s", source, namespace, p.config.BucketName, objectName))
+	_, err = transfer.NewUploadManager().UploadFile(ctx, transfer.UploadFileRequest{
+		UploadRequest: transfer.UploadRequest{
+			NamespaceName:       &namespace,
+			BucketName:          &p.config.BucketName,
+			ObjectName:          &objectName,
+			ObjectStorageClient: &storageClient,
+		},
+		FilePath: source,
+	})
+	if err != nil {
+		return nil, false, false, fmt.Errorf("Failed to upload %s: %s", source, err)
+	}
+
+	if !p.config.SkipClean {
+		defer func() {
+			ui.Say(fmt.Sprintf("Deleting import source oci://%s/%s/%s", namespace, p.config.BucketName, objectName))
+			_, err := storageClient.DeleteObject(context.TODO(), objectstorage.DeleteObjectRequest{
+				NamespaceName: &namespace,
+				BucketName:    &p.config.BucketName,
+				ObjectName:    &objectName,
+			})
+			if err != nil {
+				ui.Error(fmt.Sprintf("Failed to delete oci://%s/%s/%s: %s", namespace, p.config.BucketName, objectName, err))
+			}
+		}()
+	}
+
+	ui.Say(fmt.Sprintf("Importing image %s as %s in %s mode", imageName, imageType, p.config.LaunchMode))
+	resp, err := computeClient.CreateImage(ctx, buildCreateImageRequest(p.config, namespace, objectName, imageName, imageType))
+	if err != nil {
+		return nil, false, false, fmt.Errorf("Failed to import image %s: %s", imageName, err)
+	}
+
+	ui.Message(fmt.Sprintf("Waiting for image %s to become available (may take a while)", *resp.Image.Id))
+	image, err := waitImageAvailable(ctx, computeClient, *resp.Image.Id, p.config.Timeout)
+	if err != nil {
+		return nil, false, false, fmt.Errorf("Import of image %s failed with error: %s", imageName, err)
+	}
+	ui.Message(fmt.Sprintf("Import of image %s complete", imageName))
+
+	return &Artifact{
+		Image:     image,
+		Region:    region,
+		client:    computeClient,
+		StateData: map[string]interface{}{"generated_data": generatedData},
+	}, false, false, nil
+}
+
+func buildCreateImageRequest(c Config, namespace, objectName, imageName, imageType string) core.CreateImageRequest {
+	source := core.ImageSourceViaObjectStorageTupleDetails{
+		NamespaceName:   &namespace,
+		BucketName:      &c.BucketName,
+		ObjectName:      &objectName,
+		SourceImageType: core.ImageSourceDetailsSourceImageTypeEnum(imageType),
+	}
+	if c.OperatingSystem != "" {
+		source.OperatingSystem = &c.OperatingSystem
+	}
+	if c.OperatingSystemVersion != "" {
+		source.OperatingSystemVersion = &c.OperatingSystemVersion
+	}
+
+	return core.CreateImageRequest{
+		CreateImageDetails: core.CreateImageDetails{
+			CompartmentId:      &c.CompartmentID,
+			DisplayName:        &imageName,
+			FreeformTags:       c.Tags,
+			DefinedTags:        c.DefinedTags,
+			ImageSourceDetails: source,
+			LaunchMode:         core.CreateImageDetailsLaunchModeEnum(c.LaunchMode),
+		},
+	}
+}
+
+func waitImageAvailable(ctx context.Context, client core.ComputeClient, id string, timeout time.Duration) (core.Image, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		resp, err := client.GetImage(ctx, core.GetImageRequest{ImageId: &id})
+		if err != nil {
+			return core.Image{}, err
+		}
+
+		switch resp.Image.LifecycleState {
+		case core.ImageLifecycleStateAvailable:
+			return resp.Image, nil
+		case core.ImageLifecycleStateImporting, core.ImageLifecycleStateProvisioning:
+			log.Printf("Image %s is %s", id, resp.Image.LifecycleState)
+		default:
+			return core.Image{}, fmt.Errorf("unexpected image state %q", resp.Image.LifecycleState)
+		}
+
+		select {
+		case <-ctx.Done():
+			return core.Image{}, fmt.Errorf("timeout while waiting for image %s to become available", id)
+		case <-time.After(10 * time.Second):
+		}
+	}
+}
+
+func extractImageArtifact(artifacts []string) (string, error) {
+	artifactCount := len(artifacts)
+
+	if artifactCount == 0 {
+		return "", fmt.Errorf("no artifacts were provided")
+	}
+
+	if artifactCount == 1 {
+		return artifacts[0], nil
+	}
+
+	validSuffix := []string{"qcow2", "vmdk"}
+	for _, path := range artifacts {
+		for _, suffix := range validSuffix {
+			if strings.HasSuffix(path, suffix) {
+				return path, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("no valid image file found")
+}
+
+// detectImageType guesses the type of an image from its file extension,
+// falling back to QCOW2.
+func detectImageType(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".vmdk") {
+		return imageTypeVMDK
+	}
+	return imageTypeQCOW2
+}
@@ -0,0 +1,89 @@
+package ociimport
+
+import (
+	"testing"
+
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+	"github.com/oracle/oci-go-sdk/v36/core"
+)
+
+func TestPostProcessor_ImplementsPostProcessor(t *testing.T) {
+	var _ packersdk.PostProcessor = new(PostProcessor)
+}
+
+func TestPostProcessor_ImageArtifactExtraction(t *testing.T) {
+	tt := []struct {
+		Name          string
+		Source        string
+		Artifacts     []string
+		ExpectedError string
+	}{
+		{Name: "EmptyArtifacts", ExpectedError: "no artifacts were provided"},
+		{Name: "SingleArtifact", Source: "disk.img", Artifacts: []string{"disk.img"}},
+		{Name: "SupportedArtifact", Source: "disk.vmdk", Artifacts: []string{"disk.vmx", "disk.vmdk"}},
+		{Name: "NonSupportedArtifact", Artifacts: []string{"disk.vmx", "disk.raw"}, ExpectedError: "no valid image file found"},
+	}
+
+	for _, tc := range tt {
+		source, err := extractImageArtifact(tc.Artifacts)
+
+		if tc.Source != source {
+			t.Errorf("%s: expected the source to be %q, but got %q", tc.Name, tc.Source, source)
+		}
+
+		if err != nil && (tc.ExpectedError != err.Error()) {
+			t.Errorf("%s: unexpected error received; expected %q, but got %q", tc.Name, tc.ExpectedError, err.Error())
+		}
+	}
+}
+
+func TestPostProcessor_detectImageType(t *testing.T) {
+	tt := map[string]string{
+		"disk.qcow2": imageTypeQCOW2,
+		"disk.img":   imageTypeQCOW2,
+		"disk.VMDK":  imageTypeVMDK,
+	}
+
+	for source, expected := range tt {
+		if imageType := detectImageType(source); imageType != expected {
+			t.Errorf("%s: expected %q, got %q", source, expected, imageType)
+		}
+	}
+}
+
+func TestPostProcessor_buildCreateImageRequest(t *testing.T) {
+	c := Config{
+		CompartmentID:   "ocid1.compartment.oc1..test",
+		BucketName:      "images",
+		LaunchMode:      "EMULATED",
+		OperatingSystem: "Linux",
+		Tags:            map[string]string{"team": "packer"},
+	}
+
+	req := buildCreateImageRequest(c, "ns", "disk.vmdk", "my-image", imageTypeVMDK)
+	details := req.CreateImageDetails
+
+	if *details.CompartmentId != c.CompartmentID || *details.DisplayName != "my-image" {
+		t.Fatalf("unexpected image details: %#v", details)
+	}
+	if details.LaunchMode != core.CreateImageDetailsLaunchModeEmulated {
+		t.Errorf("expected launch mode EMULATED, got %q", details.LaunchMode)
+	}
+	if details.FreeformTags["team"] != "packer" {
+		t.Errorf("expected tags to be set, got %v", details.FreeformTags)
+	}
+
+	source, ok := details.ImageSourceDetails.(core.ImageSourceViaObjectStorageTupleDetails)
+	if !ok {
+		t.Fatalf("unexpected image source details: %#v", details.ImageSourceDetails)
+	}
+	if *source.NamespaceName != "ns" || *source.BucketName != "images" || *source.ObjectName != "disk.vmdk" {
+		t.Errorf("unexpected object: %#v", source)
+	}
+	if source.SourceImageType != core.ImageSourceDetailsSourceImageTypeEnum(imageTypeVMDK) {
+		t.Errorf("expected source image type VMDK, got %q", source.SourceImageType)
+	}
+	if *source.OperatingSystem != "Linux" || source.OperatingSystemVersion != nil {
+		t.Errorf("unexpected operating system: %#v", source)
+	}
+}
@@ -0,0 +1,20 @@
+package shell_local
+
+import (
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+)
+
+// Artifact is the input artifact of the post-processor, with the data written
+// by the scripts added to its generated data.
+type Artifact struct {
+	packersdk.Artifact
+
+	generatedData interface{}
+}
+
+func (a *Artifact) State(name string) interface{} {
+	if name == "generated_data" {
+		return a.generatedData
+	}
+	return a.Artifact.State(name)
+}
@@ -2,12 +2,21 @@ package shell_local
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
 
 	"github.com/hashicorp/hcl/v2/hcldec"
 	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
 	sl "github.com/hashicorp/packer-plugin-sdk/shell-local"
 )
 
+// GeneratedDataFileVar is the environment variable set to the path of a file
+// the scripts can write a JSON object to. Its keys and values are added to the
+// generated data of the artifact, for the post-processors that follow.
+const GeneratedDataFileVar = "PACKER_GENERATED_DATA_FILE"
+
 type PostProcessor struct {
 	config sl.Config
 }
@@ -49,14 +58,91 @@ func (p *PostProcessor) PostProcess(ctx context.Context, ui packersdk.Ui, artifa
 		}
 	}
 
-	success, retErr := sl.Run(ctx, ui, &p.config, generatedData)
+	dataFile, err := ioutil.TempFile("", "packer-generated-data-*.json")
+	if err != nil {
+		return nil, false, false, fmt.Errorf("Error creating generated data file: %s", err)
+	}
+	dataFile.Close()
+	defer os.Remove(dataFile.Name())
+
+	config := p.config
+	config.Vars = append(append([]string{}, p.config.Vars...),
+		fmt.Sprintf("%s=%s", GeneratedDataFileVar, dataFile.Name()))
+
+	success, retErr := sl.Run(ctx, ui, &config, generatedData)
 	if !success {
 		return nil, false, false, retErr
 	}
 
+	scriptData, err := readGeneratedData(dataFile.Name())
+	if err != nil {
+		return nil, false, false, err
+	}
+	if len(scriptData) > 0 {
+		artifact = &Artifact{
+			Artifact:      artifact,
+			generatedData: mergeGeneratedData(artifactStateData, scriptData),
+		}
+	}
+
 	// Force shell-local pp to keep the input artifact, because otherwise we'll
 	// lose it instead of being able to pass it through. If you want to delete
 	// the input artifact for a shell local pp, use the artifice pp to create a
 	// new artifact
 	return artifact, true, true, retErr
 }
+
+// readGeneratedData reads the JSON object written by the scripts to path.
+// Values that are not strings are kept as JSON.
+func readGeneratedData(path string) (map[string]string, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading generated data file: %s", err)
+	}
+	if len(contents) == 0 {
+		return nil, nil
+	}
+
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(contents, &values); err != nil {
+		return nil, fmt.Errorf("Error parsing generated data written to %s: %s",
+			GeneratedDataFileVar, err)
+	}
+
+	data := make(map[string]string, len(values))
+	for key, value := range values {
+		var s string
+		if err := json.Unmarshal(value, &s); err == nil {
+			data[key] = s
+		} else {
+			data[key] = string(value)
+		}
+	}
+	return data, nil
+}
+
+// mergeGeneratedData adds data to the generated data of an artifact, keeping
+// the type of map the artifact uses.
+func mergeGeneratedData(artifactData interface{}, data map[string]string) interface{} {
+	if stringMap, ok := artifactData.(map[string]interface{}); ok {
+		merged := make(map[string]interface{}, len(stringMap)+len(data))
+		for k, v := range stringMap {
+			merged[k] = v
+		}
+		for k, v := range data {
+			merged[k] = v
+		}
+		return merged
+	}
+
+	merged := make(map[interface{}]interface{}, len(data))
+	if interfaceMap, ok := artifactData.(map[interface{}]interface{}); ok {
+		for k, v := range interfaceMap {
+			merged[k] = v
+		}
+	}
+	for k, v := range data {
+		merged[k] = v
+	}
+	return merged
+}
@@ -1,6 +1,7 @@
 package shell_local
 
 import (
+	"context"
 	"io/ioutil"
 	"os"
 	"runtime"
@@ -263,3 +264,61 @@ func TestPostProcessorPrepare_EnvironmentVars(t *testing.T) {
 		t.Fatalf("should not have error: %s", err)
 	}
 }
+
+func TestPostProcessor_PostProcess_generatedData(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the inline script requires a POSIX shell")
+	}
+
+	p := new(PostProcessor)
+	err := p.Configure(map[string]interface{}{
+		"inline": []interface{}{
+			`echo '{"Version": "1.2.3", "Layers": 4}' > "$PACKER_GENERATED_DATA_FILE"`,
+		},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	artifact := &packersdk.MockArtifact{
+		StateValues: map[string]interface{}{
+			"generated_data": map[interface{}]interface{}{"ID": "abc"},
+		},
+	}
+	result, keep, _, err := p.PostProcess(context.Background(), packersdk.TestUi(t), artifact)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !keep {
+		t.Fatal("should keep the input artifact")
+	}
+
+	expected := map[interface{}]interface{}{
+		"ID":      "abc",
+		"Version": "1.2.3",
+		"Layers":  "4",
+	}
+	assert.Equal(t, expected, result.State("generated_data"))
+	assert.Equal(t, artifact.Id(), result.Id())
+}
+
+func TestReadGeneratedData(t *testing.T) {
+	f, err := ioutil.TempFile("", "packer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+
+	data, err := readGeneratedData(f.Name())
+	if err != nil || data != nil {
+		t.Fatalf("an empty file should have no data, got %v, %v", data, err)
+	}
+
+	if err := ioutil.WriteFile(f.Name(), []byte("Version=1.2.3"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readGeneratedData(f.Name()); err == nil {
+		t.Fatal("should fail when the file isn't a JSON object")
+	}
+}
@@ -0,0 +1,51 @@
+package ucloudimport
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"os"
+)
+
+// etagBlockSize is the size of the blocks the UFile ETag of a file is
+// computed from.
+const etagBlockSize = 4 * 1024 * 1024
+
+// fileEtag computes the UFile ETag of a local file, which is the URL safe
+// base64 encoding of the number of 4MB blocks of the file followed by the
+// SHA1 of the file when it fits in one block, or the SHA1 of the
+// concatenated SHA1 of every block otherwise.
+func fileEtag(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	blkcnt := uint32((fi.Size() + etagBlockSize - 1) / etagBlockSize)
+	buf := make([]byte, 4, 4+sha1.Size)
+	binary.LittleEndian.PutUint32(buf, blkcnt)
+
+	h := sha1.New()
+	if fi.Size() <= etagBlockSize {
+		if _, err := io.Copy(h, f); err != nil {
+			return "", err
+		}
+	} else {
+		for i := uint32(0); i < blkcnt; i++ {
+			blk := sha1.New()
+			if _, err := io.Copy(blk, io.LimitReader(f, etagBlockSize)); err != nil {
+				return "", err
+			}
+			h.Write(blk.Sum(nil))
+		}
+	}
+
+	return base64.URLEncoding.EncodeToString(h.Sum(buf)), nil
+}
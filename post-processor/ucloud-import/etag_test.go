@@ -0,0 +1,40 @@
+package ucloudimport
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileEtag(t *testing.T) {
+	tc := []struct {
+		Name    string
+		Content []byte
+		Etag    string
+	}{
+		{Name: "SingleBlock", Content: []byte("0123456789"), Etag: "AQAAAIes7BfNnc0gpxbMLPZ0F7ccinAW"},
+		{Name: "MultipleBlocks", Content: bytes.Repeat([]byte("a"), etagBlockSize+3), Etag: "AgAAACUkqYKhEDT1cK4qW1PvsMCBcdUL"},
+	}
+
+	for _, c := range tc {
+		t.Run(c.Name, func(t *testing.T) {
+			f, err := ioutil.TempFile("", "packer-ucloud-import")
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			defer os.Remove(f.Name())
+
+			if _, err := f.Write(c.Content); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			f.Close()
+
+			etag, err := fileEtag(f.Name())
+			assert.NoError(t, err)
+			assert.Equal(t, c.Etag, etag)
+		})
+	}
+}
@@ -8,7 +8,11 @@ import (
 	"fmt"
 	"log"
 	"net/url"
+	"path"
+	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/hcl/v2/hcldec"
@@ -21,6 +25,8 @@ import (
 	"github.com/ucloud/ucloud-sdk-go/services/ufile"
 	"github.com/ucloud/ucloud-sdk-go/services/uhost"
 	"github.com/ucloud/ucloud-sdk-go/ucloud"
+	"github.com/ucloud/ucloud-sdk-go/ucloud/request"
+	"github.com/ucloud/ucloud-sdk-go/ucloud/response"
 	ufsdk "github.com/ufilesdk-dev/ufile-gosdk"
 )
 
@@ -31,12 +37,22 @@ const (
 	ImageFileFormatVHD   = "vhd"
 	ImageFileFormatVMDK  = "vmdk"
 	ImageFileFormatQCOW2 = "qcow2"
+
+	BucketTypePrivate = "private"
+	BucketTypePublic  = "public"
+
+	defaultUploadConcurrency      = 10
+	defaultUploadProgressInterval = 30 * time.Second
 )
 
+// imageFormatMap maps the format of the image file to the format expected by
+// the ImportCustomImage API. qcow2 is supported natively by UCloud and is
+// passed through as is, so there is no need to convert it to RAW first.
 var imageFormatMap = ucloudcommon.NewStringConverter(map[string]string{
-	"raw":  "RAW",
-	"vhd":  "VHD",
-	"vmdk": "VMDK",
+	"raw":   "RAW",
+	"vhd":   "VHD",
+	"vmdk":  "VMDK",
+	"qcow2": "qcow2",
 })
 
 // Configuration of this post processor
@@ -45,13 +61,19 @@ type Config struct {
 	ucloudcommon.AccessConfig `mapstructure:",squash"`
 
 	//  The name of the UFile bucket where the RAW, VHD, VMDK, or qcow2 file will be copied to for import.
-	//  This bucket must exist when the post-processor is run.
+	//  This bucket must exist when the post-processor is run, unless `create_bucket` is set.
 	UFileBucket string `mapstructure:"ufile_bucket_name" required:"true"`
 	// The name of the object key in
 	//  `ufile_bucket_name` where the RAW, VHD, VMDK, or qcow2 file will be copied
 	//  to import. This is a [template engine](/docs/templates/legacy_json_templates/engine).
 	//  Therefore, you may use user variables and template functions in this field.
 	UFileKey string `mapstructure:"ufile_key_name" required:"false"`
+	// Whether to create the `ufile_bucket_name` bucket when it does not exist.
+	// (Default: `false`).
+	CreateBucket bool `mapstructure:"create_bucket" required:"false"`
+	// The type of the bucket created when `create_bucket` is set. Possible values
+	// are: `private` and `public`. (Default: `private`).
+	BucketType string `mapstructure:"bucket_type" required:"false"`
 	// Whether we should skip removing the RAW, VHD, VMDK, or qcow2 file uploaded to
 	// UFile after the import process has completed. Possible values are: `true` to
 	// leave it in the UFile bucket, `false` to remove it. (Default: `false`).
@@ -71,6 +93,51 @@ type Config struct {
 	Format string `mapstructure:"format" required:"true"`
 	// Timeout of importing image. The default timeout is 3600 seconds if this option is not set or is set.
 	WaitImageReadyTimeout int `mapstructure:"wait_image_ready_timeout" required:"false"`
+	// Whether to skip uploading the image file when an object already exists in
+	// `ufile_bucket_name` under `ufile_key_name`, and import the existing object
+	// instead. This avoids uploading the image file again when retrying a build.
+	// (Default: `false`).
+	SkipUploadIfExists bool `mapstructure:"skip_upload_if_exists" required:"false"`
+	// Only skip the upload if the ETag of the existing object matches the ETag
+	// computed from the local image file, so that an outdated or partial object
+	// is never imported. This requires `skip_upload_if_exists` to be set.
+	// (Default: `false`).
+	SkipUploadVerifyEtag bool `mapstructure:"skip_upload_verify_etag" required:"false"`
+	// Whether to compare the ETag of the object uploaded to UFile with the ETag
+	// computed from the local image file before importing it, and abort the
+	// import on mismatch so that a corrupted upload is never imported. UFile
+	// ETags are computed from the SHA1 of every 4MB block of the file. With
+	// `skip_upload_if_exists`, an existing object is only reused if its ETag
+	// matches, and is uploaded again otherwise. (Default: `false`).
+	ValidateChecksum bool `mapstructure:"validate_checksum" required:"false"`
+	// The number of parts uploaded to UFile in parallel. Raise it to saturate
+	// fast links, or lower it to reduce the number of concurrent connections,
+	// e.g. behind a proxy. The limit is shared by all the files imported with
+	// `disk_file_globs`. The size of the parts can not be configured, as UFile
+	// imposes it when the upload is initiated. (Default: `10`).
+	UploadConcurrency int `mapstructure:"upload_concurrency" required:"false"`
+	// The interval at which the progress of the upload of the image file to UFile
+	// is reported, such as `30s` or `5m`. (Default: `30s`).
+	UploadProgressInterval time.Duration `mapstructure:"upload_progress_interval" required:"false"`
+	// The list of regions the imported image will be copied to once the import
+	// is complete. The copied images keep the `image_name` and `image_description`
+	// of the imported image and are created in the same `project_id`.
+	CopyToRegions []string `mapstructure:"copy_to_regions" required:"false"`
+	// The id of another project the imported image will be copied to once it is
+	// available, in the region of the import as well as in every region of
+	// `copy_to_regions`. The images copied to this project are added to the
+	// artifact along with the imported image.
+	TargetProjectId string `mapstructure:"target_project_id" required:"false"`
+	// Key/value pairs of labels bound to the imported image, as well as to every
+	// image copied to `copy_to_regions` or `target_project_id`, once the image is
+	// available.
+	ImageTag map[string]string `mapstructure:"image_tag" required:"false"`
+	// A list of glob patterns, such as `*.vmdk` or `disk-*.raw`, matched against the
+	// files of the artifact to select the image files to import. Every matching
+	// file is imported in parallel as a separate image, in which case a `-<n>`
+	// suffix is added to `ufile_key_name` and `image_name` for the n-th file.
+	// When not set, only the first file with the extension of `format` is imported.
+	DiskFileGlobs []string `mapstructure:"disk_file_globs" required:"false"`
 
 	ctx interpolate.Context
 }
@@ -105,6 +172,18 @@ func (p *PostProcessor) Configure(raws ...interface{}) error {
 		p.config.WaitImageReadyTimeout = ucloudcommon.DefaultCreateImageTimeout
 	}
 
+	if p.config.BucketType == "" {
+		p.config.BucketType = BucketTypePrivate
+	}
+
+	if p.config.UploadConcurrency <= 0 {
+		p.config.UploadConcurrency = defaultUploadConcurrency
+	}
+
+	if p.config.UploadProgressInterval <= 0 {
+		p.config.UploadProgressInterval = defaultUploadProgressInterval
+	}
+
 	errs := new(packersdk.MultiError)
 
 	// Check and render ufile_key_name
@@ -137,6 +216,39 @@ func (p *PostProcessor) Configure(raws ...interface{}) error {
 		errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("expected %q to be 1-63 characters and only support chinese, english, numbers, '-_,.:[]', got %q", "image_name", imageName))
 	}
 
+	for _, region := range p.config.CopyToRegions {
+		if region == "" {
+			errs = packersdk.MultiErrorAppend(
+				errs, fmt.Errorf("%q must not contain empty region", "copy_to_regions"))
+		}
+	}
+
+	for key := range p.config.ImageTag {
+		if key == "" {
+			errs = packersdk.MultiErrorAppend(
+				errs, fmt.Errorf("%q must not contain empty key", "image_tag"))
+		}
+	}
+
+	for _, glob := range p.config.DiskFileGlobs {
+		if _, err := filepath.Match(glob, ""); err != nil {
+			errs = packersdk.MultiErrorAppend(
+				errs, fmt.Errorf("%q contains an invalid pattern %q, %s", "disk_file_globs", glob, err))
+		}
+	}
+
+	if p.config.SkipUploadVerifyEtag && !p.config.SkipUploadIfExists {
+		errs = packersdk.MultiErrorAppend(
+			errs, fmt.Errorf("%q requires %q to be set", "skip_upload_verify_etag", "skip_upload_if_exists"))
+	}
+
+	switch p.config.BucketType {
+	case BucketTypePrivate, BucketTypePublic:
+	default:
+		errs = packersdk.MultiErrorAppend(
+			errs, fmt.Errorf("expected %q only be one of 'private' or 'public', got %q", "bucket_type", p.config.BucketType))
+	}
+
 	switch p.config.Format {
 	case ImageFileFormatVHD, ImageFileFormatRAW, ImageFileFormatVMDK, ImageFileFormatQCOW2:
 	default:
@@ -168,7 +280,6 @@ func (p *PostProcessor) PostProcess(ctx context.Context, ui packersdk.Ui, artifa
 	if err != nil {
 		return nil, false, false, fmt.Errorf("Failed to connect ucloud client %s", err)
 	}
-	uhostconn := client.UHostConn
 	ufileconn := client.UFileConn
 
 	// Render this key since we didn't in the configure phase
@@ -181,24 +292,24 @@ func (p *PostProcessor) PostProcess(ctx context.Context, ui packersdk.Ui, artifa
 
 	ui.Message("Looking for image in artifact")
 	// Locate the files output from the builder
-	var source string
-	for _, path := range artifact.Files() {
-		if strings.HasSuffix(path, "."+p.config.Format) {
-			source = path
-			break
-		}
-	}
+	sources := p.imageFiles(artifact.Files())
 
 	// Hope we found something useful
-	if source == "" {
+	if len(sources) == 0 {
 		return nil, false, false, fmt.Errorf("No %s image file found in artifact from builder", p.config.Format)
 	}
 
-	keyName := p.config.UFileKey
 	bucketName := p.config.UFileBucket
 
 	// query bucket
 	domain, err := queryBucket(ufileconn, bucketName)
+	if ucloudcommon.IsNotFoundError(err) && p.config.CreateBucket {
+		ui.Say(fmt.Sprintf("Creating %s bucket %s...", p.config.BucketType, bucketName))
+		if err = createBucket(ufileconn, bucketName, p.config.BucketType); err != nil {
+			return nil, false, false, fmt.Errorf("Failed to create bucket, %s", err)
+		}
+		domain, err = queryBucket(ufileconn, bucketName)
+	}
 	if err != nil {
 		return nil, false, false, fmt.Errorf("Failed to query bucket, %s", err)
 	}
@@ -222,39 +333,303 @@ func (p *PostProcessor) PostProcess(ctx context.Context, ui packersdk.Ui, artifa
 		BucketHost: bucketHost,
 	}
 
-	ui.Say(fmt.Sprintf("Waiting for uploading image file %s to UFile: %s/%s...", source, bucketName, keyName))
+	// import every image file in parallel, each one as a separate image
+	disks := make([]*importedDisk, len(sources))
+	for i, source := range sources {
+		disks[i] = &importedDisk{
+			source:    source,
+			keyName:   diskKeyName(p.config.UFileKey, i, len(sources)),
+			imageName: diskImageName(p.config.ImageName, i, len(sources)),
+		}
+	}
+
+	// the disks share the limit of parts uploaded in parallel
+	limiter := newUploadLimiter(p.config.UploadConcurrency)
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(disks))
+	for i, disk := range disks {
+		wg.Add(1)
+		go func(i int, disk *importedDisk) {
+			defer wg.Done()
+			errs[i] = p.importDisk(ctx, ui, client, config, limiter, disk)
+		}(i, disk)
+	}
+	wg.Wait()
 
-	// upload file to bucket
-	ufileUrl, err := uploadFile(ufileconn, config, keyName, source)
-	if err != nil {
-		return nil, false, false, fmt.Errorf("Failed to Upload image file, %s", err)
+	multiErr := new(packersdk.MultiError)
+	for _, err := range errs {
+		if err != nil {
+			multiErr = packersdk.MultiErrorAppend(multiErr, err)
+		}
+	}
+	if len(multiErr.Errors) > 0 {
+		return nil, false, false, multiErr
+	}
+
+	// Add the reported UCloud image IDs to the artifact list
+	var images []ucloudcommon.ImageInfo
+	for _, disk := range disks {
+		images = append(images, ucloudcommon.ImageInfo{
+			ImageId:   disk.imageId,
+			ProjectId: p.config.ProjectId,
+			Region:    p.config.Region,
+		})
+	}
+
+	if destinations := p.copyDestinations(); len(destinations) > 0 {
+		for _, disk := range disks {
+			copiedImages, err := p.copyImage(ctx, ui, client, disk.imageId, disk.imageName, destinations)
+			if err != nil {
+				return nil, false, false, err
+			}
+			images = append(images, copiedImages...)
+		}
+	}
+
+	artifact = &ucloudcommon.Artifact{
+		UCloudImages:   ucloudcommon.NewImageInfoSet(images),
+		BuilderIdValue: BuilderId,
+		Client:         client,
 	}
 
-	ui.Say(fmt.Sprintf("Image file %s has been uploaded to UFile: %s/%s", source, bucketName, keyName))
+	if !p.config.SkipClean {
+		for _, disk := range disks {
+			ui.Message(fmt.Sprintf("Deleting import source UFile: %s/%s", bucketName, disk.keyName))
+			if err = deleteFile(config, disk.keyName); err != nil {
+				return nil, false, false, fmt.Errorf("Failed to delete UFile: %s/%s, %s", bucketName, disk.keyName, err)
+			}
+		}
+	}
+
+	return artifact, false, false, nil
+}
+
+// importedDisk is an image file of the artifact, imported as its own image.
+type importedDisk struct {
+	source    string
+	keyName   string
+	imageName string
+	imageId   string
+}
+
+// imageFiles returns the image files of the artifact to import. Without
+// disk_file_globs, this is the first file with the extension of format,
+// otherwise this is every file matching one of the globs.
+func (p *PostProcessor) imageFiles(files []string) []string {
+	var sources []string
+	if len(p.config.DiskFileGlobs) == 0 {
+		for _, file := range files {
+			if strings.HasSuffix(file, "."+p.config.Format) {
+				sources = append(sources, file)
+				break
+			}
+		}
+		return sources
+	}
+
+	for _, file := range files {
+		for _, glob := range p.config.DiskFileGlobs {
+			// the globs have been validated by prepare
+			matchPath, _ := filepath.Match(glob, file)
+			matchBase, _ := filepath.Match(glob, filepath.Base(file))
+			if matchPath || matchBase {
+				sources = append(sources, file)
+				break
+			}
+		}
+	}
+	return sources
+}
+
+// diskKeyName returns the UFile key of the i-th of count disks, suffixing
+// the key with the number of the disk when there is more than one disk, e.g.
+// packer-import-1.vmdk, packer-import-2.vmdk.
+func diskKeyName(keyName string, i, count int) string {
+	if count <= 1 {
+		return keyName
+	}
+
+	ext := path.Ext(keyName)
+	return fmt.Sprintf("%s-%d%s", strings.TrimSuffix(keyName, ext), i+1, ext)
+}
+
+// diskImageName returns the image name of the i-th of count disks.
+func diskImageName(imageName string, i, count int) string {
+	if count <= 1 {
+		return imageName
+	}
+
+	return fmt.Sprintf("%s-%d", imageName, i+1)
+}
+
+// importDisk uploads the image file of the disk to UFile, imports it and
+// waits for the imported image to become available.
+func (p *PostProcessor) importDisk(ctx context.Context, ui packersdk.Ui, client *ucloudcommon.UCloudClient, config *ufsdk.Config, limiter uploadLimiter, disk *importedDisk) error {
+	var err error
+	uhostconn := client.UHostConn
+	bucketName := config.BucketName
+	keyName := disk.keyName
+	source := disk.source
+
+	uploader := newMultipartUploader(config, source+checkpointFileSuffix, limiter,
+		newUploadProgress(ui, p.config.UploadProgressInterval, filepath.Base(source)))
+
+	// hashing a large image file is expensive, so the ETag of the local file
+	// is computed once for both the existing object and the uploaded one.
+	var localEtag string
+	if p.config.SkipUploadVerifyEtag || p.config.ValidateChecksum {
+		localEtag, err = fileEtag(source)
+		if err != nil {
+			return fmt.Errorf("Failed to compute the ETag of %s, %s", source, err)
+		}
+	}
+
+	uploaded := false
+	if p.config.SkipUploadIfExists {
+		uploaded, err = p.isUploaded(ctx, ui, uploader, keyName, source, localEtag)
+		if err != nil {
+			return fmt.Errorf("Failed to check existing UFile: %s/%s, %s", bucketName, keyName, err)
+		}
+	}
+
+	if !uploaded {
+		ui.Say(fmt.Sprintf("Waiting for uploading image file %s to UFile: %s/%s...", source, bucketName, keyName))
+
+		// upload file in segments, the state of the upload is kept in a checkpoint
+		// file next to the source so that an interrupted upload can be resumed,
+		// in which case the file is uploaded under the key of the previous run.
+		keyName, err = uploader.upload(ctx, source, keyName)
+		if err != nil {
+			return fmt.Errorf("Failed to Upload image file %s, %s", source, err)
+		}
+		disk.keyName = keyName
+
+		ui.Say(fmt.Sprintf("Image file %s has been uploaded to UFile: %s/%s", source, bucketName, keyName))
+	}
+
+	// an existing object has already been compared with the local file by
+	// isUploaded, only the file uploaded by this run is left to validate.
+	if p.config.ValidateChecksum && !uploaded {
+		ui.Message(fmt.Sprintf("Validating the checksum of UFile: %s/%s against %s", bucketName, keyName, source))
+		if err = validateChecksum(ctx, uploader, keyName, source, localEtag); err != nil {
+			return fmt.Errorf("Aborting import of UFile: %s/%s, %s", bucketName, keyName, err)
+		}
+	}
+
+	ufileUrl, err := fileURL(client.UFileConn, config, keyName)
+	if err != nil {
+		return fmt.Errorf("Failed to get the URL of UFile: %s/%s, %s", bucketName, keyName, err)
+	}
 
-	importImageRequest := p.buildImportImageRequest(uhostconn, ufileUrl)
+	importImageRequest := p.buildImportImageRequest(uhostconn, disk.imageName, ufileUrl)
 	importImageResponse, err := uhostconn.ImportCustomImage(importImageRequest)
 	if err != nil {
-		return nil, false, false, fmt.Errorf("Failed to import image from UFile: %s/%s, %s", bucketName, keyName, err)
+		return fmt.Errorf("Failed to import image from UFile: %s/%s, %s", bucketName, keyName, err)
 	}
 
 	ui.Say(fmt.Sprintf("Waiting for importing image from UFile: %s/%s ...", bucketName, keyName))
 
 	imageId := importImageResponse.ImageId
-	err = retry.Config{
+	err = p.waitImageAvailable(ctx, client, p.config.ProjectId, p.config.Region, imageId)
+	if err != nil {
+		return fmt.Errorf("Error on waiting for importing image %q from UFile: %s/%s, %s",
+			imageId, bucketName, keyName, err)
+	}
+
+	ui.Say(fmt.Sprintf("Importing created ucloud image %q in region %q Complete.", imageId, p.config.Region))
+	disk.imageId = imageId
+
+	image := ucloudcommon.ImageInfo{
+		ImageId:   imageId,
+		ProjectId: p.config.ProjectId,
+		Region:    p.config.Region,
+	}
+	return p.tagImage(client, image)
+}
+
+// copyImage copies the imported image to each of the given destinations,
+// and waits for every copied image to become available.
+func (p *PostProcessor) copyImage(ctx context.Context, ui packersdk.Ui, client *ucloudcommon.UCloudClient, srcImageId, imageName string, destinations []ucloudcommon.ImageInfo) ([]ucloudcommon.ImageInfo, error) {
+	conn := client.UHostConn
+	var images []ucloudcommon.ImageInfo
+
+	ui.Say(fmt.Sprintf("Copying image %q...", srcImageId))
+	for _, v := range destinations {
+		if v.ProjectId == p.config.ProjectId && v.Region == p.config.Region {
+			continue
+		}
+
+		req := conn.NewCopyCustomImageRequest()
+		req.TargetProjectId = ucloud.String(v.ProjectId)
+		req.TargetRegion = ucloud.String(v.Region)
+		req.SourceImageId = ucloud.String(srcImageId)
+		req.TargetImageName = ucloud.String(imageName)
+		req.TargetImageDescription = ucloud.String(p.config.ImageDescription)
+
+		resp, err := conn.CopyCustomImage(req)
+		if err != nil {
+			return images, fmt.Errorf("Error on copying image %q to %s:%s, %s", srcImageId, v.ProjectId, v.Region, err)
+		}
+
+		images = append(images, ucloudcommon.ImageInfo{
+			ImageId:   resp.TargetImageId,
+			ProjectId: v.ProjectId,
+			Region:    v.Region,
+		})
+		ui.Message(fmt.Sprintf("Copying image from %s:%s:%s to %s:%s:%s",
+			p.config.ProjectId, p.config.Region, srcImageId, v.ProjectId, v.Region, resp.TargetImageId))
+	}
+
+	for _, image := range images {
+		ui.Message(fmt.Sprintf("Waiting for the copied image %s:%s:%s to become available...", image.ProjectId, image.Region, image.ImageId))
+		if err := p.waitImageAvailable(ctx, client, image.ProjectId, image.Region, image.ImageId); err != nil {
+			return images, fmt.Errorf("Error on waiting for copied image %s:%s:%s to become available, %s",
+				image.ProjectId, image.Region, image.ImageId, err)
+		}
+
+		if err := p.tagImage(client, image); err != nil {
+			return images, err
+		}
+	}
+
+	ui.Message("Copying image complete")
+	return images, nil
+}
+
+// copyDestinations returns the projects and regions the imported image has
+// to be copied to, according to copy_to_regions and target_project_id.
+func (p *PostProcessor) copyDestinations() []ucloudcommon.ImageInfo {
+	var destinations []ucloudcommon.ImageInfo
+	for _, region := range p.config.CopyToRegions {
+		destinations = append(destinations, ucloudcommon.ImageInfo{ProjectId: p.config.ProjectId, Region: region})
+	}
+
+	if p.config.TargetProjectId != "" && p.config.TargetProjectId != p.config.ProjectId {
+		regions := append([]string{p.config.Region}, p.config.CopyToRegions...)
+		for _, region := range regions {
+			destinations = append(destinations, ucloudcommon.ImageInfo{ProjectId: p.config.TargetProjectId, Region: region})
+		}
+	}
+
+	return destinations
+}
+
+func (p *PostProcessor) waitImageAvailable(ctx context.Context, client *ucloudcommon.UCloudClient, projectId, region, imageId string) error {
+	return retry.Config{
 		StartTimeout: time.Duration(p.config.WaitImageReadyTimeout) * time.Second,
 		ShouldRetry: func(err error) bool {
 			return ucloudcommon.IsExpectedStateError(err)
 		},
 		RetryDelay: (&retry.Backoff{InitialBackoff: 2 * time.Second, MaxBackoff: 12 * time.Second, Multiplier: 2}).Linear,
 	}.Run(ctx, func(ctx context.Context) error {
-		image, err := client.DescribeImageById(imageId)
+		image, err := client.DescribeImageByInfo(projectId, region, imageId)
 		if err != nil {
 			return err
 		}
 
 		if image.State == ucloudcommon.ImageStateUnavailable {
-			return fmt.Errorf("Unavailable importing image %q", imageId)
+			return fmt.Errorf("Unavailable image %q", imageId)
 		}
 
 		if image.State != ucloudcommon.ImageStateAvailable {
@@ -263,41 +638,11 @@ func (p *PostProcessor) PostProcess(ctx context.Context, ui packersdk.Ui, artifa
 
 		return nil
 	})
-
-	if err != nil {
-		return nil, false, false, fmt.Errorf("Error on waiting for importing image %q from UFile: %s/%s, %s",
-			imageId, bucketName, keyName, err)
-	}
-
-	// Add the reported UCloud image ID to the artifact list
-	ui.Say(fmt.Sprintf("Importing created ucloud image %q in region %q Complete.", imageId, p.config.Region))
-	images := []ucloudcommon.ImageInfo{
-		{
-			ImageId:   imageId,
-			ProjectId: p.config.ProjectId,
-			Region:    p.config.Region,
-		},
-	}
-
-	artifact = &ucloudcommon.Artifact{
-		UCloudImages:   ucloudcommon.NewImageInfoSet(images),
-		BuilderIdValue: BuilderId,
-		Client:         client,
-	}
-
-	if !p.config.SkipClean {
-		ui.Message(fmt.Sprintf("Deleting import source UFile: %s/%s", p.config.UFileBucket, p.config.UFileKey))
-		if err = deleteFile(config, p.config.UFileKey); err != nil {
-			return nil, false, false, fmt.Errorf("Failed to delete UFile: %s/%s, %s", p.config.UFileBucket, p.config.UFileKey, err)
-		}
-	}
-
-	return artifact, false, false, nil
 }
 
-func (p *PostProcessor) buildImportImageRequest(conn *uhost.UHostClient, privateUrl string) *uhost.ImportCustomImageRequest {
+func (p *PostProcessor) buildImportImageRequest(conn *uhost.UHostClient, imageName, privateUrl string) *uhost.ImportCustomImageRequest {
 	req := conn.NewImportCustomImageRequest()
-	req.ImageName = ucloud.String(p.config.ImageName)
+	req.ImageName = ucloud.String(imageName)
 	req.ImageDescription = ucloud.String(p.config.ImageDescription)
 	req.UFileUrl = ucloud.String(privateUrl)
 	req.OsType = ucloud.String(p.config.OSType)
@@ -307,6 +652,62 @@ func (p *PostProcessor) buildImportImageRequest(conn *uhost.UHostClient, private
 	return req
 }
 
+// bindLabelsRequest is the request of the BindLabels action of the UCloud
+// label service, which has no client in the UCloud SDK.
+type bindLabelsRequest struct {
+	request.CommonBase
+
+	ResourceIds []string
+	Labels      []bindLabelsParamLabels
+}
+
+type bindLabelsParamLabels struct {
+	Key   *string
+	Value *string
+}
+
+type bindLabelsResponse struct {
+	response.CommonBase
+}
+
+func (p *PostProcessor) buildBindLabelsRequest(conn *uhost.UHostClient, image ucloudcommon.ImageInfo) *bindLabelsRequest {
+	req := &bindLabelsRequest{}
+	conn.SetupRequest(req)
+	req.ProjectId = ucloud.String(image.ProjectId)
+	req.Region = ucloud.String(image.Region)
+	req.ResourceIds = []string{image.ImageId}
+
+	keys := make([]string, 0, len(p.config.ImageTag))
+	for key := range p.config.ImageTag {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		req.Labels = append(req.Labels, bindLabelsParamLabels{
+			Key:   ucloud.String(key),
+			Value: ucloud.String(p.config.ImageTag[key]),
+		})
+	}
+	return req
+}
+
+// tagImage binds the labels of image_tag to the image.
+func (p *PostProcessor) tagImage(client *ucloudcommon.UCloudClient, image ucloudcommon.ImageInfo) error {
+	if len(p.config.ImageTag) == 0 {
+		return nil
+	}
+
+	conn := client.UHostConn
+	req := p.buildBindLabelsRequest(conn, image)
+	var resp bindLabelsResponse
+	if err := conn.InvokeAction("BindLabels", req, &resp); err != nil {
+		return fmt.Errorf("Error on tagging image %s:%s:%s, %s", image.ProjectId, image.Region, image.ImageId, err)
+	}
+
+	return nil
+}
+
 func queryBucket(conn *ufile.UFileClient, bucketName string) (string, error) {
 	req := conn.NewDescribeBucketRequest()
 	req.BucketName = ucloud.String(bucketName)
@@ -316,22 +717,65 @@ func queryBucket(conn *ufile.UFileClient, bucketName string) (string, error) {
 	}
 
 	if len(resp.DataSet) < 1 {
-		return "", fmt.Errorf("the bucket %s is not exit", bucketName)
+		return "", ucloudcommon.NewNotFoundError("bucket", bucketName)
 	}
 
 	return resp.DataSet[0].Domain.Src[0], nil
 }
 
-func uploadFile(conn *ufile.UFileClient, config *ufsdk.Config, keyName, source string) (string, error) {
-	reqFile, err := ufsdk.NewFileRequest(config, nil)
+func createBucket(conn *ufile.UFileClient, bucketName, bucketType string) error {
+	req := conn.NewCreateBucketRequest()
+	req.BucketName = ucloud.String(bucketName)
+	req.Type = ucloud.String(bucketType)
+	if _, err := conn.CreateBucket(req); err != nil {
+		return fmt.Errorf("error on creating bucket %q, %s", bucketName, err)
+	}
+
+	return nil
+}
+
+// isUploaded reports whether the image file has already been uploaded to
+// UFile under keyName by a previous run. When localEtag is set, the existing
+// object is only reused if its ETag matches it.
+func (p *PostProcessor) isUploaded(ctx context.Context, ui packersdk.Ui, uploader *multipartUploader, keyName, source, localEtag string) (bool, error) {
+	etag, err := uploader.objectEtag(ctx, keyName)
+	if err != nil {
+		return false, err
+	}
+
+	if etag == "" {
+		return false, nil
+	}
+
+	if localEtag != "" && localEtag != etag {
+		ui.Message(fmt.Sprintf("UFile: %s/%s already exists but its ETag %q does not match the ETag %q of %s, uploading it again",
+			p.config.UFileBucket, keyName, etag, localEtag, source))
+		return false, nil
+	}
+
+	ui.Say(fmt.Sprintf("Skipping upload, UFile: %s/%s already exists", p.config.UFileBucket, keyName))
+	return true, nil
+}
+
+// validateChecksum checks that the ETag of the object stored under keyName
+// matches localEtag, the ETag of the local source file.
+func validateChecksum(ctx context.Context, uploader *multipartUploader, keyName, source, localEtag string) error {
+	etag, err := uploader.objectEtag(ctx, keyName)
 	if err != nil {
-		return "", fmt.Errorf("error on building upload file request, %s", err)
+		return fmt.Errorf("error on reading ETag of %s, %s", keyName, err)
 	}
 
-	// upload file in segments
-	err = reqFile.AsyncMPut(source, keyName, "")
+	if etag != localEtag {
+		return fmt.Errorf("the ETag %q of the uploaded file does not match the ETag %q of %s", etag, localEtag, source)
+	}
+
+	return nil
+}
+
+func fileURL(conn *ufile.UFileClient, config *ufsdk.Config, keyName string) (string, error) {
+	reqFile, err := ufsdk.NewFileRequest(config, nil)
 	if err != nil {
-		return "", fmt.Errorf("error on upload file, %s, details: %s", err, reqFile.DumpResponse(true))
+		return "", fmt.Errorf("error on building file request, %s", err)
 	}
 
 	reqBucket := conn.NewDescribeBucketRequest()
@@ -10,30 +10,41 @@ import (
 // FlatConfig is an auto-generated flat version of Config.
 // Where the contents of a field with a `mapstructure:,squash` tag are bubbled up.
 type FlatConfig struct {
-	PackerBuildName       *string           `mapstructure:"packer_build_name" cty:"packer_build_name" hcl:"packer_build_name"`
-	PackerBuilderType     *string           `mapstructure:"packer_builder_type" cty:"packer_builder_type" hcl:"packer_builder_type"`
-	PackerCoreVersion     *string           `mapstructure:"packer_core_version" cty:"packer_core_version" hcl:"packer_core_version"`
-	PackerDebug           *bool             `mapstructure:"packer_debug" cty:"packer_debug" hcl:"packer_debug"`
-	PackerForce           *bool             `mapstructure:"packer_force" cty:"packer_force" hcl:"packer_force"`
-	PackerOnError         *string           `mapstructure:"packer_on_error" cty:"packer_on_error" hcl:"packer_on_error"`
-	PackerUserVars        map[string]string `mapstructure:"packer_user_variables" cty:"packer_user_variables" hcl:"packer_user_variables"`
-	PackerSensitiveVars   []string          `mapstructure:"packer_sensitive_variables" cty:"packer_sensitive_variables" hcl:"packer_sensitive_variables"`
-	PublicKey             *string           `mapstructure:"public_key" required:"true" cty:"public_key" hcl:"public_key"`
-	PrivateKey            *string           `mapstructure:"private_key" required:"true" cty:"private_key" hcl:"private_key"`
-	Region                *string           `mapstructure:"region" required:"true" cty:"region" hcl:"region"`
-	ProjectId             *string           `mapstructure:"project_id" required:"true" cty:"project_id" hcl:"project_id"`
-	BaseUrl               *string           `mapstructure:"base_url" required:"false" cty:"base_url" hcl:"base_url"`
-	Profile               *string           `mapstructure:"profile" required:"false" cty:"profile" hcl:"profile"`
-	SharedCredentialsFile *string           `mapstructure:"shared_credentials_file" required:"false" cty:"shared_credentials_file" hcl:"shared_credentials_file"`
-	UFileBucket           *string           `mapstructure:"ufile_bucket_name" required:"true" cty:"ufile_bucket_name" hcl:"ufile_bucket_name"`
-	UFileKey              *string           `mapstructure:"ufile_key_name" required:"false" cty:"ufile_key_name" hcl:"ufile_key_name"`
-	SkipClean             *bool             `mapstructure:"skip_clean" required:"false" cty:"skip_clean" hcl:"skip_clean"`
-	ImageName             *string           `mapstructure:"image_name" required:"true" cty:"image_name" hcl:"image_name"`
-	ImageDescription      *string           `mapstructure:"image_description" required:"false" cty:"image_description" hcl:"image_description"`
-	OSType                *string           `mapstructure:"image_os_type" required:"true" cty:"image_os_type" hcl:"image_os_type"`
-	OSName                *string           `mapstructure:"image_os_name" required:"true" cty:"image_os_name" hcl:"image_os_name"`
-	Format                *string           `mapstructure:"format" required:"true" cty:"format" hcl:"format"`
-	WaitImageReadyTimeout *int              `mapstructure:"wait_image_ready_timeout" required:"false" cty:"wait_image_ready_timeout" hcl:"wait_image_ready_timeout"`
+	PackerBuildName        *string           `mapstructure:"packer_build_name" cty:"packer_build_name" hcl:"packer_build_name"`
+	PackerBuilderType      *string           `mapstructure:"packer_builder_type" cty:"packer_builder_type" hcl:"packer_builder_type"`
+	PackerCoreVersion      *string           `mapstructure:"packer_core_version" cty:"packer_core_version" hcl:"packer_core_version"`
+	PackerDebug            *bool             `mapstructure:"packer_debug" cty:"packer_debug" hcl:"packer_debug"`
+	PackerForce            *bool             `mapstructure:"packer_force" cty:"packer_force" hcl:"packer_force"`
+	PackerOnError          *string           `mapstructure:"packer_on_error" cty:"packer_on_error" hcl:"packer_on_error"`
+	PackerUserVars         map[string]string `mapstructure:"packer_user_variables" cty:"packer_user_variables" hcl:"packer_user_variables"`
+	PackerSensitiveVars    []string          `mapstructure:"packer_sensitive_variables" cty:"packer_sensitive_variables" hcl:"packer_sensitive_variables"`
+	PublicKey              *string           `mapstructure:"public_key" required:"true" cty:"public_key" hcl:"public_key"`
+	PrivateKey             *string           `mapstructure:"private_key" required:"true" cty:"private_key" hcl:"private_key"`
+	Region                 *string           `mapstructure:"region" required:"true" cty:"region" hcl:"region"`
+	ProjectId              *string           `mapstructure:"project_id" required:"true" cty:"project_id" hcl:"project_id"`
+	BaseUrl                *string           `mapstructure:"base_url" required:"false" cty:"base_url" hcl:"base_url"`
+	Profile                *string           `mapstructure:"profile" required:"false" cty:"profile" hcl:"profile"`
+	SharedCredentialsFile  *string           `mapstructure:"shared_credentials_file" required:"false" cty:"shared_credentials_file" hcl:"shared_credentials_file"`
+	UFileBucket            *string           `mapstructure:"ufile_bucket_name" required:"true" cty:"ufile_bucket_name" hcl:"ufile_bucket_name"`
+	UFileKey               *string           `mapstructure:"ufile_key_name" required:"false" cty:"ufile_key_name" hcl:"ufile_key_name"`
+	CreateBucket           *bool             `mapstructure:"create_bucket" required:"false" cty:"create_bucket" hcl:"create_bucket"`
+	BucketType             *string           `mapstructure:"bucket_type" required:"false" cty:"bucket_type" hcl:"bucket_type"`
+	SkipClean              *bool             `mapstructure:"skip_clean" required:"false" cty:"skip_clean" hcl:"skip_clean"`
+	ImageName              *string           `mapstructure:"image_name" required:"true" cty:"image_name" hcl:"image_name"`
+	ImageDescription       *string           `mapstructure:"image_description" required:"false" cty:"image_description" hcl:"image_description"`
+	OSType                 *string           `mapstructure:"image_os_type" required:"true" cty:"image_os_type" hcl:"image_os_type"`
+	OSName                 *string           `mapstructure:"image_os_name" required:"true" cty:"image_os_name" hcl:"image_os_name"`
+	Format                 *string           `mapstructure:"format" required:"true" cty:"format" hcl:"format"`
+	WaitImageReadyTimeout  *int              `mapstructure:"wait_image_ready_timeout" required:"false" cty:"wait_image_ready_timeout" hcl:"wait_image_ready_timeout"`
+	SkipUploadIfExists     *bool             `mapstructure:"skip_upload_if_exists" required:"false" cty:"skip_upload_if_exists" hcl:"skip_upload_if_exists"`
+	SkipUploadVerifyEtag   *bool             `mapstructure:"skip_upload_verify_etag" required:"false" cty:"skip_upload_verify_etag" hcl:"skip_upload_verify_etag"`
+	ValidateChecksum       *bool             `mapstructure:"validate_checksum" required:"false" cty:"validate_checksum" hcl:"validate_checksum"`
+	UploadConcurrency      *int              `mapstructure:"upload_concurrency" required:"false" cty:"upload_concurrency" hcl:"upload_concurrency"`
+	UploadProgressInterval *string           `mapstructure:"upload_progress_interval" required:"false" cty:"upload_progress_interval" hcl:"upload_progress_interval"`
+	CopyToRegions          []string          `mapstructure:"copy_to_regions" required:"false" cty:"copy_to_regions" hcl:"copy_to_regions"`
+	TargetProjectId        *string           `mapstructure:"target_project_id" required:"false" cty:"target_project_id" hcl:"target_project_id"`
+	ImageTag               map[string]string `mapstructure:"image_tag" required:"false" cty:"image_tag" hcl:"image_tag"`
+	DiskFileGlobs          []string          `mapstructure:"disk_file_globs" required:"false" cty:"disk_file_globs" hcl:"disk_file_globs"`
 }
 
 // FlatMapstructure returns a new FlatConfig.
@@ -65,6 +76,8 @@ func (*FlatConfig) HCL2Spec() map[string]hcldec.Spec {
 		"shared_credentials_file":    &hcldec.AttrSpec{Name: "shared_credentials_file", Type: cty.String, Required: false},
 		"ufile_bucket_name":          &hcldec.AttrSpec{Name: "ufile_bucket_name", Type: cty.String, Required: false},
 		"ufile_key_name":             &hcldec.AttrSpec{Name: "ufile_key_name", Type: cty.String, Required: false},
+		"create_bucket":              &hcldec.AttrSpec{Name: "create_bucket", Type: cty.Bool, Required: false},
+		"bucket_type":                &hcldec.AttrSpec{Name: "bucket_type", Type: cty.String, Required: false},
 		"skip_clean":                 &hcldec.AttrSpec{Name: "skip_clean", Type: cty.Bool, Required: false},
 		"image_name":                 &hcldec.AttrSpec{Name: "image_name", Type: cty.String, Required: false},
 		"image_description":          &hcldec.AttrSpec{Name: "image_description", Type: cty.String, Required: false},
@@ -72,6 +85,15 @@ func (*FlatConfig) HCL2Spec() map[string]hcldec.Spec {
 		"image_os_name":              &hcldec.AttrSpec{Name: "image_os_name", Type: cty.String, Required: false},
 		"format":                     &hcldec.AttrSpec{Name: "format", Type: cty.String, Required: false},
 		"wait_image_ready_timeout":   &hcldec.AttrSpec{Name: "wait_image_ready_timeout", Type: cty.Number, Required: false},
+		"skip_upload_if_exists":      &hcldec.AttrSpec{Name: "skip_upload_if_exists", Type: cty.Bool, Required: false},
+		"skip_upload_verify_etag":    &hcldec.AttrSpec{Name: "skip_upload_verify_etag", Type: cty.Bool, Required: false},
+		"validate_checksum":          &hcldec.AttrSpec{Name: "validate_checksum", Type: cty.Bool, Required: false},
+		"upload_concurrency":         &hcldec.AttrSpec{Name: "upload_concurrency", Type: cty.Number, Required: false},
+		"upload_progress_interval":   &hcldec.AttrSpec{Name: "upload_progress_interval", Type: cty.String, Required: false},
+		"copy_to_regions":            &hcldec.AttrSpec{Name: "copy_to_regions", Type: cty.List(cty.String), Required: false},
+		"target_project_id":          &hcldec.AttrSpec{Name: "target_project_id", Type: cty.String, Required: false},
+		"image_tag":                  &hcldec.AttrSpec{Name: "image_tag", Type: cty.Map(cty.String), Required: false},
+		"disk_file_globs":            &hcldec.AttrSpec{Name: "disk_file_globs", Type: cty.List(cty.String), Required: false},
 	}
 	return s
 }
@@ -0,0 +1,79 @@
+package ucloudimport
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+	builderT "github.com/hashicorp/packer/acctest"
+	ucloudcommon "github.com/hashicorp/packer/builder/ucloud/common"
+)
+
+func TestPostProcessorAcc_formats(t *testing.T) {
+	if os.Getenv(builderT.TestEnvVar) == "" {
+		t.Skip(fmt.Sprintf("Acceptance tests skipped unless env '%s' set", builderT.TestEnvVar))
+		return
+	}
+
+	testAccPreCheck(t)
+
+	for _, format := range []string{ImageFileFormatRAW, ImageFileFormatQCOW2} {
+		format := format
+		t.Run(format, func(t *testing.T) {
+			source := os.Getenv(fmt.Sprintf("UCLOUD_IMPORT_%s_FILE", strings.ToUpper(format)))
+			if source == "" {
+				t.Skip(fmt.Sprintf("no %s image file provided, skipping", format))
+				return
+			}
+
+			var p PostProcessor
+			config := map[string]interface{}{
+				"region":            "cn-bj2",
+				"ufile_bucket_name": os.Getenv("UCLOUD_IMPORT_BUCKET"),
+				"image_name":        fmt.Sprintf("packer-test-import-%s", format),
+				"image_os_type":     "CentOS",
+				"image_os_name":     "CentOS 7.0 64位",
+				"format":            format,
+			}
+			if err := p.Configure(config); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			artifact := &packersdk.MockArtifact{
+				FilesValue: []string{source},
+			}
+
+			result, _, _, err := p.PostProcess(context.Background(), packersdk.TestUi(t), artifact)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			defer result.Destroy()
+
+			images := result.(*ucloudcommon.Artifact).UCloudImages.GetAll()
+			if len(images) != 1 {
+				t.Fatalf("expected one imported image, got %d", len(images))
+			}
+		})
+	}
+}
+
+func testAccPreCheck(t *testing.T) {
+	if v := os.Getenv("UCLOUD_PUBLIC_KEY"); v == "" {
+		t.Fatal("UCLOUD_PUBLIC_KEY must be set for acceptance tests")
+	}
+
+	if v := os.Getenv("UCLOUD_PRIVATE_KEY"); v == "" {
+		t.Fatal("UCLOUD_PRIVATE_KEY must be set for acceptance tests")
+	}
+
+	if v := os.Getenv("UCLOUD_PROJECT_ID"); v == "" {
+		t.Fatal("UCLOUD_PROJECT_ID must be set for acceptance tests")
+	}
+
+	if v := os.Getenv("UCLOUD_IMPORT_BUCKET"); v == "" {
+		t.Fatal("UCLOUD_IMPORT_BUCKET must be set for acceptance tests")
+	}
+}
@@ -0,0 +1,207 @@
+package ucloudimport
+
+import (
+	"testing"
+
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+	ucloudcommon "github.com/hashicorp/packer/builder/ucloud/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func testConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"public_key":        "foo",
+		"private_key":       "bar",
+		"project_id":        "foo",
+		"region":            "cn-bj2",
+		"ufile_bucket_name": "packer-import",
+		"image_name":        "packer_import",
+		"image_os_type":     "CentOS",
+		"image_os_name":     "CentOS 6.10 64位",
+		"format":            "raw",
+	}
+}
+
+func TestPostProcessor_ImplementsPostProcessor(t *testing.T) {
+	var _ packersdk.PostProcessor = new(PostProcessor)
+}
+
+func TestPostProcessor_Configure_Format(t *testing.T) {
+	for _, format := range []string{"raw", "vhd", "vmdk", "qcow2"} {
+		var p PostProcessor
+		config := testConfig()
+		config["format"] = format
+		if err := p.Configure(config); err != nil {
+			t.Fatalf("format %q should be valid: %s", format, err)
+		}
+	}
+
+	var p PostProcessor
+	config := testConfig()
+	config["format"] = "iso"
+	if err := p.Configure(config); err == nil {
+		t.Fatal("format iso should be invalid")
+	}
+}
+
+func TestPostProcessor_buildImportImageRequest_Format(t *testing.T) {
+	tc := map[string]string{
+		"raw":   "RAW",
+		"vhd":   "VHD",
+		"vmdk":  "VMDK",
+		"qcow2": "qcow2",
+	}
+
+	for format, expected := range tc {
+		var p PostProcessor
+		config := testConfig()
+		config["format"] = format
+		if err := p.Configure(config); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		client, err := p.config.Client()
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		req := p.buildImportImageRequest(client.UHostConn, "packer_import", "http://example.com/packer-import")
+		assert.Equal(t, expected, *req.Format, "unexpected import format for %q", format)
+	}
+}
+
+func TestPostProcessor_Configure_CopyToRegions(t *testing.T) {
+	var p PostProcessor
+	config := testConfig()
+	config["copy_to_regions"] = []string{"cn-sh2", "hk"}
+	if err := p.Configure(config); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	assert.Equal(t, []string{"cn-sh2", "hk"}, p.config.CopyToRegions)
+
+	p = PostProcessor{}
+	config["copy_to_regions"] = []string{"cn-sh2", ""}
+	if err := p.Configure(config); err == nil {
+		t.Fatal("should error with empty region in copy_to_regions")
+	}
+}
+
+func TestPostProcessor_Configure_BucketType(t *testing.T) {
+	var p PostProcessor
+	config := testConfig()
+	if err := p.Configure(config); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	assert.Equal(t, BucketTypePrivate, p.config.BucketType)
+
+	p = PostProcessor{}
+	config["create_bucket"] = true
+	config["bucket_type"] = "public"
+	if err := p.Configure(config); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	p = PostProcessor{}
+	config["bucket_type"] = "shared"
+	if err := p.Configure(config); err == nil {
+		t.Fatal("should error with invalid bucket_type")
+	}
+}
+
+func TestPostProcessor_Configure_SkipUploadVerifyEtag(t *testing.T) {
+	var p PostProcessor
+	config := testConfig()
+	config["skip_upload_verify_etag"] = true
+	if err := p.Configure(config); err == nil {
+		t.Fatal("should error with skip_upload_verify_etag but no skip_upload_if_exists")
+	}
+
+	p = PostProcessor{}
+	config["skip_upload_if_exists"] = true
+	if err := p.Configure(config); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestPostProcessor_copyDestinations(t *testing.T) {
+	var p PostProcessor
+	config := testConfig()
+	config["copy_to_regions"] = []string{"cn-sh2"}
+	config["target_project_id"] = "org-target"
+	if err := p.Configure(config); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := []ucloudcommon.ImageInfo{
+		{ProjectId: "foo", Region: "cn-sh2"},
+		{ProjectId: "org-target", Region: "cn-bj2"},
+		{ProjectId: "org-target", Region: "cn-sh2"},
+	}
+	assert.Equal(t, expected, p.copyDestinations())
+}
+
+func TestPostProcessor_buildBindLabelsRequest(t *testing.T) {
+	var p PostProcessor
+	config := testConfig()
+	config["image_tag"] = map[string]string{"team": "packer", "env": "test"}
+	if err := p.Configure(config); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	client, err := p.config.Client()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	image := ucloudcommon.ImageInfo{ImageId: "uimage-xxx", ProjectId: "org-target", Region: "cn-sh2"}
+	req := p.buildBindLabelsRequest(client.UHostConn, image)
+	assert.Equal(t, "org-target", *req.ProjectId)
+	assert.Equal(t, "cn-sh2", *req.Region)
+	assert.Equal(t, []string{"uimage-xxx"}, req.ResourceIds)
+	if assert.Len(t, req.Labels, 2) {
+		assert.Equal(t, "env", *req.Labels[0].Key)
+		assert.Equal(t, "test", *req.Labels[0].Value)
+		assert.Equal(t, "team", *req.Labels[1].Key)
+		assert.Equal(t, "packer", *req.Labels[1].Value)
+	}
+
+	p = PostProcessor{}
+	config["image_tag"] = map[string]string{"": "packer"}
+	if err := p.Configure(config); err == nil {
+		t.Fatal("should error with empty key in image_tag")
+	}
+}
+
+func TestPostProcessor_imageFiles(t *testing.T) {
+	files := []string{"output/disk-1.vmdk", "output/disk-2.vmdk", "output/image.vmx", "output/image.vmdk"}
+
+	var p PostProcessor
+	config := testConfig()
+	config["format"] = "vmdk"
+	if err := p.Configure(config); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	assert.Equal(t, []string{"output/disk-1.vmdk"}, p.imageFiles(files))
+
+	p = PostProcessor{}
+	config["disk_file_globs"] = []string{"disk-*.vmdk"}
+	if err := p.Configure(config); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	assert.Equal(t, []string{"output/disk-1.vmdk", "output/disk-2.vmdk"}, p.imageFiles(files))
+
+	p = PostProcessor{}
+	config["disk_file_globs"] = []string{"[disk"}
+	if err := p.Configure(config); err == nil {
+		t.Fatal("should error with invalid glob in disk_file_globs")
+	}
+}
+
+func TestDiskNames(t *testing.T) {
+	assert.Equal(t, "packer-import.vmdk", diskKeyName("packer-import.vmdk", 0, 1))
+	assert.Equal(t, "packer-import-1.vmdk", diskKeyName("packer-import.vmdk", 0, 2))
+	assert.Equal(t, "packer-import-2.vmdk", diskKeyName("packer-import.vmdk", 1, 2))
+
+	assert.Equal(t, "packer_import", diskImageName("packer_import", 0, 1))
+	assert.Equal(t, "packer_import-2", diskImageName("packer_import", 1, 2))
+}
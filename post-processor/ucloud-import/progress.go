@@ -0,0 +1,104 @@
+package ucloudimport
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/c2h5oh/datasize"
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+)
+
+// uploadProgress periodically reports the progress of an upload through
+// the ui, with the amount of bytes uploaded and the current transfer rate.
+type uploadProgress struct {
+	ui       packersdk.Ui
+	interval time.Duration
+	label    string
+
+	total    int64
+	uploaded int64
+
+	doneCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+func newUploadProgress(ui packersdk.Ui, interval time.Duration, label string) *uploadProgress {
+	return &uploadProgress{
+		ui:       ui,
+		interval: interval,
+		label:    label,
+	}
+}
+
+// start begins reporting the progress of an upload of total bytes, of which
+// uploaded bytes were already uploaded by a previous run.
+func (p *uploadProgress) start(total, uploaded int64) {
+	p.total = total
+	atomic.StoreInt64(&p.uploaded, uploaded)
+	p.doneCh = make(chan struct{})
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		last := uploaded
+		lastTime := time.Now()
+		for {
+			select {
+			case <-p.doneCh:
+				return
+			case now := <-ticker.C:
+				current := atomic.LoadInt64(&p.uploaded)
+				rate := float64(current-last) / now.Sub(lastTime).Seconds()
+				p.ui.Message(p.message(current, rate))
+				last, lastTime = current, now
+			}
+		}
+	}()
+}
+
+// stop stops reporting the progress.
+func (p *uploadProgress) stop() {
+	if p.doneCh == nil {
+		return
+	}
+	close(p.doneCh)
+	p.wg.Wait()
+	p.doneCh = nil
+}
+
+func (p *uploadProgress) message(current int64, rate float64) string {
+	percent := 100.0
+	if p.total > 0 {
+		percent = float64(current) * 100 / float64(p.total)
+	}
+
+	return fmt.Sprintf("%s: uploaded %s of %s (%.1f%%), %s/s",
+		p.label,
+		datasize.ByteSize(current).HumanReadable(),
+		datasize.ByteSize(p.total).HumanReadable(),
+		percent,
+		datasize.ByteSize(rate).HumanReadable())
+}
+
+// reader wraps r so that every byte read from it is accounted as uploaded.
+func (p *uploadProgress) reader(r io.Reader) io.Reader {
+	return &progressReader{r: r, p: p}
+}
+
+type progressReader struct {
+	r io.Reader
+	p *uploadProgress
+}
+
+func (r *progressReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	atomic.AddInt64(&r.p.uploaded, int64(n))
+	return n, err
+}
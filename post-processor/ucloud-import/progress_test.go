@@ -0,0 +1,22 @@
+package ucloudimport
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUploadProgress(t *testing.T) {
+	p := newUploadProgress(packersdk.TestUi(t), time.Hour, "image.raw")
+	p.start(20, 5)
+	defer p.stop()
+
+	_, err := ioutil.ReadAll(p.reader(strings.NewReader("01234")))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), p.uploaded)
+	assert.Contains(t, p.message(p.uploaded, 0), "(50.0%)")
+}
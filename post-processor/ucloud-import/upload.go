@@ -0,0 +1,420 @@
+package ucloudimport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-cleanhttp"
+	ufsdk "github.com/ufilesdk-dev/ufile-gosdk"
+)
+
+// checkpointFileSuffix is appended to the path of the uploaded file to name
+// the local checkpoint file of a multipart upload.
+const checkpointFileSuffix = ".ufile-upload.json"
+
+// uploadCheckpoint records the state of a multipart upload to UFile. It is
+// persisted after every completed part, so that a later run of the
+// post-processor can resume the upload instead of starting from zero.
+type uploadCheckpoint struct {
+	Bucket   string         `json:"bucket"`
+	Key      string         `json:"key"`
+	Size     int64          `json:"size"`
+	ModTime  time.Time      `json:"mod_time"`
+	UploadId string         `json:"upload_id"`
+	BlkSize  int64          `json:"blk_size"`
+	Etags    map[int]string `json:"etags"`
+}
+
+// matches reports whether the checkpoint was recorded for an upload of the
+// same, unchanged, local file to the same bucket. The key is not compared,
+// as the default key is rendered with a timestamp and differs on every run.
+func (c *uploadCheckpoint) matches(bucket string, fi os.FileInfo) bool {
+	return c.Bucket == bucket &&
+		c.Size == fi.Size() &&
+		c.ModTime.Equal(fi.ModTime()) &&
+		c.UploadId != "" &&
+		c.BlkSize > 0
+}
+
+func (c *uploadCheckpoint) partCount() int {
+	return int((c.Size + c.BlkSize - 1) / c.BlkSize)
+}
+
+// partRange returns the offset and the size of the given part in the file.
+func (c *uploadCheckpoint) partRange(part int) (int64, int64) {
+	offset := int64(part) * c.BlkSize
+	size := c.BlkSize
+	if offset+size > c.Size {
+		size = c.Size - offset
+	}
+	return offset, size
+}
+
+// uploadedSize returns the amount of bytes of the completed parts.
+func (c *uploadCheckpoint) uploadedSize() int64 {
+	var uploaded int64
+	for part := range c.Etags {
+		_, size := c.partRange(part)
+		uploaded += size
+	}
+	return uploaded
+}
+
+func loadCheckpoint(path string) (*uploadCheckpoint, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &uploadCheckpoint{}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, err
+	}
+	if c.Etags == nil {
+		c.Etags = make(map[int]string)
+	}
+	return c, nil
+}
+
+func (c *uploadCheckpoint) save(path string) error {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first, so that an interruption can not leave
+	// a truncated checkpoint behind.
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// uploadLimiter bounds the number of parts uploaded in parallel by all the
+// uploaders sharing it.
+type uploadLimiter chan struct{}
+
+func newUploadLimiter(concurrency int) uploadLimiter {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return make(uploadLimiter, concurrency)
+}
+
+// multipartUploader uploads a local file to UFile with the multipart upload
+// API, resuming from its checkpoint file when possible.
+type multipartUploader struct {
+	config         *ufsdk.Config
+	auth           ufsdk.Auth
+	client         *http.Client
+	baseURL        string
+	checkpointPath string
+
+	// limiter bounds the number of parts uploaded in parallel, it may be
+	// shared with the uploaders of other files.
+	limiter uploadLimiter
+
+	// progress, when set, is used to report the progress of the upload.
+	progress *uploadProgress
+}
+
+func newMultipartUploader(config *ufsdk.Config, checkpointPath string, limiter uploadLimiter, progress *uploadProgress) *multipartUploader {
+	return &multipartUploader{
+		config:         config,
+		auth:           ufsdk.NewAuth(config.PublicKey, config.PrivateKey),
+		client:         cleanhttp.DefaultPooledClient(),
+		baseURL:        fmt.Sprintf("http://%s.%s", config.BucketName, config.FileHost),
+		checkpointPath: checkpointPath,
+		limiter:        limiter,
+		progress:       progress,
+	}
+}
+
+// upload uploads source to UFile under keyName, and returns the key the file
+// has been uploaded to. When an interrupted upload of the same file is
+// resumed, this is the key of the interrupted upload.
+func (u *multipartUploader) upload(ctx context.Context, source, keyName string) (string, error) {
+	f, err := os.Open(source)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	checkpoint, err := loadCheckpoint(u.checkpointPath)
+	switch {
+	case err == nil && checkpoint.matches(u.config.BucketName, fi):
+		log.Printf("Resuming upload of %s to %s with %d of %d parts already uploaded",
+			source, checkpoint.Key, len(checkpoint.Etags), checkpoint.partCount())
+	default:
+		if err == nil {
+			// the checkpoint belongs to an upload which can not be resumed
+			// anymore, abort it so that its parts do not pile up in the bucket.
+			u.abort(ctx, checkpoint)
+		} else if !os.IsNotExist(err) {
+			log.Printf("Ignoring unreadable upload checkpoint %s: %s", u.checkpointPath, err)
+		}
+		checkpoint, err = u.initiate(ctx, keyName, fi)
+		if err != nil {
+			return "", err
+		}
+		if err := checkpoint.save(u.checkpointPath); err != nil {
+			u.abort(ctx, checkpoint)
+			return "", fmt.Errorf("error on saving upload checkpoint %s, %s", u.checkpointPath, err)
+		}
+	}
+
+	if u.progress != nil {
+		u.progress.start(checkpoint.Size, checkpoint.uploadedSize())
+		defer u.progress.stop()
+	}
+
+	var parts []int
+	for part := 0; part < checkpoint.partCount(); part++ {
+		if _, ok := checkpoint.Etags[part]; !ok {
+			parts = append(parts, part)
+		}
+	}
+
+	// upload the remaining parts with a pool of workers, stopping at the first
+	// failure; the parts uploaded so far stay in the checkpoint. Every part
+	// takes a slot of the limiter while it is uploaded.
+	var mu sync.Mutex
+	var uploadErr error
+	partCh := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < cap(u.limiter); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for part := range partCh {
+				select {
+				case u.limiter <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+
+				offset, size := checkpoint.partRange(part)
+				var body io.Reader = io.NewSectionReader(f, offset, size)
+				if u.progress != nil {
+					body = u.progress.reader(body)
+				}
+
+				etag, err := u.uploadPart(ctx, checkpoint.Key, checkpoint.UploadId, part, body, size)
+				<-u.limiter
+
+				mu.Lock()
+				if err != nil {
+					if uploadErr == nil {
+						uploadErr = fmt.Errorf("error on uploading part %d, %w", part, err)
+					}
+				} else {
+					checkpoint.Etags[part] = etag
+					if err := checkpoint.save(u.checkpointPath); err != nil && uploadErr == nil {
+						uploadErr = fmt.Errorf("error on saving upload checkpoint %s, %s", u.checkpointPath, err)
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+dispatch:
+	for _, part := range parts {
+		mu.Lock()
+		failed := uploadErr != nil
+		mu.Unlock()
+		if failed {
+			break
+		}
+
+		select {
+		case partCh <- part:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(partCh)
+	wg.Wait()
+
+	if uploadErr == nil {
+		uploadErr = ctx.Err()
+	}
+
+	if uploadErr == nil {
+		uploadErr = u.finish(ctx, checkpoint)
+	}
+
+	if uploadErr != nil {
+		// UFile rejected the upload, so it can not be resumed by a later run.
+		if isClientError(uploadErr) {
+			u.abort(ctx, checkpoint)
+			os.Remove(u.checkpointPath)
+		}
+		return "", uploadErr
+	}
+
+	return checkpoint.Key, os.Remove(u.checkpointPath)
+}
+
+func (u *multipartUploader) initiate(ctx context.Context, keyName string, fi os.FileInfo) (*uploadCheckpoint, error) {
+	resp, err := u.do(ctx, http.MethodPost, keyName, "uploads", nil, 0, "application/octet-stream")
+	if err != nil {
+		return nil, fmt.Errorf("error on initiating multipart upload, %s", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		UploadId string `json:"UploadId"`
+		BlkSize  int64  `json:"BlkSize"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("error on reading multipart upload response, %s", err)
+	}
+
+	return &uploadCheckpoint{
+		Bucket:   u.config.BucketName,
+		Key:      keyName,
+		Size:     fi.Size(),
+		ModTime:  fi.ModTime(),
+		UploadId: result.UploadId,
+		BlkSize:  result.BlkSize,
+		Etags:    make(map[int]string),
+	}, nil
+}
+
+func (u *multipartUploader) uploadPart(ctx context.Context, keyName, uploadId string, part int, body io.Reader, size int64) (string, error) {
+	query := url.Values{
+		"uploadId":   {uploadId},
+		"partNumber": {fmt.Sprintf("%d", part)},
+	}
+	resp, err := u.do(ctx, http.MethodPut, keyName, query.Encode(), body, size, "application/octet-stream")
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	etag := strings.Trim(resp.Header.Get("ETag"), `"`)
+	if etag == "" {
+		return "", fmt.Errorf("no ETag returned for part %d", part)
+	}
+	return etag, nil
+}
+
+func (u *multipartUploader) finish(ctx context.Context, checkpoint *uploadCheckpoint) error {
+	etags := make([]string, checkpoint.partCount())
+	for i := range etags {
+		etags[i] = checkpoint.Etags[i]
+	}
+	body := strings.Join(etags, ",")
+
+	query := url.Values{
+		"uploadId": {checkpoint.UploadId},
+		"newKey":   {checkpoint.Key},
+	}
+	resp, err := u.do(ctx, http.MethodPost, checkpoint.Key, query.Encode(), strings.NewReader(body), int64(len(body)), "text/plain")
+	if err != nil {
+		return fmt.Errorf("error on finishing multipart upload, %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// abort aborts the multipart upload of the checkpoint, so that UFile discards
+// its uploaded parts. This is best effort, failures are only logged.
+func (u *multipartUploader) abort(ctx context.Context, checkpoint *uploadCheckpoint) {
+	if checkpoint.UploadId == "" || checkpoint.Bucket != u.config.BucketName {
+		return
+	}
+
+	query := url.Values{"uploadId": {checkpoint.UploadId}}
+	resp, err := u.do(ctx, http.MethodDelete, checkpoint.Key, query.Encode(), nil, 0, "")
+	if err != nil {
+		log.Printf("Failed to abort multipart upload %s of %s: %s", checkpoint.UploadId, checkpoint.Key, err)
+		return
+	}
+	resp.Body.Close()
+}
+
+// objectEtag returns the ETag of the object stored under keyName, or an
+// empty string if there is no such object in the bucket.
+func (u *multipartUploader) objectEtag(ctx context.Context, keyName string) (string, error) {
+	resp, err := u.do(ctx, http.MethodHead, keyName, "", nil, 0, "")
+	if err != nil {
+		if statusErr, ok := err.(*ufileStatusError); ok && statusErr.StatusCode == http.StatusNotFound {
+			return "", nil
+		}
+		return "", err
+	}
+	resp.Body.Close()
+
+	return strings.Trim(resp.Header.Get("ETag"), `"`), nil
+}
+
+// ufileStatusError is returned when UFile answers a request with a non
+// successful status code.
+type ufileStatusError struct {
+	StatusCode int
+	Status     string
+	Body       string
+}
+
+func (e *ufileStatusError) Error() string {
+	return fmt.Sprintf("unexpected status %s, %s", e.Status, e.Body)
+}
+
+// isClientError reports whether err is a 4xx answer of UFile, which will not
+// succeed by sending the same request again.
+func isClientError(err error) bool {
+	var statusErr *ufileStatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode >= 400 && statusErr.StatusCode < 500
+}
+
+func (u *multipartUploader) do(ctx context.Context, method, keyName, rawQuery string, body io.Reader, size int64, contentType string) (*http.Response, error) {
+	reqURL := u.baseURL + (&url.URL{Path: "/" + keyName}).EscapedPath()
+	if rawQuery != "" {
+		reqURL += "?" + rawQuery
+	}
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, body)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = size
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Authorization", u.auth.Authorization(method, u.config.BucketName, keyName, req.Header))
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		b, _ := ioutil.ReadAll(resp.Body)
+		return nil, &ufileStatusError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Body:       string(b),
+		}
+	}
+
+	return resp, nil
+}
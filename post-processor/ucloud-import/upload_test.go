@@ -0,0 +1,272 @@
+package ucloudimport
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	ufsdk "github.com/ufilesdk-dev/ufile-gosdk"
+)
+
+func TestMultipartUploader_resume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "packer-ucloud-import")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	source := filepath.Join(dir, "image.qcow2")
+	if err := ioutil.WriteFile(source, []byte("0123456789"), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var initiated, finished int
+	var finishBody string
+	uploaded := make(map[string]string)
+	failPart := "1"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		q := r.URL.Query()
+		switch {
+		case r.Method == http.MethodPost && r.URL.RawQuery == "uploads":
+			initiated++
+			fmt.Fprint(w, `{"UploadId": "upload-id", "BlkSize": 4}`)
+		case r.Method == http.MethodPut:
+			part := q.Get("partNumber")
+			if part == failPart {
+				failPart = ""
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			uploaded[part] = string(body)
+			w.Header().Set("ETag", fmt.Sprintf(`"etag-%s"`, part))
+		case r.Method == http.MethodPost && q.Get("uploadId") == "upload-id":
+			finished++
+			finishBody = string(body)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	config := &ufsdk.Config{PublicKey: "foo", PrivateKey: "bar", BucketName: "bucket"}
+	checkpointPath := source + checkpointFileSuffix
+	uploader := newMultipartUploader(config, checkpointPath, newUploadLimiter(1), nil)
+	uploader.baseURL = server.URL
+
+	_, err = uploader.upload(context.Background(), source, "image.qcow2")
+	assert.Error(t, err, "the first upload should be interrupted")
+	assert.FileExists(t, checkpointPath)
+
+	// the upload is resumed under its original key, even though the key
+	// rendered by this run differs, such as with a timestamp in the key.
+	keyName, err := uploader.upload(context.Background(), source, "image-2.qcow2")
+	assert.NoError(t, err)
+	assert.Equal(t, "image.qcow2", keyName)
+
+	assert.Equal(t, 1, initiated, "the upload should have been resumed")
+	assert.Equal(t, 1, finished)
+	assert.Equal(t, map[string]string{"0": "0123", "1": "4567", "2": "89"}, uploaded)
+	assert.Equal(t, "etag-0,etag-1,etag-2", finishBody)
+	assert.NoFileExists(t, checkpointPath)
+}
+
+func TestMultipartUploader_abort(t *testing.T) {
+	dir, err := ioutil.TempDir("", "packer-ucloud-import")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	source := filepath.Join(dir, "image.raw")
+	if err := ioutil.WriteFile(source, []byte("0123456789"), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// a checkpoint of a previous version of the file
+	checkpointPath := source + checkpointFileSuffix
+	stale := &uploadCheckpoint{
+		Bucket:   "bucket",
+		Key:      "image.raw",
+		Size:     4,
+		UploadId: "stale-id",
+		BlkSize:  4,
+		Etags:    map[int]string{0: "etag-0"},
+	}
+	if err := stale.save(checkpointPath); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var aborted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.RawQuery == "uploads":
+			fmt.Fprint(w, `{"UploadId": "upload-id", "BlkSize": 4}`)
+		case r.Method == http.MethodDelete:
+			aborted = append(aborted, r.URL.Query().Get("uploadId"))
+		default:
+			// the upload is unknown to UFile, and can not be resumed.
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	config := &ufsdk.Config{PublicKey: "foo", PrivateKey: "bar", BucketName: "bucket"}
+	uploader := newMultipartUploader(config, checkpointPath, newUploadLimiter(1), nil)
+	uploader.baseURL = server.URL
+
+	_, err = uploader.upload(context.Background(), source, "image.raw")
+	assert.Error(t, err)
+	assert.Equal(t, []string{"stale-id", "upload-id"}, aborted)
+	assert.NoFileExists(t, checkpointPath)
+}
+
+func TestMultipartUploader_objectEtag(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch r.URL.Path {
+		case "/exists.raw":
+			w.Header().Set("ETag", `"AQAAAIes7BfNnc0gpxbMLPZ0F7ccinAW"`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	config := &ufsdk.Config{PublicKey: "foo", PrivateKey: "bar", BucketName: "bucket"}
+	uploader := newMultipartUploader(config, "", newUploadLimiter(1), nil)
+	uploader.baseURL = server.URL
+
+	etag, err := uploader.objectEtag(context.Background(), "exists.raw")
+	assert.NoError(t, err)
+	assert.Equal(t, "AQAAAIes7BfNnc0gpxbMLPZ0F7ccinAW", etag)
+
+	etag, err = uploader.objectEtag(context.Background(), "missing.raw")
+	assert.NoError(t, err)
+	assert.Empty(t, etag)
+}
+
+func TestValidateChecksum(t *testing.T) {
+	f, err := ioutil.TempFile("", "packer-ucloud-import")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString("0123456789")
+	f.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/good.raw":
+			w.Header().Set("ETag", `"AQAAAIes7BfNnc0gpxbMLPZ0F7ccinAW"`)
+		case "/corrupted.raw":
+			w.Header().Set("ETag", `"AQAAAOlzydfhkRy8l6ELFSiVk1Drdckm"`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	config := &ufsdk.Config{PublicKey: "foo", PrivateKey: "bar", BucketName: "bucket"}
+	uploader := newMultipartUploader(config, "", newUploadLimiter(1), nil)
+	uploader.baseURL = server.URL
+
+	localEtag, err := fileEtag(f.Name())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	assert.NoError(t, validateChecksum(context.Background(), uploader, "good.raw", f.Name(), localEtag))
+	assert.Error(t, validateChecksum(context.Background(), uploader, "corrupted.raw", f.Name(), localEtag))
+	assert.Error(t, validateChecksum(context.Background(), uploader, "missing.raw", f.Name(), localEtag))
+}
+
+func TestMultipartUploader_concurrency(t *testing.T) {
+	dir, err := ioutil.TempDir("", "packer-ucloud-import")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var sources []string
+	for _, name := range []string{"disk-1.raw", "disk-2.raw"} {
+		source := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(source, []byte("0123456789abcdefghij"), 0644); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		sources = append(sources, source)
+	}
+
+	var mu sync.Mutex
+	var inFlight, maxInFlight int
+	uploaded := make(map[string]string)
+	finished := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		key := strings.TrimPrefix(r.URL.Path, "/")
+		switch {
+		case r.Method == http.MethodPost && r.URL.RawQuery == "uploads":
+			fmt.Fprint(w, `{"UploadId": "upload-id", "BlkSize": 3}`)
+		case r.Method == http.MethodPut:
+			mu.Lock()
+			inFlight++
+			if inFlight > maxInFlight {
+				maxInFlight = inFlight
+			}
+			mu.Unlock()
+
+			time.Sleep(10 * time.Millisecond)
+
+			part := r.URL.Query().Get("partNumber")
+			mu.Lock()
+			inFlight--
+			uploaded[key+"/"+part] = string(body)
+			mu.Unlock()
+			w.Header().Set("ETag", fmt.Sprintf(`"etag-%s"`, part))
+		case r.Method == http.MethodPost:
+			mu.Lock()
+			finished[key] = string(body)
+			mu.Unlock()
+		}
+	}))
+	defer server.Close()
+
+	// both files share the same limit of parts uploaded in parallel
+	config := &ufsdk.Config{PublicKey: "foo", PrivateKey: "bar", BucketName: "bucket"}
+	limiter := newUploadLimiter(2)
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(sources))
+	for i, source := range sources {
+		uploader := newMultipartUploader(config, source+checkpointFileSuffix, limiter, nil)
+		uploader.baseURL = server.URL
+
+		wg.Add(1)
+		go func(i int, source string) {
+			defer wg.Done()
+			_, errs[i] = uploader.upload(context.Background(), source, filepath.Base(source))
+		}(i, source)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.LessOrEqual(t, maxInFlight, 2)
+	assert.Len(t, uploaded, 14)
+	assert.Equal(t, "ij", uploaded["disk-2.raw/6"])
+	assert.Equal(t, "etag-0,etag-1,etag-2,etag-3,etag-4,etag-5,etag-6", finished["disk-1.raw"])
+	assert.Equal(t, "etag-0,etag-1,etag-2,etag-3,etag-4,etag-5,etag-6", finished["disk-2.raw"])
+}
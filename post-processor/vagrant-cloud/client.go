@@ -9,9 +9,11 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/hashicorp/packer-plugin-sdk/net"
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
 )
 
 type VagrantCloudClient struct {
@@ -125,7 +127,7 @@ func (v *VagrantCloudClient) Delete(path string) (*http.Response, error) {
 	return resp, err
 }
 
-func (v *VagrantCloudClient) Upload(path string, url string) (*http.Response, error) {
+func (v *VagrantCloudClient) Upload(ui packersdk.Ui, path string, url string) (*http.Response, error) {
 	file, err := os.Open(path)
 
 	if err != nil {
@@ -140,7 +142,10 @@ func (v *VagrantCloudClient) Upload(path string, url string) (*http.Response, er
 
 	defer file.Close()
 
-	request, err := v.newRequest("PUT", url, file)
+	// The body, and so the progress bar, is closed once the request is done
+	body := ui.TrackProgress(filepath.Base(path), 0, fi.Size(), file)
+
+	request, err := v.newRequest("PUT", url, body)
 
 	if err != nil {
 		return nil, fmt.Errorf("Error preparing upload request: %s", err)
@@ -156,7 +161,7 @@ func (v *VagrantCloudClient) Upload(path string, url string) (*http.Response, er
 	return resp, err
 }
 
-func (v *VagrantCloudClient) DirectUpload(path string, url string) (*http.Response, error) {
+func (v *VagrantCloudClient) DirectUpload(ui packersdk.Ui, path string, url string) (*http.Response, error) {
 	file, err := os.Open(path)
 
 	if err != nil {
@@ -170,7 +175,10 @@ func (v *VagrantCloudClient) DirectUpload(path string, url string) (*http.Respon
 		return nil, fmt.Errorf("Error stating file for upload: %s", err)
 	}
 
-	request, err := http.NewRequest("PUT", url, file)
+	// The body, and so the progress bar, is closed once the request is done
+	body := ui.TrackProgress(filepath.Base(path), 0, fi.Size(), file)
+
+	request, err := http.NewRequest("PUT", url, body)
 
 	if err != nil {
 		return nil, fmt.Errorf("Error preparing upload request: %s", err)
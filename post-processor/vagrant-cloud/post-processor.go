@@ -17,6 +17,7 @@ import (
 	"log"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/hashicorp/packer-plugin-sdk/common"
@@ -49,6 +50,9 @@ type Config struct {
 	BoxDownloadUrl        string `mapstructure:"box_download_url"`
 	NoDirectUpload        bool   `mapstructure:"no_direct_upload"`
 
+	UploadTries      int           `mapstructure:"upload_tries"`
+	UploadRetryDelay time.Duration `mapstructure:"upload_retry_delay"`
+
 	ctx interpolate.Context
 }
 
@@ -82,6 +86,14 @@ func (p *PostProcessor) Configure(raws ...interface{}) error {
 		p.config.VagrantCloudUrl = VAGRANT_CLOUD_URL
 	}
 
+	if p.config.UploadTries == 0 {
+		p.config.UploadTries = 3
+	}
+
+	if p.config.UploadRetryDelay == 0 {
+		p.config.UploadRetryDelay = 10 * time.Second
+	}
+
 	p.insecureSkipTLSVerify = p.config.InsecureSkipTLSVerify == true && p.config.VagrantCloudUrl != VAGRANT_CLOUD_URL
 
 	if p.config.AccessToken == "" {
@@ -111,6 +123,10 @@ func (p *PostProcessor) Configure(raws ...interface{}) error {
 		}
 	}
 
+	if p.config.UploadTries < 0 {
+		errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("upload_tries must be greater than 0"))
+	}
+
 	if p.config.VagrantCloudUrl == VAGRANT_CLOUD_URL && p.config.AccessToken == "" {
 		errs = packersdk.MultiErrorAppend(errs, fmt.Errorf("access_token must be set if vagrant_cloud_url has not been overridden"))
 	}
@@ -27,6 +27,8 @@ type FlatConfig struct {
 	InsecureSkipTLSVerify *bool             `mapstructure:"insecure_skip_tls_verify" cty:"insecure_skip_tls_verify" hcl:"insecure_skip_tls_verify"`
 	BoxDownloadUrl        *string           `mapstructure:"box_download_url" cty:"box_download_url" hcl:"box_download_url"`
 	NoDirectUpload        *bool             `mapstructure:"no_direct_upload" cty:"no_direct_upload" hcl:"no_direct_upload"`
+	UploadTries           *int              `mapstructure:"upload_tries" cty:"upload_tries" hcl:"upload_tries"`
+	UploadRetryDelay      *string           `mapstructure:"upload_retry_delay" cty:"upload_retry_delay" hcl:"upload_retry_delay"`
 }
 
 // FlatMapstructure returns a new FlatConfig.
@@ -58,6 +60,8 @@ func (*FlatConfig) HCL2Spec() map[string]hcldec.Spec {
 		"insecure_skip_tls_verify":   &hcldec.AttrSpec{Name: "insecure_skip_tls_verify", Type: cty.Bool, Required: false},
 		"box_download_url":           &hcldec.AttrSpec{Name: "box_download_url", Type: cty.String, Required: false},
 		"no_direct_upload":           &hcldec.AttrSpec{Name: "no_direct_upload", Type: cty.Bool, Required: false},
+		"upload_tries":               &hcldec.AttrSpec{Name: "upload_tries", Type: cty.Number, Required: false},
+		"upload_retry_delay":         &hcldec.AttrSpec{Name: "upload_retry_delay", Type: cty.String, Required: false},
 	}
 	return s
 }
@@ -3,6 +3,7 @@ package vagrantcloud
 import (
 	"context"
 	"fmt"
+	"log"
 	"os"
 
 	"github.com/hashicorp/packer-plugin-sdk/multistep"
@@ -40,41 +41,49 @@ func (s *stepPrepareUpload) Run(ctx context.Context, state multistep.StateBag) m
 		}
 	}
 
+	ui.Say(fmt.Sprintf("Preparing upload of box: %s", artifactFilePath))
+
+	upload, err := prepareUpload(client, config, box, version, provider)
+	if err != nil {
+		state.Put("error", err)
+		return multistep.ActionHalt
+	}
+
+	// Save the upload details to the state
+	state.Put("upload", upload)
+
+	return multistep.ActionContinue
+}
+
+func (s *stepPrepareUpload) Cleanup(state multistep.StateBag) {
+	// No cleanup
+}
+
+// prepareUpload requests the URLs to upload the box to. They can expire or be
+// used only once, so new ones are requested for every upload attempt.
+func prepareUpload(client *VagrantCloudClient, config *Config, box *Box, version *Version, provider *Provider) (*Upload, error) {
 	path := fmt.Sprintf("box/%s/version/%v/provider/%s/upload", box.Tag, version.Version, provider.Name)
 	if !config.NoDirectUpload {
 		path = path + "/direct"
 	}
 	upload := &Upload{}
 
-	ui.Say(fmt.Sprintf("Preparing upload of box: %s", artifactFilePath))
-
 	resp, err := client.Get(path)
 
 	if err != nil || (resp.StatusCode != 200) {
 		if resp == nil || resp.Body == nil {
-			state.Put("error", fmt.Errorf("No response from server."))
-		} else {
-			cloudErrors := &VagrantCloudErrors{}
-			err = decodeBody(resp, cloudErrors)
-			if err != nil {
-				ui.Error(fmt.Sprintf("error decoding error response: %s", err))
-			}
-			state.Put("error", fmt.Errorf("Error preparing upload: %s", cloudErrors.FormatErrors()))
+			return nil, fmt.Errorf("No response from server.")
 		}
-		return multistep.ActionHalt
+		cloudErrors := &VagrantCloudErrors{}
+		if err := decodeBody(resp, cloudErrors); err != nil {
+			log.Printf("error decoding error response: %s", err)
+		}
+		return nil, fmt.Errorf("Error preparing upload: %s", cloudErrors.FormatErrors())
 	}
 
 	if err = decodeBody(resp, upload); err != nil {
-		state.Put("error", fmt.Errorf("Error parsing upload response: %s", err))
-		return multistep.ActionHalt
+		return nil, fmt.Errorf("Error parsing upload response: %s", err)
 	}
 
-	// Save the upload details to the state
-	state.Put("upload", upload)
-
-	return multistep.ActionContinue
-}
-
-func (s *stepPrepareUpload) Cleanup(state multistep.StateBag) {
-	// No cleanup
+	return upload, nil
 }
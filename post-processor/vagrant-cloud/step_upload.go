@@ -19,40 +19,52 @@ func (s *stepUpload) Run(ctx context.Context, state multistep.StateBag) multiste
 	client := state.Get("client").(*VagrantCloudClient)
 	config := state.Get("config").(*Config)
 	ui := state.Get("ui").(packersdk.Ui)
-	upload := state.Get("upload").(*Upload)
 	artifactFilePath := state.Get("artifactFilePath").(string)
-	url := upload.UploadPath
 
 	ui.Say(fmt.Sprintf("Uploading box: %s", artifactFilePath))
 	ui.Message(
 		"Depending on your internet connection and the size of the box,\n" +
 			"this may take some time")
 
+	attempt := 0
 	err := retry.Config{
-		Tries:      3,
-		RetryDelay: (&retry.Backoff{InitialBackoff: 10 * time.Second, MaxBackoff: 10 * time.Second, Multiplier: 2}).Linear,
+		Tries:      config.UploadTries,
+		RetryDelay: func() time.Duration { return config.UploadRetryDelay },
 	}.Run(ctx, func(ctx context.Context) error {
+		attempt++
+		if attempt > 1 {
+			upload, err := prepareUpload(client, config,
+				state.Get("box").(*Box), state.Get("version").(*Version), state.Get("provider").(*Provider))
+			if err != nil {
+				ui.Message(fmt.Sprintf(
+					"Error preparing upload! Will retry in %s. Error: %s", config.UploadRetryDelay, err))
+				return err
+			}
+			state.Put("upload", upload)
+		}
+		url := state.Get("upload").(*Upload).UploadPath
+
 		ui.Message(fmt.Sprintf("Uploading box"))
 
 		var err error
 		var resp *http.Response
 
 		if config.NoDirectUpload {
-			resp, err = client.Upload(artifactFilePath, url)
+			resp, err = client.Upload(ui, artifactFilePath, url)
 		} else {
-			resp, err = client.DirectUpload(artifactFilePath, url)
+			resp, err = client.DirectUpload(ui, artifactFilePath, url)
 		}
 		if err != nil {
 			ui.Message(fmt.Sprintf(
-				"Error uploading box! Will retry in 10 seconds. Error: %s", err))
+				"Error uploading box! Will retry in %s. Error: %s", config.UploadRetryDelay, err))
 			return err
 		}
 		if resp.StatusCode != 200 {
 			err := fmt.Errorf("bad HTTP status: %d", resp.StatusCode)
 			log.Print(err)
 			ui.Message(fmt.Sprintf(
-				"Error uploading box! Will retry in 10 seconds. Status: %d",
-				resp.StatusCode))
+				"Error uploading box! Will retry in %s. Status: %d",
+				config.UploadRetryDelay, resp.StatusCode))
 			return err
 		}
 		return err
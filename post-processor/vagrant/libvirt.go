@@ -1,7 +1,13 @@
 package vagrant
 
 import (
+	"bytes"
+	"encoding/binary"
 	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -58,33 +64,76 @@ func sizeInMegabytes(size string) uint64 {
 	}
 }
 
-type LibVirtProvider struct{}
+type LibVirtProvider struct {
+	// StoragePool is the name of the libvirt storage pool the box volume
+	// is created in. The default pool of vagrant-libvirt is used when empty.
+	StoragePool string
+}
 
 func (p *LibVirtProvider) KeepInputArtifact() bool {
 	return false
 }
 func (p *LibVirtProvider) Process(ui packersdk.Ui, artifact packersdk.Artifact, dir string) (vagrantfile string, metadata map[string]interface{}, err error) {
-	diskName := artifact.State("diskName").(string)
-
-	// Copy the disk image into the temporary directory (as box.img)
-	for _, path := range artifact.Files() {
-		if strings.HasSuffix(path, "/"+diskName) {
-			ui.Message(fmt.Sprintf("Copying from artifact: %s", path))
-			dstPath := filepath.Join(dir, "box.img")
-			if err = CopyContents(dstPath, path); err != nil {
-				return
-			}
+	diskName, _ := artifact.State("diskName").(string)
+	diskPath, err := libvirtDiskPath(artifact.Files(), diskName)
+	if err != nil {
+		return
+	}
+
+	// Artifacts that don't come from the QEMU builder, like the ones of
+	// the artifice post-processor, don't describe their disk, so read the
+	// format and the virtual size from the image itself.
+	format, _ := artifact.State("diskType").(string)
+	var origSize uint64
+	if diskSize, ok := artifact.State("diskSize").(string); ok && diskSize != "" {
+		origSize = sizeInMegabytes(diskSize)
+	}
+	if format == "" || origSize == 0 {
+		var imageFormat string
+		var imageSize uint64
+		imageFormat, imageSize, err = diskImageInfo(diskPath)
+		if err != nil {
+			return
+		}
+		if format == "" {
+			format = imageFormat
+		}
+		if origSize == 0 {
+			origSize = imageSize
+		}
+	}
+
+	// vagrant-libvirt only supports qcow2 boxes, so raw images are
+	// converted instead of being copied.
+	dstPath := filepath.Join(dir, "box.img")
+	switch format {
+	case "qcow2":
+		// Copy the disk image into the temporary directory (as box.img)
+		ui.Message(fmt.Sprintf("Copying from artifact: %s", diskPath))
+		if err = CopyContents(dstPath, diskPath); err != nil {
+			return
 		}
+	case "raw":
+		ui.Message(fmt.Sprintf("Converting raw disk image to qcow2: %s", diskPath))
+		if err = convertToQcow2(dstPath, diskPath); err != nil {
+			return
+		}
+		format = "qcow2"
+	default:
+		return "", nil, fmt.Errorf(
+			"Unsupported disk format %q for libvirt boxes, expected qcow2 or raw", format)
 	}
 
-	format := artifact.State("diskType").(string)
-	origSize := sizeInMegabytes(artifact.State("diskSize").(string))
 	size := origSize / 1024 // In MB, want GB
 	if origSize%1024 > 0 {
 		// Make sure we don't make the size smaller
 		size++
 	}
-	domainType := artifact.State("domainType").(string)
+
+	domainType, _ := artifact.State("domainType").(string)
+	if domainType == "" {
+		domainType = "kvm"
+	}
 
 	// Convert domain type to libvirt driver
 	var driver string
@@ -104,14 +153,90 @@ func (p *LibVirtProvider) Process(ui packersdk.Ui, artifact packersdk.Artifact,
 		"virtual_size": size,
 	}
 
-	vagrantfile = fmt.Sprintf(libvirtVagrantfile, driver)
+	var storagePool string
+	if p.StoragePool != "" {
+		storagePool = fmt.Sprintf("\n    libvirt.storage_pool_name = \"%s\"", p.StoragePool)
+	}
+
+	vagrantfile = fmt.Sprintf(libvirtVagrantfile, driver, storagePool)
 	return
 }
 
+// libvirtDiskPath returns the disk image of the artifact. The QEMU builder
+// names its disk in the artifact state; otherwise the only file of the
+// artifact, or the first one that looks like a disk image, is used.
+func libvirtDiskPath(files []string, diskName string) (string, error) {
+	if diskName != "" {
+		for _, path := range files {
+			if strings.HasSuffix(path, "/"+diskName) {
+				return path, nil
+			}
+		}
+		return "", fmt.Errorf("Disk %s not found in artifact", diskName)
+	}
+
+	if len(files) == 1 {
+		return files[0], nil
+	}
+	for _, path := range files {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".qcow2", ".img", ".raw":
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("No disk image found in artifact")
+}
+
+var qcow2Magic = []byte{'Q', 'F', 'I', 0xfb}
+
+// diskImageInfo returns the format and the virtual size in megabytes of the
+// disk image at path. Images that aren't qcow2 are considered raw.
+func diskImageInfo(path string) (format string, size uint64, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+
+	// The virtual size of a qcow2 image is stored at offset 24 of its header
+	header := make([]byte, 32)
+	_, err = io.ReadFull(f, header)
+	if err == nil && bytes.Equal(header[:4], qcow2Magic) {
+		return "qcow2", megabytesCeil(binary.BigEndian.Uint64(header[24:32])), nil
+	}
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", 0, err
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		return "", 0, err
+	}
+	log.Printf("Disk image %s is not qcow2, assuming raw", path)
+	return "raw", megabytesCeil(uint64(fi.Size())), nil
+}
+
+// convertToQcow2 converts the raw disk image at src to a qcow2 image at dst
+// with qemu-img.
+func convertToQcow2(dst, src string) error {
+	if _, err := exec.LookPath("qemu-img"); err != nil {
+		return fmt.Errorf("qemu-img is required to create libvirt boxes from raw disk images: %s", err)
+	}
+	out, err := exec.Command("qemu-img", "convert", "-f", "raw", "-O", "qcow2", src, dst).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("Failed to convert %s to qcow2: %s: %s", src, err, out)
+	}
+	return nil
+}
+
+func megabytesCeil(size uint64) uint64 {
+	return (size + 1024*1024 - 1) / (1024 * 1024)
+}
+
 var libvirtVagrantfile = `
 Vagrant.configure("2") do |config|
   config.vm.provider :libvirt do |libvirt|
-    libvirt.driver = "%s"
+    libvirt.driver = "%s"%s
   end
 end
 `
@@ -1,10 +1,206 @@
 package vagrant
 
 import (
+	"encoding/binary"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	packersdk "github.com/hashicorp/packer-plugin-sdk/packer"
 )
 
+func TestLibVirtProvider_impl(t *testing.T) {
+	var _ Provider = new(LibVirtProvider)
+}
+
+func writeQcow2Header(t *testing.T, path string, virtualSize uint64) {
+	header := make([]byte, 512)
+	copy(header, qcow2Magic)
+	binary.BigEndian.PutUint64(header[24:32], virtualSize)
+	if err := ioutil.WriteFile(path, header, 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestLibVirtProvider_QemuArtifact(t *testing.T) {
+	src, err := ioutil.TempDir("", "packer")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(src)
+	dir, err := ioutil.TempDir("", "packer")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	disk := filepath.Join(src, "packer-qemu")
+	if err := ioutil.WriteFile(disk, []byte("disk"), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	artifact := &packersdk.MockArtifact{
+		FilesValue: []string{disk},
+		StateValues: map[string]interface{}{
+			"diskName":   "packer-qemu",
+			"diskType":   "qcow2",
+			"diskSize":   "40960M",
+			"domainType": "tcg",
+		},
+	}
+
+	p := new(LibVirtProvider)
+	vagrantfile, metadata, err := p.Process(testUi(), artifact, dir)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if metadata["format"] != "qcow2" || metadata["virtual_size"] != uint64(40) {
+		t.Fatalf("bad metadata: %#v", metadata)
+	}
+	if !strings.Contains(vagrantfile, `libvirt.driver = "qemu"`) {
+		t.Fatalf("bad vagrantfile: %s", vagrantfile)
+	}
+	if strings.Contains(vagrantfile, "storage_pool_name") {
+		t.Fatalf("storage pool should not be set: %s", vagrantfile)
+	}
+}
+
+func TestLibVirtProvider_ArtificeArtifact(t *testing.T) {
+	src, err := ioutil.TempDir("", "packer")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(src)
+	dir, err := ioutil.TempDir("", "packer")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	disk := filepath.Join(src, "disk.qcow2")
+	writeQcow2Header(t, disk, 10*1024*1024*1024+1)
+	artifact := &packersdk.MockArtifact{
+		FilesValue: []string{filepath.Join(src, "disk.ovf"), disk},
+	}
+
+	p := &LibVirtProvider{StoragePool: "images"}
+	vagrantfile, metadata, err := p.Process(testUi(), artifact, dir)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// 10GiB and one byte must not be rounded down
+	if metadata["format"] != "qcow2" || metadata["virtual_size"] != uint64(11) {
+		t.Fatalf("bad metadata: %#v", metadata)
+	}
+	if _, err := json.Marshal(metadata); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for _, expected := range []string{
+		`libvirt.driver = "kvm"`,
+		`libvirt.storage_pool_name = "images"`,
+	} {
+		if !strings.Contains(vagrantfile, expected) {
+			t.Fatalf("expected %q in vagrantfile: %s", expected, vagrantfile)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "box.img")); err != nil {
+		t.Fatalf("box.img not copied: %s", err)
+	}
+}
+
+func TestLibVirtProvider_MissingDisk(t *testing.T) {
+	artifact := &packersdk.MockArtifact{
+		FilesValue: []string{"/tmp/disk.ovf", "/tmp/disk.vmdk"},
+	}
+
+	p := new(LibVirtProvider)
+	if _, _, err := p.Process(testUi(), artifact, "foo"); err == nil {
+		t.Fatal("should have error")
+	}
+}
+
+func TestLibVirtProvider_RawArtifact(t *testing.T) {
+	src, err := ioutil.TempDir("", "packer")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(src)
+	dir, err := ioutil.TempDir("", "packer")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	disk := filepath.Join(src, "disk.raw")
+	if err := ioutil.WriteFile(disk, make([]byte, 1024*1024), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	artifact := &packersdk.MockArtifact{
+		FilesValue: []string{disk},
+	}
+
+	p := new(LibVirtProvider)
+	_, metadata, err := p.Process(testUi(), artifact, dir)
+	if _, lookErr := exec.LookPath("qemu-img"); lookErr != nil {
+		if err == nil || !strings.Contains(err.Error(), "qemu-img is required") {
+			t.Fatalf("expected qemu-img to be required, got %v", err)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if metadata["format"] != "qcow2" {
+		t.Fatalf("bad metadata: %#v", metadata)
+	}
+	format, _, err := diskImageInfo(filepath.Join(dir, "box.img"))
+	if err != nil || format != "qcow2" {
+		t.Fatalf("box.img should be qcow2, got %q: %v", format, err)
+	}
+}
+
+func TestLibVirtProvider_UnsupportedFormat(t *testing.T) {
+	artifact := &packersdk.MockArtifact{
+		FilesValue: []string{"/tmp/packer-qemu"},
+		StateValues: map[string]interface{}{
+			"diskName": "packer-qemu",
+			"diskType": "vmdk",
+			"diskSize": "40960M",
+		},
+	}
+
+	p := new(LibVirtProvider)
+	_, _, err := p.Process(testUi(), artifact, "foo")
+	if err == nil || !strings.Contains(err.Error(), `Unsupported disk format "vmdk"`) {
+		t.Fatalf("expected an unsupported format error, got %v", err)
+	}
+}
+
+func TestDiskImageInfo_Raw(t *testing.T) {
+	f, err := ioutil.TempFile("", "packer")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.Remove(f.Name())
+	if err := f.Truncate(3*1024*1024 + 5); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	f.Close()
+
+	format, size, err := diskImageInfo(f.Name())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if format != "raw" || size != 4 {
+		t.Fatalf("bad: %s %d", format, size)
+	}
+}
+
 func assertSizeInMegabytes(t *testing.T, size string, expected uint64) {
 	actual := sizeInMegabytes(size)
 	if actual != expected {
@@ -66,9 +66,11 @@ type Config struct {
 	Include                      []string `mapstructure:"include"`
 	OutputPath                   string   `mapstructure:"output"`
 	Override                     map[string]interface{}
-	VagrantfileTemplate          string `mapstructure:"vagrantfile_template"`
-	VagrantfileTemplateGenerated bool   `mapstructure:"vagrantfile_template_generated"`
-	ProviderOverride             string `mapstructure:"provider_override"`
+	VagrantfileTemplate          string            `mapstructure:"vagrantfile_template"`
+	VagrantfileTemplateGenerated bool              `mapstructure:"vagrantfile_template_generated"`
+	ProviderOverride             string            `mapstructure:"provider_override"`
+	BoxMetadata                  map[string]string `mapstructure:"box_metadata"`
+	LibvirtStoragePool           string            `mapstructure:"libvirt_storage_pool"`
 
 	ctx interpolate.Context
 }
@@ -110,6 +112,10 @@ func (p *PostProcessor) PostProcessProvider(name string, provider Provider, ui p
 		return nil, false, err
 	}
 
+	if libvirt, ok := provider.(*LibVirtProvider); ok {
+		libvirt.StoragePool = config.LibvirtStoragePool
+	}
+
 	err = CreateDummyBox(ui, config.CompressionLevel)
 	if err != nil {
 		return nil, false, err
@@ -161,6 +167,16 @@ func (p *PostProcessor) PostProcessProvider(name string, provider Provider, ui p
 		return nil, false, err
 	}
 
+	// Add the custom metadata, the keys set by the provider take precedence
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+	for k, v := range config.BoxMetadata {
+		if _, ok := metadata[k]; !ok {
+			metadata[k] = v
+		}
+	}
+
 	// Write the metadata we got
 	if err := WriteMetadata(dir, metadata); err != nil {
 		return nil, false, err
@@ -280,6 +296,11 @@ func (p *PostProcessor) configureSingle(c *Config, raws ...interface{}) error {
 		}
 	}
 
+	if _, ok := c.BoxMetadata["provider"]; ok {
+		errs = packersdk.MultiErrorAppend(errs, fmt.Errorf(
+			"box_metadata can't set the provider, use provider_override instead"))
+	}
+
 	if errs != nil && len(errs.Errors) > 0 {
 		return errs
 	}
@@ -25,6 +25,8 @@ type FlatConfig struct {
 	VagrantfileTemplate          *string                `mapstructure:"vagrantfile_template" cty:"vagrantfile_template" hcl:"vagrantfile_template"`
 	VagrantfileTemplateGenerated *bool                  `mapstructure:"vagrantfile_template_generated" cty:"vagrantfile_template_generated" hcl:"vagrantfile_template_generated"`
 	ProviderOverride             *string                `mapstructure:"provider_override" cty:"provider_override" hcl:"provider_override"`
+	BoxMetadata                  map[string]string      `mapstructure:"box_metadata" cty:"box_metadata" hcl:"box_metadata"`
+	LibvirtStoragePool           *string                `mapstructure:"libvirt_storage_pool" cty:"libvirt_storage_pool" hcl:"libvirt_storage_pool"`
 }
 
 // FlatMapstructure returns a new FlatConfig.
@@ -54,6 +56,8 @@ func (*FlatConfig) HCL2Spec() map[string]hcldec.Spec {
 		"vagrantfile_template":           &hcldec.AttrSpec{Name: "vagrantfile_template", Type: cty.String, Required: false},
 		"vagrantfile_template_generated": &hcldec.AttrSpec{Name: "vagrantfile_template_generated", Type: cty.Bool, Required: false},
 		"provider_override":              &hcldec.AttrSpec{Name: "provider_override", Type: cty.String, Required: false},
+		"box_metadata":                   &hcldec.AttrSpec{Name: "box_metadata", Type: cty.Map(cty.String), Required: false},
+		"libvirt_storage_pool":           &hcldec.AttrSpec{Name: "libvirt_storage_pool", Type: cty.String, Required: false},
 	}
 	return s
 }
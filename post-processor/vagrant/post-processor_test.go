@@ -182,6 +182,25 @@ func TestPostProcessorPrepare_ProviderOverrideExists(t *testing.T) {
 	}
 }
 
+func TestPostProcessorPrepare_boxMetadata(t *testing.T) {
+	c := testConfig()
+	c["box_metadata"] = map[string]string{"provider": "aws"}
+
+	var p PostProcessor
+	if err := p.Configure(c); err == nil {
+		t.Fatal("should have errored since the provider can't be set")
+	}
+
+	c = testConfig()
+	c["box_metadata"] = map[string]string{"architecture": "amd64"}
+	if err := p.Configure(c); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if p.config.BoxMetadata["architecture"] != "amd64" {
+		t.Fatalf("bad: %#v", p.config.BoxMetadata)
+	}
+}
+
 func TestPostProcessorPostProcess_badId(t *testing.T) {
 	artifact := &packersdk.MockArtifact{
 		BuilderIdValue: "invalid.packer",